@@ -1,58 +1,276 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi"
 	// Import the PostgreSQL driver.
 	_ "github.com/lib/pq"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Embed the IANA time zone database, so client timezones resolve without system tzdata.
+	_ "time/tzdata"
 	"to-do-list-go/internal/config"
 	"to-do-list-go/internal/database"
 	"to-do-list-go/internal/delivery/handlers"
+	"to-do-list-go/internal/delivery/middleware"
+	"to-do-list-go/internal/events"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/metrics"
+	"to-do-list-go/internal/notifier"
+	"to-do-list-go/internal/outbound"
 	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/tracing"
 	"to-do-list-go/internal/validator"
 )
 
+// eventConsumerBuffer is the number of events the in-process consumer of the channel publisher can lag behind by.
+const eventConsumerBuffer = 100
+
 const (
-	errLoadingConfig  = "error loading config"
-	errConnectingToDB = "error connecting to db"
-	errValidatorInit  = "error validator init"
+	errLoadingConfig   = "error loading config"
+	errConnectingToDB  = "error connecting to db"
+	errValidatorInit   = "error validator init"
+	errServer          = "server error"
+	errShuttingDown    = "error shutting down server"
+	errStoppingWorkers = "error stopping background workers"
+	errClosingDB       = "error closing db connection"
+	errTracingSetup    = "error setting up tracing"
+	errTracingShutdown = "error flushing traces"
+	errRecurrences     = "error generating todo occurrences"
+	errReminders       = "error sending reminders"
+	errWebhooks        = "error delivering webhooks"
+	errOutbox          = "error relaying outbox events"
+	errEventListener   = "error listening for todo events"
 
 	successfulConfigLoad   = "config has been loaded successfully"
 	successfulDBConnection = "successful connection to db"
-	serverStart            = "server starting on port"
+	serverStart            = "server starting"
+	shutdownStart          = "shutting down server"
+	shutdownComplete       = "server has been shut down"
+	workerStart            = "background worker starting"
+	workerStop             = "background worker stopped"
+	emailRemindersDisabled = "SMTP_HOST is not set, email reminders will fail"
 )
 
-// Run initializes whole application.
+// Run initializes whole application and serves requests until SIGINT or SIGTERM,
+// then drains in-flight requests, stops background workers and closes the db connection.
 func Run() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	logLevel := new(slog.LevelVar)
+	slog.SetDefault(logger.New(os.Stdout, logLevel))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf(errLoadingConfig+": %s\n", err)
+		fatal(errLoadingConfig, err)
+	}
+	logLevel.Set(cfg.LogLevel)
+	slog.Info(successfulConfigLoad)
+
+	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", cfg.DbUser, cfg.DbPassword, cfg.DbHost, cfg.DbPort, cfg.DbName)
+	conn, err := sql.Open("postgres", dsn)
+	if err != nil {
+		fatal(errConnectingToDB, err)
 	}
-	log.Println(successfulConfigLoad)
+	slog.Info(successfulDBConnection)
 
-	conn, err := sql.Open("postgres", fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", cfg.DbUser, cfg.DbPassword, cfg.DbHost, cfg.DbPort, cfg.DbName))
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingExporter, cfg.OTLPEndpoint)
 	if err != nil {
-		log.Fatalf(errConnectingToDB+": %s\n", err)
+		fatal(errTracingSetup, err)
 	}
-	log.Println(successfulDBConnection)
-	repo := database.New(conn)
 
-	s := service.NewService(repo)
+	m := metrics.New()
+	m.RegisterDBStats(conn, cfg.DbName)
+	repo := tracing.NewRepository(metrics.NewRepository(database.New(conn), m))
+
+	s := service.NewService(repo, conn, service.AuthConfig{
+		Secret:          []byte(cfg.JWTSecret),
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	}, service.TodoConfig{
+		AutoCompleteParents: cfg.AutoCompleteParents,
+	})
+	s.Todos = tracing.NewTodos(metrics.NewTodos(s.Todos, m))
 
 	v, err := validator.InitValidator()
 	if err != nil {
-		log.Fatalf(errValidatorInit+": %s\n", err)
+		fatal(errValidatorInit, err)
 	}
 
 	r := chi.NewRouter()
+	r.Use(middleware.Tracing)
+	r.Use(middleware.RequestID)
+	r.Use(middleware.AccessLog)
+	r.Use(middleware.Metrics(m))
+	r.Use(middleware.Timeout(cfg.RequestTimeout, handlers.EventStreamPath))
 	h := handlers.NewHandler(s, v)
 	h.RegisterRoutes(r)
+	r.Method(http.MethodGet, "/metrics", m.Handler())
+
+	srv := &http.Server{
+		Addr:           ":" + cfg.Port,
+		Handler:        r,
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+	// Event streams never finish on their own, so they are ended once the server starts shutting down.
+	srv.RegisterOnShutdown(s.Events.Close)
+
+	var bg workers
+
+	// Every replica listens for the events committed by any of them, so event streams get them wherever they are connected.
+	bg.Go(ctx, "event listener", func(ctx context.Context) {
+		if err := database.Listen(ctx, dsn, database.TodoEventsChannel, s.Events.HandleNotification); err != nil && ctx.Err() == nil {
+			slog.Error(errEventListener, "error", err)
+		}
+	})
+
+	// The generator is disabled with a zero interval, occurrences are then only created when todos are completed.
+	if cfg.RecurrenceInterval > 0 {
+		bg.Every(ctx, "recurrence generator", cfg.RecurrenceInterval, func(ctx context.Context) {
+			if _, err := s.Recurrences.GenerateOccurrences(ctx, time.Now().Add(cfg.RecurrenceWindow)); err != nil && ctx.Err() == nil {
+				slog.Error(errRecurrences, "error", err)
+			}
+		})
+	}
+
+	if cfg.ReminderInterval > 0 {
+		scheduler := service.NewReminderScheduler(repo, newNotifiers(cfg))
+		bg.Every(ctx, "reminder scheduler", cfg.ReminderInterval, func(ctx context.Context) {
+			if _, err := scheduler.SendDueReminders(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				slog.Error(errReminders, "error", err)
+			}
+		})
+	}
+
+	// Events keep being queued while the dispatcher is disabled, they are delivered once it runs again.
+	if cfg.WebhookInterval > 0 {
+		dispatcher := service.NewWebhookDispatcher(repo, outbound.NewClient())
+		bg.Every(ctx, "webhook dispatcher", cfg.WebhookInterval, func(ctx context.Context) {
+			if _, err := dispatcher.DeliverWebhooks(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				slog.Error(errWebhooks, "error", err)
+			}
+		})
+	}
+
+	// Events keep being saved to the outbox while the relay is disabled, they are published once it runs again.
+	if cfg.OutboxInterval > 0 {
+		relay := service.NewOutboxRelay(repo, newEventPublisher(ctx, cfg, &bg))
+		bg.Every(ctx, "outbox relay", cfg.OutboxInterval, func(ctx context.Context) {
+			if _, err := relay.RelayEvents(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				slog.Error(errOutbox, "error", err)
+			}
+		})
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info(serverStart, "port", cfg.Port)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error(errServer, "error", err)
+	case <-ctx.Done():
+	}
+	stop()
+	slog.Info(shutdownStart)
+
+	// Keep serving while reporting not ready, so the orchestrator stops routing traffic before connections are closed.
+	s.Health.SetShuttingDown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error(errShuttingDown, "error", err)
+	}
+
+	if err := bg.Wait(shutdownCtx); err != nil {
+		slog.Error(errStoppingWorkers, "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error(errTracingShutdown, "error", err)
+	}
+
+	if err := conn.Close(); err != nil {
+		slog.Error(errClosingDB, "error", err)
+	}
+	slog.Info(shutdownComplete)
+}
+
+// newNotifiers creates the notifiers of the reminder channels. The email channel is only available when an SMTP server is configured.
+func newNotifiers(cfg *config.Config) map[string]notifier.Notifier {
+	notifiers := map[string]notifier.Notifier{
+		service.ReminderChannelWebhook: notifier.NewWebhook(outbound.NewClient()),
+		service.ReminderChannelLog:     notifier.NewLog(slog.Default()),
+	}
+
+	if cfg.SMTPHost == "" {
+		slog.Warn(emailRemindersDisabled)
+		return notifiers
+	}
+
+	notifiers[service.ReminderChannelEmail] = notifier.NewSMTP(notifier.SMTPConfig{
+		Addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+
+	return notifiers
+}
+
+// newEventPublisher creates the publisher of outbox events selected by EVENT_PUBLISHER.
+// No message broker client is bundled, so events end up in the application log either way:
+// the log publisher writes them there itself, and the only consumer of the channel publisher hands them to it.
+// When the consumer stops, it ends its subscription before writing the events left in its buffer, so no event the channel
+// accepted is lost, and the events published afterwards are rejected and stay in the outbox.
+func newEventPublisher(ctx context.Context, cfg *config.Config, bg *workers) events.EventPublisher {
+	log := events.NewLog(slog.Default())
+	if cfg.EventPublisher != config.EventPublisherChannel {
+		return log
+	}
+
+	channel := events.NewChannel()
+	received, unsubscribe := channel.Subscribe(eventConsumerBuffer)
+	bg.Go(ctx, "event consumer", func(ctx context.Context) {
+		// Received events are written even while stopping.
+		logCtx := context.WithoutCancel(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				unsubscribe()
+				for len(received) > 0 {
+					log.Publish(logCtx, <-received)
+				}
+				return
+			case event := <-received:
+				log.Publish(logCtx, event)
+			}
+		}
+	})
+
+	return channel
+}
 
-	log.Printf(serverStart+" %s", cfg.Port)
-	log.Fatal(http.ListenAndServe(":"+cfg.Port, r))
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
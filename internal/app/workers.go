@@ -0,0 +1,58 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// workers runs background jobs of the application and waits for them to stop on shutdown.
+type workers struct {
+	wg sync.WaitGroup
+}
+
+// Go runs job in its own goroutine. Jobs must return once ctx is done.
+func (w *workers) Go(ctx context.Context, name string, job func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		slog.Info(workerStart, "worker", name)
+		job(ctx)
+		slog.Info(workerStop, "worker", name)
+	}()
+}
+
+// Every runs job in its own goroutine once per interval until ctx is done.
+func (w *workers) Every(ctx context.Context, name string, interval time.Duration, job func(ctx context.Context)) {
+	w.Go(ctx, name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	})
+}
+
+// Wait blocks until all jobs have returned or ctx is done.
+func (w *workers) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
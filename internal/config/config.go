@@ -3,11 +3,44 @@ package config
 import (
 	"errors"
 	"github.com/joho/godotenv"
+	"log/slog"
 	"os"
+	"strconv"
+	"time"
 )
 
 const (
 	errUndefinedEnvParam = "parameter is undefined"
+	errInvalidEnvParam   = "parameter is invalid"
+
+	defaultRequestTimeout  = 10 * time.Second
+	defaultReadTimeout     = 15 * time.Second
+	defaultWriteTimeout    = 30 * time.Second
+	defaultIdleTimeout     = 60 * time.Second
+	defaultMaxHeaderBytes  = 1 << 20
+	defaultShutdownTimeout = 15 * time.Second
+	defaultShutdownDelay   = 5 * time.Second
+	defaultTracingExporter = "none"
+	defaultOTLPEndpoint    = "localhost:4318"
+	defaultLogLevel        = "info"
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	defaultRecurrenceInterval = time.Minute
+	defaultRecurrenceWindow   = 24 * time.Hour
+	defaultReminderInterval   = 30 * time.Second
+	defaultSMTPPort           = "587"
+	defaultWebhookInterval    = 10 * time.Second
+	defaultOutboxInterval     = time.Second
+	defaultEventPublisher     = EventPublisherLog
+)
+
+// Defines the values of EVENT_PUBLISHER.
+const (
+	// EventPublisherLog writes outbox events to the application log.
+	EventPublisherLog = "log"
+	// EventPublisherChannel hands outbox events to consumers in the same process.
+	EventPublisherChannel = "channel"
 )
 
 // Config is a struct that holds the configuration settings for the application.
@@ -18,6 +51,40 @@ type Config struct {
 	DbHost     string
 	DbPort     string
 	DbName     string
+	JWTSecret  string
+
+	RequestTimeout  time.Duration
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	MaxHeaderBytes  int
+	ShutdownTimeout time.Duration
+	ShutdownDelay   time.Duration
+
+	TracingExporter string
+	OTLPEndpoint    string
+
+	LogLevel slog.Level
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	AutoCompleteParents bool
+
+	RecurrenceInterval time.Duration
+	RecurrenceWindow   time.Duration
+
+	ReminderInterval time.Duration
+	SMTPHost         string
+	SMTPPort         string
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPFrom         string
+
+	WebhookInterval time.Duration
+
+	OutboxInterval time.Duration
+	EventPublisher string
 }
 
 // LoadConfig reads the environment variables from the .env file and loads them into a Config struct.
@@ -62,6 +129,107 @@ func LoadConfig() (*Config, error) {
 		return nil, errors.New("DB_NAME " + errUndefinedEnvParam)
 	}
 
+	jwtSecret := os.Getenv("JWT_SECRET")
+
+	if jwtSecret == "" {
+		return nil, errors.New("JWT_SECRET " + errUndefinedEnvParam)
+	}
+
+	requestTimeout, err := durationEnv("REQUEST_TIMEOUT", defaultRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	readTimeout, err := durationEnv("READ_TIMEOUT", defaultReadTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	writeTimeout, err := durationEnv("WRITE_TIMEOUT", defaultWriteTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	idleTimeout, err := durationEnv("IDLE_TIMEOUT", defaultIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	maxHeaderBytes, err := intEnv("MAX_HEADER_BYTES", defaultMaxHeaderBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownDelay, err := durationEnv("SHUTDOWN_DELAY", defaultShutdownDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	tracingExporter := stringEnv("TRACING_EXPORTER", defaultTracingExporter)
+	otlpEndpoint := stringEnv("OTLP_ENDPOINT", defaultOTLPEndpoint)
+
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(stringEnv("LOG_LEVEL", defaultLogLevel))); err != nil {
+		return nil, errors.New("LOG_LEVEL " + errInvalidEnvParam)
+	}
+
+	accessTokenTTL, err := durationEnv("ACCESS_TOKEN_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenTTL, err := durationEnv("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	autoCompleteParents, err := boolEnv("AUTO_COMPLETE_PARENTS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrenceInterval, err := durationEnv("RECURRENCE_INTERVAL", defaultRecurrenceInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrenceWindow, err := durationEnv("RECURRENCE_WINDOW", defaultRecurrenceWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	reminderInterval, err := durationEnv("REMINDER_INTERVAL", defaultReminderInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookInterval, err := durationEnv("WEBHOOK_INTERVAL", defaultWebhookInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxInterval, err := durationEnv("OUTBOX_INTERVAL", defaultOutboxInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	eventPublisher := stringEnv("EVENT_PUBLISHER", defaultEventPublisher)
+	if eventPublisher != EventPublisherLog && eventPublisher != EventPublisherChannel {
+		return nil, errors.New("EVENT_PUBLISHER " + errInvalidEnvParam)
+	}
+
+	smtpHost := os.Getenv("SMTP_HOST")
+	smtpFrom := os.Getenv("SMTP_FROM")
+
+	if smtpHost != "" && smtpFrom == "" {
+		return nil, errors.New("SMTP_FROM " + errUndefinedEnvParam)
+	}
+
 	return &Config{
 		Port:       port,
 		DbUser:     dbUser,
@@ -69,5 +237,93 @@ func LoadConfig() (*Config, error) {
 		DbHost:     dbHost,
 		DbPort:     dbPort,
 		DbName:     dbName,
+		JWTSecret:  jwtSecret,
+
+		RequestTimeout:  requestTimeout,
+		ReadTimeout:     readTimeout,
+		WriteTimeout:    writeTimeout,
+		IdleTimeout:     idleTimeout,
+		MaxHeaderBytes:  maxHeaderBytes,
+		ShutdownTimeout: shutdownTimeout,
+		ShutdownDelay:   shutdownDelay,
+
+		TracingExporter: tracingExporter,
+		OTLPEndpoint:    otlpEndpoint,
+
+		LogLevel: logLevel,
+
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+
+		AutoCompleteParents: autoCompleteParents,
+
+		RecurrenceInterval: recurrenceInterval,
+		RecurrenceWindow:   recurrenceWindow,
+
+		ReminderInterval: reminderInterval,
+		SMTPHost:         smtpHost,
+		SMTPPort:         stringEnv("SMTP_PORT", defaultSMTPPort),
+		SMTPUsername:     os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:         smtpFrom,
+
+		WebhookInterval: webhookInterval,
+
+		OutboxInterval: outboxInterval,
+		EventPublisher: eventPublisher,
 	}, nil
 }
+
+// stringEnv reads an optional parameter, falling back to defaultValue when it is unset.
+func stringEnv(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
+
+// durationEnv reads an optional non-negative duration parameter, falling back to defaultValue when it is unset.
+func durationEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, errors.New(name + " " + errInvalidEnvParam)
+	}
+
+	return duration, nil
+}
+
+// intEnv reads an optional positive integer parameter, falling back to defaultValue when it is unset.
+func intEnv(name string, defaultValue int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, errors.New(name + " " + errInvalidEnvParam)
+	}
+
+	return number, nil
+}
+
+// boolEnv reads an optional boolean parameter, falling back to defaultValue when it is unset.
+func boolEnv(name string, defaultValue bool) (bool, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New(name + " " + errInvalidEnvParam)
+	}
+
+	return flag, nil
+}
@@ -0,0 +1,241 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: dependencies.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const addTodoBlocker = `-- name: AddTodoBlocker :execrows
+INSERT INTO todo_dependencies (todo_id, blocker_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddTodoBlockerParams struct {
+	TodoID    int32
+	BlockerID int32
+}
+
+func (q *Queries) AddTodoBlocker(ctx context.Context, arg AddTodoBlockerParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addTodoBlocker, arg.TodoID, arg.BlockerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listProjectTodos = `-- name: ListProjectTodos :many
+SELECT id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone FROM todos
+WHERE project_id = $1::int AND owner_id = $2::int
+ORDER BY due_date, id
+`
+
+type ListProjectTodosParams struct {
+	ProjectID int32
+	OwnerID   int32
+}
+
+func (q *Queries) ListProjectTodos(ctx context.Context, arg ListProjectTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectTodos, arg.ProjectID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTodoBlockerChain = `-- name: ListTodoBlockerChain :many
+WITH RECURSIVE chain AS (
+    SELECT todos.id FROM todos
+    WHERE todos.id = $1::int AND todos.owner_id = $2::int
+    UNION
+    SELECT todo_dependencies.blocker_id FROM todo_dependencies
+    JOIN chain ON todo_dependencies.todo_id = chain.id
+)
+SELECT chain.id FROM chain
+`
+
+type ListTodoBlockerChainParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) ListTodoBlockerChain(ctx context.Context, arg ListTodoBlockerChainParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, listTodoBlockerChain, arg.ID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTodoBlockers = `-- name: ListTodoBlockers :many
+SELECT todo_dependencies.todo_id, todo_dependencies.blocker_id, todos.status AS blocker_status
+FROM todo_dependencies
+JOIN todos ON todos.id = todo_dependencies.blocker_id
+WHERE todo_dependencies.todo_id = ANY($1::int[])
+ORDER BY todo_dependencies.todo_id, todo_dependencies.blocker_id
+`
+
+type ListTodoBlockersRow struct {
+	TodoID        int32
+	BlockerID     int32
+	BlockerStatus TodoStatus
+}
+
+func (q *Queries) ListTodoBlockers(ctx context.Context, todoIds []int32) ([]ListTodoBlockersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTodoBlockers, pq.Array(todoIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTodoBlockersRow
+	for rows.Next() {
+		var i ListTodoBlockersRow
+		if err := rows.Scan(&i.TodoID, &i.BlockerID, &i.BlockerStatus); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockTodoDependencies = `-- name: LockTodoDependencies :exec
+SELECT pg_advisory_xact_lock(hashtext('todo_dependencies'), $1::int)
+`
+
+func (q *Queries) LockTodoDependencies(ctx context.Context, ownerID int32) error {
+	_, err := q.db.ExecContext(ctx, lockTodoDependencies, ownerID)
+	return err
+}
+
+const removeTodoBlocker = `-- name: RemoveTodoBlocker :one
+DELETE FROM todo_dependencies
+USING todos
+WHERE todo_dependencies.todo_id = $1 AND todo_dependencies.blocker_id = $2
+  AND todos.id = todo_dependencies.todo_id AND todos.owner_id = $3::int
+RETURNING todo_dependencies.todo_id, todo_dependencies.blocker_id, todo_dependencies.created_at
+`
+
+type RemoveTodoBlockerParams struct {
+	TodoID    int32
+	BlockerID int32
+	OwnerID   int32
+}
+
+func (q *Queries) RemoveTodoBlocker(ctx context.Context, arg RemoveTodoBlockerParams) (TodoDependency, error) {
+	row := q.db.QueryRowContext(ctx, removeTodoBlocker, arg.TodoID, arg.BlockerID, arg.OwnerID)
+	var i TodoDependency
+	err := row.Scan(&i.TodoID, &i.BlockerID, &i.CreatedAt)
+	return i, err
+}
+
+const touchBlockedTodos = `-- name: TouchBlockedTodos :many
+UPDATE todos
+SET updated_at = NOW(), version = version + 1
+FROM todo_dependencies
+WHERE todo_dependencies.blocker_id = $1::int AND todos.id = todo_dependencies.todo_id AND todos.owner_id = $2::int
+RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
+`
+
+type TouchBlockedTodosParams struct {
+	BlockerID int32
+	OwnerID   int32
+}
+
+func (q *Queries) TouchBlockedTodos(ctx context.Context, arg TouchBlockedTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, touchBlockedTodos, arg.BlockerID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"time"
+)
+
+// TodoEventsChannel is the channel the IDs of the events saved to the outbox are announced on once they are committed.
+const TodoEventsChannel = "todo_events"
+
+const (
+	listenerMinReconnect = time.Second
+	listenerMaxReconnect = time.Minute
+	// listenerPingInterval is how often an idle connection is checked, so a dead one is noticed and re-established.
+	listenerPingInterval = time.Minute
+)
+
+// Listen calls handle with the payload of every notification sent on channel of the database at dsn until ctx is done.
+// The connection is re-established whenever it is lost. Notifications sent meanwhile are lost,
+// so handle is then called with an empty payload.
+func Listen(ctx context.Context, dsn, channel string, handle func(ctx context.Context, payload string)) error {
+	l := pq.NewListener(dsn, listenerMinReconnect, listenerMaxReconnect, nil)
+	// Closing the listener also unblocks Listen, which waits until the connection is established.
+	stop := context.AfterFunc(ctx, func() { l.Close() })
+	defer func() {
+		if stop() {
+			l.Close()
+		}
+	}()
+
+	if err := l.Listen(channel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("listen on %s: %w", channel, err)
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-l.Notify:
+			if !ok {
+				return nil
+			}
+			if n == nil {
+				handle(ctx, "")
+				continue
+			}
+			handle(ctx, n.Extra)
+		case <-ticker.C:
+			// A failed ping closes the connection, the listener then reconnects on its own.
+			l.Ping()
+		}
+	}
+}
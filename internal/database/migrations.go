@@ -0,0 +1,16 @@
+package database
+
+import "context"
+
+// goose_db_version is created by goose rather than by migrations, so sqlc doesn't know its schema.
+const migrationVersion = `-- name: MigrationVersion :one
+SELECT COALESCE(MAX(version_id), 0)::bigint FROM goose_db_version WHERE is_applied
+`
+
+// MigrationVersion returns the version of the latest applied migration.
+func (q *Queries) MigrationVersion(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, migrationVersion)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
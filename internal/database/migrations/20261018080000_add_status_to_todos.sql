@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE todo_status AS ENUM ('open', 'in_progress', 'done', 'cancelled');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN status todo_status DEFAULT 'open' NOT NULL,
+    ADD COLUMN completed_at TIMESTAMPTZ,
+    ADD CONSTRAINT todos_completed_at_check CHECK ((status = 'done') = (completed_at IS NOT NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos
+    DROP CONSTRAINT todos_completed_at_check,
+    DROP COLUMN completed_at,
+    DROP COLUMN status;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TYPE todo_status;
+-- +goose StatementEnd
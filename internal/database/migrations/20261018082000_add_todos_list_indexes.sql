@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX todos_due_date_idx ON todos (due_date, id);
+CREATE INDEX todos_created_at_idx ON todos (created_at, id);
+CREATE INDEX todos_updated_at_idx ON todos (updated_at, id);
+CREATE INDEX todos_status_idx ON todos (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX todos_status_idx;
+DROP INDEX todos_updated_at_idx;
+DROP INDEX todos_created_at_idx;
+DROP INDEX todos_due_date_idx;
+-- +goose StatementEnd
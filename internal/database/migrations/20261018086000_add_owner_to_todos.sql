@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN owner_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_owner_id_idx ON todos (owner_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX todos_owner_id_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos
+    DROP COLUMN owner_id;
+-- +goose StatementEnd
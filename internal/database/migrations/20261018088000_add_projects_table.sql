@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE projects (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    archived BOOLEAN DEFAULT FALSE NOT NULL,
+    sort_order INTEGER DEFAULT 0 NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX projects_owner_id_idx ON projects (owner_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos ADD COLUMN project_id INTEGER REFERENCES projects (id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_project_id_idx ON todos (project_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX todos_project_id_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos DROP COLUMN project_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE projects;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN parent_id INTEGER REFERENCES todos (id) ON DELETE CASCADE,
+    ADD CONSTRAINT todos_parent_id_check CHECK (parent_id <> id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_parent_id_idx ON todos (parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos DROP COLUMN parent_id;
+-- +goose StatementEnd
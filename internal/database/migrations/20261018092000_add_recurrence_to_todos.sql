@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN recurrence TEXT,
+    ADD COLUMN series_id INTEGER,
+    ADD COLUMN recurred BOOLEAN DEFAULT FALSE NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_series_id_idx ON todos (series_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_recurring_due_date_idx ON todos (due_date) WHERE recurrence IS NOT NULL AND NOT recurred;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos
+    DROP COLUMN recurred,
+    DROP COLUMN series_id,
+    DROP COLUMN recurrence;
+-- +goose StatementEnd
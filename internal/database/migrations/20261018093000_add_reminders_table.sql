@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE reminder_channel AS ENUM ('email', 'webhook', 'log');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE reminders (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    remind_at TIMESTAMPTZ,
+    minutes_before INTEGER CHECK (minutes_before >= 0),
+    channel reminder_channel NOT NULL,
+    target TEXT,
+    attempts INTEGER DEFAULT 0 NOT NULL,
+    last_error TEXT,
+    locked_until TIMESTAMPTZ,
+    sent_at TIMESTAMPTZ,
+    failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    CONSTRAINT reminders_time_check CHECK ((remind_at IS NULL) <> (minutes_before IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX reminders_todo_id_idx ON reminders (todo_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX reminders_pending_idx ON reminders (id) WHERE sent_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE reminders;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TYPE reminder_channel;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX outbox_user_id_idx ON outbox (user_id, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION notify_todo_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('todo_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER outbox_notify
+AFTER INSERT ON outbox
+FOR EACH ROW EXECUTE FUNCTION notify_todo_event();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER outbox_notify ON outbox;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION notify_todo_event();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX outbox_user_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX todos_search_vector_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos
+    DROP COLUMN search_vector;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_search_idx ON todos USING GIN (
+    (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', description), 'B'))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX todos_search_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', description), 'B')
+    ) STORED NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX todos_search_vector_idx ON todos USING GIN (search_vector);
+-- +goose StatementEnd
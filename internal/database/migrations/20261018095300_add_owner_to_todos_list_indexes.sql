@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX todos_due_date_idx;
+DROP INDEX todos_created_at_idx;
+DROP INDEX todos_updated_at_idx;
+CREATE INDEX todos_owner_due_date_idx ON todos (owner_id, due_date, id);
+CREATE INDEX todos_owner_created_at_idx ON todos (owner_id, created_at, id);
+CREATE INDEX todos_owner_updated_at_idx ON todos (owner_id, updated_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX todos_owner_updated_at_idx;
+DROP INDEX todos_owner_created_at_idx;
+DROP INDEX todos_owner_due_date_idx;
+CREATE INDEX todos_due_date_idx ON todos (due_date, id);
+CREATE INDEX todos_created_at_idx ON todos (created_at, id);
+CREATE INDEX todos_updated_at_idx ON todos (updated_at, id);
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Todos created before users were added have no owner. They are not dropped: the migration stops until
+-- an operator assigns them to a user, e.g. UPDATE todos SET owner_id = <user id> WHERE owner_id IS NULL.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    ownerless bigint;
+BEGIN
+    SELECT count(*) INTO ownerless FROM todos WHERE owner_id IS NULL;
+    IF ownerless > 0 THEN
+        RAISE EXCEPTION '% todos have no owner', ownerless
+            USING HINT = 'Assign them to a user with UPDATE todos SET owner_id = <user id> WHERE owner_id IS NULL and run the migrations again.';
+    END IF;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE todos
+    ALTER COLUMN owner_id SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE todos
+    ALTER COLUMN owner_id DROP NOT NULL;
+-- +goose StatementEnd
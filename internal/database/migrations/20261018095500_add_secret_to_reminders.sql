@@ -0,0 +1,19 @@
+-- +goose Up
+-- Webhook reminders are signed like webhook deliveries. Reminders created before have no secret and are sent unsigned.
+-- +goose StatementBegin
+ALTER TABLE reminders
+    ADD COLUMN secret TEXT;
+-- +goose StatementEnd
+
+-- Email reminders are sent to the email of the todo owner only.
+-- +goose StatementBegin
+UPDATE reminders
+SET target = NULL
+WHERE channel = 'email';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reminders
+    DROP COLUMN secret;
+-- +goose StatementEnd
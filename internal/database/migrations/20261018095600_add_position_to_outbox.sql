@@ -0,0 +1,74 @@
+-- +goose Up
+-- IDs of outbox events are allocated on insert, so an event may be committed after events with greater IDs.
+-- Positions are allocated on commit instead, under a lock per user, so the positions of the events of a user
+-- grow in the order they are committed and event streams can be resumed after a position without missing events.
+-- +goose StatementBegin
+CREATE SEQUENCE outbox_position_seq;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE outbox
+    ADD COLUMN position BIGINT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE outbox
+SET position = id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+SELECT setval('outbox_position_seq', COALESCE(MAX(id), 0) + 1, false) FROM outbox;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX outbox_user_id_position_idx ON outbox (user_id, position);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX outbox_user_id_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION set_outbox_position() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('outbox_position'), NEW.user_id);
+    UPDATE outbox SET position = nextval('outbox_position_seq') WHERE id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- The trigger runs on commit, after all statements of the transaction, so waiting for the lock of the user
+-- can't deadlock with the row locks held by the transactions.
+-- +goose StatementBegin
+CREATE CONSTRAINT TRIGGER outbox_position
+AFTER INSERT ON outbox
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION set_outbox_position();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER outbox_position ON outbox;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP FUNCTION set_outbox_position();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX outbox_user_id_idx ON outbox (user_id, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX outbox_user_id_position_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE outbox
+    DROP COLUMN position;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP SEQUENCE outbox_position_seq;
+-- +goose StatementEnd
@@ -6,7 +6,9 @@ package mock_database
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 	database "to-do-list-go/internal/database"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +37,171 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddTodoBlocker mocks base method.
+func (m *MockRepository) AddTodoBlocker(ctx context.Context, arg database.AddTodoBlockerParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTodoBlocker", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTodoBlocker indicates an expected call of AddTodoBlocker.
+func (mr *MockRepositoryMockRecorder) AddTodoBlocker(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTodoBlocker", reflect.TypeOf((*MockRepository)(nil).AddTodoBlocker), ctx, arg)
+}
+
+// ClaimDueReminders mocks base method.
+func (m *MockRepository) ClaimDueReminders(ctx context.Context, arg database.ClaimDueRemindersParams) ([]database.ClaimDueRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueReminders", ctx, arg)
+	ret0, _ := ret[0].([]database.ClaimDueRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueReminders indicates an expected call of ClaimDueReminders.
+func (mr *MockRepositoryMockRecorder) ClaimDueReminders(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueReminders", reflect.TypeOf((*MockRepository)(nil).ClaimDueReminders), ctx, arg)
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockRepository) ClaimOutboxEvents(ctx context.Context, rowLimit int32) ([]database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", ctx, rowLimit)
+	ret0, _ := ret[0].([]database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockRepositoryMockRecorder) ClaimOutboxEvents(ctx, rowLimit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockRepository)(nil).ClaimOutboxEvents), ctx, rowLimit)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockRepository) ClaimWebhookDeliveries(ctx context.Context, arg database.ClaimWebhookDeliveriesParams) ([]database.ClaimWebhookDeliveriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]database.ClaimWebhookDeliveriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimWebhookDeliveries), ctx, arg)
+}
+
+// CompleteParentTodo mocks base method.
+func (m *MockRepository) CompleteParentTodo(ctx context.Context, arg database.CompleteParentTodoParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteParentTodo", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteParentTodo indicates an expected call of CompleteParentTodo.
+func (mr *MockRepositoryMockRecorder) CompleteParentTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteParentTodo", reflect.TypeOf((*MockRepository)(nil).CompleteParentTodo), ctx, arg)
+}
+
+// ConsumeRefreshToken mocks base method.
+func (m *MockRepository) ConsumeRefreshToken(ctx context.Context, tokenHash string) (database.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(database.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRefreshToken indicates an expected call of ConsumeRefreshToken.
+func (mr *MockRepositoryMockRecorder) ConsumeRefreshToken(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRefreshToken", reflect.TypeOf((*MockRepository)(nil).ConsumeRefreshToken), ctx, tokenHash)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockRepository) CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockRepositoryMockRecorder) CreateAPIKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockRepository)(nil).CreateAPIKey), ctx, arg)
+}
+
+// CreateNextOccurrence mocks base method.
+func (m *MockRepository) CreateNextOccurrence(ctx context.Context, arg database.CreateNextOccurrenceParams) (database.CreateNextOccurrenceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNextOccurrence", ctx, arg)
+	ret0, _ := ret[0].(database.CreateNextOccurrenceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNextOccurrence indicates an expected call of CreateNextOccurrence.
+func (mr *MockRepositoryMockRecorder) CreateNextOccurrence(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNextOccurrence", reflect.TypeOf((*MockRepository)(nil).CreateNextOccurrence), ctx, arg)
+}
+
+// CreateProject mocks base method.
+func (m *MockRepository) CreateProject(ctx context.Context, arg database.CreateProjectParams) (database.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProject", ctx, arg)
+	ret0, _ := ret[0].(database.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockRepositoryMockRecorder) CreateProject(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockRepository)(nil).CreateProject), ctx, arg)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockRepository) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, arg)
+	ret0, _ := ret[0].(database.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockRepositoryMockRecorder) CreateRefreshToken(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockRepository)(nil).CreateRefreshToken), ctx, arg)
+}
+
+// CreateReminder mocks base method.
+func (m *MockRepository) CreateReminder(ctx context.Context, arg database.CreateReminderParams) (database.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReminder", ctx, arg)
+	ret0, _ := ret[0].(database.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReminder indicates an expected call of CreateReminder.
+func (mr *MockRepositoryMockRecorder) CreateReminder(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReminder", reflect.TypeOf((*MockRepository)(nil).CreateReminder), ctx, arg)
+}
+
 // CreateTodo mocks base method.
 func (m *MockRepository) CreateTodo(ctx context.Context, arg database.CreateTodoParams) (database.Todo, error) {
 	m.ctrl.T.Helper()
@@ -50,62 +217,882 @@ func (mr *MockRepositoryMockRecorder) CreateTodo(ctx, arg interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTodo", reflect.TypeOf((*MockRepository)(nil).CreateTodo), ctx, arg)
 }
 
+// CreateUser mocks base method.
+func (m *MockRepository) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockRepositoryMockRecorder) CreateUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepository)(nil).CreateUser), ctx, arg)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockRepository) CreateWebhook(ctx context.Context, arg database.CreateWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockRepositoryMockRecorder) CreateWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockRepository)(nil).CreateWebhook), ctx, arg)
+}
+
+// DeleteProject mocks base method.
+func (m *MockRepository) DeleteProject(ctx context.Context, arg database.DeleteProjectParams) (database.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProject", ctx, arg)
+	ret0, _ := ret[0].(database.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockRepositoryMockRecorder) DeleteProject(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockRepository)(nil).DeleteProject), ctx, arg)
+}
+
+// DeleteProjectTodos mocks base method.
+func (m *MockRepository) DeleteProjectTodos(ctx context.Context, arg database.DeleteProjectTodosParams) ([]database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProjectTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProjectTodos indicates an expected call of DeleteProjectTodos.
+func (mr *MockRepositoryMockRecorder) DeleteProjectTodos(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProjectTodos", reflect.TypeOf((*MockRepository)(nil).DeleteProjectTodos), ctx, arg)
+}
+
+// DeleteReminder mocks base method.
+func (m *MockRepository) DeleteReminder(ctx context.Context, arg database.DeleteReminderParams) (database.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReminder", ctx, arg)
+	ret0, _ := ret[0].(database.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReminder indicates an expected call of DeleteReminder.
+func (mr *MockRepositoryMockRecorder) DeleteReminder(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReminder", reflect.TypeOf((*MockRepository)(nil).DeleteReminder), ctx, arg)
+}
+
+// DeleteSentOutboxEvents mocks base method.
+func (m *MockRepository) DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSentOutboxEvents", ctx, sentBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSentOutboxEvents indicates an expected call of DeleteSentOutboxEvents.
+func (mr *MockRepositoryMockRecorder) DeleteSentOutboxEvents(ctx, sentBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSentOutboxEvents", reflect.TypeOf((*MockRepository)(nil).DeleteSentOutboxEvents), ctx, sentBefore)
+}
+
 // DeleteTodo mocks base method.
-func (m *MockRepository) DeleteTodo(ctx context.Context, id int32) (database.Todo, error) {
+func (m *MockRepository) DeleteTodo(ctx context.Context, arg database.DeleteTodoParams) ([]database.Todo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTodo", ctx, id)
-	ret0, _ := ret[0].(database.Todo)
+	ret := m.ctrl.Call(m, "DeleteTodo", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteTodo indicates an expected call of DeleteTodo.
-func (mr *MockRepositoryMockRecorder) DeleteTodo(ctx, id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) DeleteTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockRepository)(nil).DeleteTodo), ctx, arg)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockRepository) DeleteWebhook(ctx context.Context, arg database.DeleteWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockRepositoryMockRecorder) DeleteWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockRepository)(nil).DeleteWebhook), ctx, arg)
+}
+
+// EndTodoRecurrence mocks base method.
+func (m *MockRepository) EndTodoRecurrence(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndTodoRecurrence", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EndTodoRecurrence indicates an expected call of EndTodoRecurrence.
+func (mr *MockRepositoryMockRecorder) EndTodoRecurrence(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndTodoRecurrence", reflect.TypeOf((*MockRepository)(nil).EndTodoRecurrence), ctx, id)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockRepository) EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) EnqueueWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).EnqueueWebhookDeliveries), ctx, arg)
+}
+
+// GetLastTodoEventPosition mocks base method.
+func (m *MockRepository) GetLastTodoEventPosition(ctx context.Context, userID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastTodoEventPosition", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastTodoEventPosition indicates an expected call of GetLastTodoEventPosition.
+func (mr *MockRepositoryMockRecorder) GetLastTodoEventPosition(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTodoEventPosition", reflect.TypeOf((*MockRepository)(nil).GetLastTodoEventPosition), ctx, userID)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockRepository) GetOutboxEvent(ctx context.Context, id int64) (database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", ctx, id)
+	ret0, _ := ret[0].(database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockRepositoryMockRecorder) GetOutboxEvent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockRepository)(nil).GetOutboxEvent), ctx, id)
+}
+
+// GetProject mocks base method.
+func (m *MockRepository) GetProject(ctx context.Context, arg database.GetProjectParams) (database.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject", ctx, arg)
+	ret0, _ := ret[0].(database.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockRepositoryMockRecorder) GetProject(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTodo", reflect.TypeOf((*MockRepository)(nil).DeleteTodo), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockRepository)(nil).GetProject), ctx, arg)
 }
 
 // GetTodo mocks base method.
-func (m *MockRepository) GetTodo(ctx context.Context, id int32) (database.Todo, error) {
+func (m *MockRepository) GetTodo(ctx context.Context, arg database.GetTodoParams) (database.Todo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTodo", ctx, id)
+	ret := m.ctrl.Call(m, "GetTodo", ctx, arg)
 	ret0, _ := ret[0].(database.Todo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTodo indicates an expected call of GetTodo.
-func (mr *MockRepositoryMockRecorder) GetTodo(ctx, id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockRepository)(nil).GetTodo), ctx, arg)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockRepository) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockRepositoryMockRecorder) GetUserByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockRepository)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetWebhook mocks base method.
+func (m *MockRepository) GetWebhook(ctx context.Context, arg database.GetWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockRepositoryMockRecorder) GetWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockRepository)(nil).GetWebhook), ctx, arg)
+}
+
+// InTx mocks base method.
+func (m *MockRepository) InTx(ctx context.Context, fn func(database.Repository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTx indicates an expected call of InTx.
+func (mr *MockRepositoryMockRecorder) InTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockRepository)(nil).InTx), ctx, fn)
+}
+
+// InsertOutboxEvent mocks base method.
+func (m *MockRepository) InsertOutboxEvent(ctx context.Context, arg database.InsertOutboxEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOutboxEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOutboxEvent indicates an expected call of InsertOutboxEvent.
+func (mr *MockRepositoryMockRecorder) InsertOutboxEvent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOutboxEvent", reflect.TypeOf((*MockRepository)(nil).InsertOutboxEvent), ctx, arg)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockRepository) ListAPIKeys(ctx context.Context, userID int32) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx, userID)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockRepositoryMockRecorder) ListAPIKeys(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodo", reflect.TypeOf((*MockRepository)(nil).GetTodo), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockRepository)(nil).ListAPIKeys), ctx, userID)
 }
 
-// GetTodos mocks base method.
-func (m *MockRepository) GetTodos(ctx context.Context) ([]database.Todo, error) {
+// ListDueRecurringTodos mocks base method.
+func (m *MockRepository) ListDueRecurringTodos(ctx context.Context, arg database.ListDueRecurringTodosParams) ([]database.Todo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTodos", ctx)
+	ret := m.ctrl.Call(m, "ListDueRecurringTodos", ctx, arg)
 	ret0, _ := ret[0].([]database.Todo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTodos indicates an expected call of GetTodos.
-func (mr *MockRepositoryMockRecorder) GetTodos(ctx interface{}) *gomock.Call {
+// ListDueRecurringTodos indicates an expected call of ListDueRecurringTodos.
+func (mr *MockRepositoryMockRecorder) ListDueRecurringTodos(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTodos", reflect.TypeOf((*MockRepository)(nil).GetTodos), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueRecurringTodos", reflect.TypeOf((*MockRepository)(nil).ListDueRecurringTodos), ctx, arg)
 }
 
-// UpdateTodo mocks base method.
-func (m *MockRepository) UpdateTodo(ctx context.Context, arg database.UpdateTodoParams) (database.Todo, error) {
+// ListProjectTodos mocks base method.
+func (m *MockRepository) ListProjectTodos(ctx context.Context, arg database.ListProjectTodosParams) ([]database.Todo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTodo", ctx, arg)
-	ret0, _ := ret[0].(database.Todo)
+	ret := m.ctrl.Call(m, "ListProjectTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateTodo indicates an expected call of UpdateTodo.
-func (mr *MockRepositoryMockRecorder) UpdateTodo(ctx, arg interface{}) *gomock.Call {
+// ListProjectTodos indicates an expected call of ListProjectTodos.
+func (mr *MockRepositoryMockRecorder) ListProjectTodos(ctx, arg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockRepository)(nil).UpdateTodo), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectTodos", reflect.TypeOf((*MockRepository)(nil).ListProjectTodos), ctx, arg)
+}
+
+// ListProjects mocks base method.
+func (m *MockRepository) ListProjects(ctx context.Context, arg database.ListProjectsParams) ([]database.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjects", ctx, arg)
+	ret0, _ := ret[0].([]database.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockRepositoryMockRecorder) ListProjects(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockRepository)(nil).ListProjects), ctx, arg)
+}
+
+// ListReminders mocks base method.
+func (m *MockRepository) ListReminders(ctx context.Context, arg database.ListRemindersParams) ([]database.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReminders", ctx, arg)
+	ret0, _ := ret[0].([]database.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReminders indicates an expected call of ListReminders.
+func (mr *MockRepositoryMockRecorder) ListReminders(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReminders", reflect.TypeOf((*MockRepository)(nil).ListReminders), ctx, arg)
+}
+
+// ListSubtaskProgress mocks base method.
+func (m *MockRepository) ListSubtaskProgress(ctx context.Context, todoIDs []int32) ([]database.ListSubtaskProgressRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtaskProgress", ctx, todoIDs)
+	ret0, _ := ret[0].([]database.ListSubtaskProgressRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtaskProgress indicates an expected call of ListSubtaskProgress.
+func (mr *MockRepositoryMockRecorder) ListSubtaskProgress(ctx, todoIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtaskProgress", reflect.TypeOf((*MockRepository)(nil).ListSubtaskProgress), ctx, todoIDs)
+}
+
+// ListSubtaskTree mocks base method.
+func (m *MockRepository) ListSubtaskTree(ctx context.Context, arg database.ListSubtaskTreeParams) ([]database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtaskTree", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtaskTree indicates an expected call of ListSubtaskTree.
+func (mr *MockRepositoryMockRecorder) ListSubtaskTree(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtaskTree", reflect.TypeOf((*MockRepository)(nil).ListSubtaskTree), ctx, arg)
+}
+
+// ListTags mocks base method.
+func (m *MockRepository) ListTags(ctx context.Context, ownerID int32) ([]database.ListTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, ownerID)
+	ret0, _ := ret[0].([]database.ListTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockRepositoryMockRecorder) ListTags(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockRepository)(nil).ListTags), ctx, ownerID)
+}
+
+// ListTodoAncestors mocks base method.
+func (m *MockRepository) ListTodoAncestors(ctx context.Context, arg database.ListTodoAncestorsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoAncestors", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoAncestors indicates an expected call of ListTodoAncestors.
+func (mr *MockRepositoryMockRecorder) ListTodoAncestors(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoAncestors", reflect.TypeOf((*MockRepository)(nil).ListTodoAncestors), ctx, arg)
+}
+
+// ListTodoBlockerChain mocks base method.
+func (m *MockRepository) ListTodoBlockerChain(ctx context.Context, arg database.ListTodoBlockerChainParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoBlockerChain", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoBlockerChain indicates an expected call of ListTodoBlockerChain.
+func (mr *MockRepositoryMockRecorder) ListTodoBlockerChain(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoBlockerChain", reflect.TypeOf((*MockRepository)(nil).ListTodoBlockerChain), ctx, arg)
+}
+
+// ListTodoBlockers mocks base method.
+func (m *MockRepository) ListTodoBlockers(ctx context.Context, todoIDs []int32) ([]database.ListTodoBlockersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoBlockers", ctx, todoIDs)
+	ret0, _ := ret[0].([]database.ListTodoBlockersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoBlockers indicates an expected call of ListTodoBlockers.
+func (mr *MockRepositoryMockRecorder) ListTodoBlockers(ctx, todoIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoBlockers", reflect.TypeOf((*MockRepository)(nil).ListTodoBlockers), ctx, todoIDs)
+}
+
+// ListTodoEvents mocks base method.
+func (m *MockRepository) ListTodoEvents(ctx context.Context, arg database.ListTodoEventsParams) ([]database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoEvents", ctx, arg)
+	ret0, _ := ret[0].([]database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoEvents indicates an expected call of ListTodoEvents.
+func (mr *MockRepositoryMockRecorder) ListTodoEvents(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoEvents", reflect.TypeOf((*MockRepository)(nil).ListTodoEvents), ctx, arg)
+}
+
+// ListTodoTags mocks base method.
+func (m *MockRepository) ListTodoTags(ctx context.Context, todoIDs []int32) ([]database.ListTodoTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodoTags", ctx, todoIDs)
+	ret0, _ := ret[0].([]database.ListTodoTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodoTags indicates an expected call of ListTodoTags.
+func (mr *MockRepositoryMockRecorder) ListTodoTags(ctx, todoIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodoTags", reflect.TypeOf((*MockRepository)(nil).ListTodoTags), ctx, todoIDs)
+}
+
+// ListTodos mocks base method.
+func (m *MockRepository) ListTodos(ctx context.Context, arg database.ListTodosParams) ([]database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTodos indicates an expected call of ListTodos.
+func (mr *MockRepositoryMockRecorder) ListTodos(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTodos", reflect.TypeOf((*MockRepository)(nil).ListTodos), ctx, arg)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockRepository) ListWebhookDeliveries(ctx context.Context, arg database.ListWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ListWebhookDeliveries(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ListWebhookDeliveries), ctx, arg)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockRepository) ListWebhooks(ctx context.Context, userID int32) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockRepositoryMockRecorder) ListWebhooks(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockRepository)(nil).ListWebhooks), ctx, userID)
+}
+
+// LockTodoDependencies mocks base method.
+func (m *MockRepository) LockTodoDependencies(ctx context.Context, ownerID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTodoDependencies", ctx, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTodoDependencies indicates an expected call of LockTodoDependencies.
+func (mr *MockRepositoryMockRecorder) LockTodoDependencies(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTodoDependencies", reflect.TypeOf((*MockRepository)(nil).LockTodoDependencies), ctx, ownerID)
+}
+
+// MarkOutboxEventsSent mocks base method.
+func (m *MockRepository) MarkOutboxEventsSent(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventsSent", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventsSent indicates an expected call of MarkOutboxEventsSent.
+func (mr *MockRepositoryMockRecorder) MarkOutboxEventsSent(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventsSent", reflect.TypeOf((*MockRepository)(nil).MarkOutboxEventsSent), ctx, ids)
+}
+
+// MarkReminderSent mocks base method.
+func (m *MockRepository) MarkReminderSent(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminderSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReminderSent indicates an expected call of MarkReminderSent.
+func (mr *MockRepositoryMockRecorder) MarkReminderSent(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockRepository)(nil).MarkReminderSent), ctx, id)
+}
+
+// MigrationVersion mocks base method.
+func (m *MockRepository) MigrationVersion(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationVersion", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrationVersion indicates an expected call of MigrationVersion.
+func (mr *MockRepositoryMockRecorder) MigrationVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationVersion", reflect.TypeOf((*MockRepository)(nil).MigrationVersion), ctx)
+}
+
+// MoveProjectTodos mocks base method.
+func (m *MockRepository) MoveProjectTodos(ctx context.Context, arg database.MoveProjectTodosParams) ([]database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveProjectTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveProjectTodos indicates an expected call of MoveProjectTodos.
+func (mr *MockRepositoryMockRecorder) MoveProjectTodos(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveProjectTodos", reflect.TypeOf((*MockRepository)(nil).MoveProjectTodos), ctx, arg)
+}
+
+// MoveTodo mocks base method.
+func (m *MockRepository) MoveTodo(ctx context.Context, arg database.MoveTodoParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTodo", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTodo indicates an expected call of MoveTodo.
+func (mr *MockRepositoryMockRecorder) MoveTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTodo", reflect.TypeOf((*MockRepository)(nil).MoveTodo), ctx, arg)
+}
+
+// PatchTodo mocks base method.
+func (m *MockRepository) PatchTodo(ctx context.Context, arg database.PatchTodoParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTodo", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTodo indicates an expected call of PatchTodo.
+func (mr *MockRepositoryMockRecorder) PatchTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTodo", reflect.TypeOf((*MockRepository)(nil).PatchTodo), ctx, arg)
+}
+
+// RecordReminderFailure mocks base method.
+func (m *MockRepository) RecordReminderFailure(ctx context.Context, arg database.RecordReminderFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordReminderFailure", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordReminderFailure indicates an expected call of RecordReminderFailure.
+func (mr *MockRepositoryMockRecorder) RecordReminderFailure(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordReminderFailure", reflect.TypeOf((*MockRepository)(nil).RecordReminderFailure), ctx, arg)
+}
+
+// RecordWebhookDeliveryFailure mocks base method.
+func (m *MockRepository) RecordWebhookDeliveryFailure(ctx context.Context, arg database.RecordWebhookDeliveryFailureParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryFailure", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookDeliveryFailure indicates an expected call of RecordWebhookDeliveryFailure.
+func (mr *MockRepositoryMockRecorder) RecordWebhookDeliveryFailure(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryFailure", reflect.TypeOf((*MockRepository)(nil).RecordWebhookDeliveryFailure), ctx, arg)
+}
+
+// RecordWebhookDeliverySuccess mocks base method.
+func (m *MockRepository) RecordWebhookDeliverySuccess(ctx context.Context, arg database.RecordWebhookDeliverySuccessParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookDeliverySuccess", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookDeliverySuccess indicates an expected call of RecordWebhookDeliverySuccess.
+func (mr *MockRepositoryMockRecorder) RecordWebhookDeliverySuccess(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliverySuccess", reflect.TypeOf((*MockRepository)(nil).RecordWebhookDeliverySuccess), ctx, arg)
+}
+
+// RemoveTodoBlocker mocks base method.
+func (m *MockRepository) RemoveTodoBlocker(ctx context.Context, arg database.RemoveTodoBlockerParams) (database.TodoDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTodoBlocker", ctx, arg)
+	ret0, _ := ret[0].(database.TodoDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTodoBlocker indicates an expected call of RemoveTodoBlocker.
+func (mr *MockRepositoryMockRecorder) RemoveTodoBlocker(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTodoBlocker", reflect.TypeOf((*MockRepository)(nil).RemoveTodoBlocker), ctx, arg)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockRepository) RevokeAPIKey(ctx context.Context, arg database.RevokeAPIKeyParams) (database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, arg)
+	ret0, _ := ret[0].(database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockRepositoryMockRecorder) RevokeAPIKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockRepository)(nil).RevokeAPIKey), ctx, arg)
+}
+
+// SearchTodos mocks base method.
+func (m *MockRepository) SearchTodos(ctx context.Context, arg database.SearchTodosParams) ([]database.SearchTodosRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.SearchTodosRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTodos indicates an expected call of SearchTodos.
+func (mr *MockRepositoryMockRecorder) SearchTodos(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTodos", reflect.TypeOf((*MockRepository)(nil).SearchTodos), ctx, arg)
+}
+
+// SetTodoParent mocks base method.
+func (m *MockRepository) SetTodoParent(ctx context.Context, arg database.SetTodoParentParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoParent", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTodoParent indicates an expected call of SetTodoParent.
+func (mr *MockRepositoryMockRecorder) SetTodoParent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoParent", reflect.TypeOf((*MockRepository)(nil).SetTodoParent), ctx, arg)
+}
+
+// SetTodoTags mocks base method.
+func (m *MockRepository) SetTodoTags(ctx context.Context, arg database.SetTodoTagsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTodoTags", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTodoTags indicates an expected call of SetTodoTags.
+func (mr *MockRepositoryMockRecorder) SetTodoTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTodoTags", reflect.TypeOf((*MockRepository)(nil).SetTodoTags), ctx, arg)
+}
+
+// TouchBlockedTodos mocks base method.
+func (m *MockRepository) TouchBlockedTodos(ctx context.Context, arg database.TouchBlockedTodosParams) ([]database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchBlockedTodos", ctx, arg)
+	ret0, _ := ret[0].([]database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TouchBlockedTodos indicates an expected call of TouchBlockedTodos.
+func (mr *MockRepositoryMockRecorder) TouchBlockedTodos(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchBlockedTodos", reflect.TypeOf((*MockRepository)(nil).TouchBlockedTodos), ctx, arg)
+}
+
+// TouchTodo mocks base method.
+func (m *MockRepository) TouchTodo(ctx context.Context, arg database.TouchTodoParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchTodo", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TouchTodo indicates an expected call of TouchTodo.
+func (mr *MockRepositoryMockRecorder) TouchTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchTodo", reflect.TypeOf((*MockRepository)(nil).TouchTodo), ctx, arg)
+}
+
+// UpdateProject mocks base method.
+func (m *MockRepository) UpdateProject(ctx context.Context, arg database.UpdateProjectParams) (database.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProject", ctx, arg)
+	ret0, _ := ret[0].(database.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockRepositoryMockRecorder) UpdateProject(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockRepository)(nil).UpdateProject), ctx, arg)
+}
+
+// UpdateTodo mocks base method.
+func (m *MockRepository) UpdateTodo(ctx context.Context, arg database.UpdateTodoParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodo", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodo indicates an expected call of UpdateTodo.
+func (mr *MockRepositoryMockRecorder) UpdateTodo(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodo", reflect.TypeOf((*MockRepository)(nil).UpdateTodo), ctx, arg)
+}
+
+// UpdateTodoStatus mocks base method.
+func (m *MockRepository) UpdateTodoStatus(ctx context.Context, arg database.UpdateTodoStatusParams) (database.Todo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTodoStatus", ctx, arg)
+	ret0, _ := ret[0].(database.Todo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTodoStatus indicates an expected call of UpdateTodoStatus.
+func (mr *MockRepositoryMockRecorder) UpdateTodoStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTodoStatus", reflect.TypeOf((*MockRepository)(nil).UpdateTodoStatus), ctx, arg)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockRepository) UpdateWebhook(ctx context.Context, arg database.UpdateWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, arg)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockRepositoryMockRecorder) UpdateWebhook(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockRepository)(nil).UpdateWebhook), ctx, arg)
+}
+
+// UseAPIKey mocks base method.
+func (m *MockRepository) UseAPIKey(ctx context.Context, keyHash string) (database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseAPIKey", ctx, keyHash)
+	ret0, _ := ret[0].(database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseAPIKey indicates an expected call of UseAPIKey.
+func (mr *MockRepositoryMockRecorder) UseAPIKey(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAPIKey", reflect.TypeOf((*MockRepository)(nil).UseAPIKey), ctx, keyHash)
+}
+
+// MockPool is a mock of Pool interface.
+type MockPool struct {
+	ctrl     *gomock.Controller
+	recorder *MockPoolMockRecorder
+}
+
+// MockPoolMockRecorder is the mock recorder for MockPool.
+type MockPoolMockRecorder struct {
+	mock *MockPool
+}
+
+// NewMockPool creates a new mock instance.
+func NewMockPool(ctrl *gomock.Controller) *MockPool {
+	mock := &MockPool{ctrl: ctrl}
+	mock.recorder = &MockPoolMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPool) EXPECT() *MockPoolMockRecorder {
+	return m.recorder
+}
+
+// PingContext mocks base method.
+func (m *MockPool) PingContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingContext indicates an expected call of PingContext.
+func (mr *MockPoolMockRecorder) PingContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingContext", reflect.TypeOf((*MockPool)(nil).PingContext), ctx)
+}
+
+// Stats mocks base method.
+func (m *MockPool) Stats() sql.DBStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(sql.DBStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockPoolMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockPool)(nil).Stats))
 }
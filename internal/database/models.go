@@ -5,14 +5,227 @@
 package database
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+type ReminderChannel string
+
+const (
+	ReminderChannelEmail   ReminderChannel = "email"
+	ReminderChannelWebhook ReminderChannel = "webhook"
+	ReminderChannelLog     ReminderChannel = "log"
+)
+
+func (e *ReminderChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReminderChannel(s)
+	case string:
+		*e = ReminderChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReminderChannel: %T", src)
+	}
+	return nil
+}
+
+type NullReminderChannel struct {
+	ReminderChannel ReminderChannel
+	Valid           bool // Valid is true if ReminderChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReminderChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReminderChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReminderChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReminderChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReminderChannel), nil
+}
+
+type TodoStatus string
+
+const (
+	TodoStatusOpen       TodoStatus = "open"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusDone       TodoStatus = "done"
+	TodoStatusCancelled  TodoStatus = "cancelled"
+)
+
+func (e *TodoStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TodoStatus(s)
+	case string:
+		*e = TodoStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TodoStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTodoStatus struct {
+	TodoStatus TodoStatus
+	Valid      bool // Valid is true if TodoStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTodoStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TodoStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TodoStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTodoStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TodoStatus), nil
+}
+
+type APIKey struct {
+	ID         int32
+	UserID     int32
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+type Outbox struct {
+	ID        int64
+	Event     string
+	UserID    int32
+	TodoID    int32
+	Payload   json.RawMessage
+	CreatedAt time.Time
+	SentAt    sql.NullTime
+	Position  sql.NullInt64
+}
+
+type Project struct {
+	ID        int32
+	OwnerID   int32
+	Name      string
+	Color     string
+	Archived  bool
+	SortOrder int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RefreshToken struct {
+	ID        int32
+	UserID    int32
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+}
+
+type Reminder struct {
+	ID            int32
+	TodoID        int32
+	RemindAt      sql.NullTime
+	MinutesBefore sql.NullInt32
+	Channel       ReminderChannel
+	Target        sql.NullString
+	Attempts      int32
+	LastError     sql.NullString
+	LockedUntil   sql.NullTime
+	SentAt        sql.NullTime
+	FailedAt      sql.NullTime
+	CreatedAt     time.Time
+	Secret        sql.NullString
+}
+
+type Tag struct {
+	ID        int32
+	OwnerID   int32
+	Name      string
+	CreatedAt time.Time
+}
+
 type Todo struct {
-	ID          int32
-	Title       string
-	Description string
-	DueDate     string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID                 int32
+	Title              string
+	Description        string
+	DueDate            time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Status             TodoStatus
+	CompletedAt        sql.NullTime
+	Version            int32
+	OwnerID            int32
+	ProjectID          sql.NullInt32
+	ParentID           sql.NullInt32
+	Recurrence         sql.NullString
+	SeriesID           sql.NullInt32
+	Recurred           bool
+	RecurrenceTimezone sql.NullString
+}
+
+type TodoDependency struct {
+	TodoID    int32
+	BlockerID int32
+	CreatedAt time.Time
+}
+
+type TodoTag struct {
+	TodoID int32
+	TagID  int32
+}
+
+type User struct {
+	ID           int32
+	Email        string
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+type Webhook struct {
+	ID                  int32
+	UserID              int32
+	URL                 string
+	Secret              string
+	Events              []string
+	Active              bool
+	ConsecutiveFailures int32
+	DisabledAt          sql.NullTime
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int32
+	WebhookID      int32
+	Event          string
+	Payload        json.RawMessage
+	Attempts       int32
+	NextAttemptAt  time.Time
+	ResponseStatus sql.NullInt32
+	LastError      sql.NullString
+	DeliveredAt    sql.NullTime
+	FailedAt       sql.NullTime
+	CreatedAt      time.Time
 }
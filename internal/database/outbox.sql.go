@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: outbox.sql
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+SELECT id, event, user_id, todo_id, payload, created_at, sent_at, position FROM outbox
+WHERE sent_at IS NULL
+ORDER BY id
+LIMIT $1::int
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, rowLimit int32) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxEvents, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.UserID,
+			&i.TodoID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSentOutboxEvents = `-- name: DeleteSentOutboxEvents :exec
+DELETE FROM outbox
+WHERE sent_at < $1::timestamptz
+`
+
+func (q *Queries) DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteSentOutboxEvents, sentBefore)
+	return err
+}
+
+const getLastTodoEventPosition = `-- name: GetLastTodoEventPosition :one
+SELECT COALESCE(MAX(position), 0)::bigint FROM outbox
+WHERE user_id = $1::int
+`
+
+func (q *Queries) GetLastTodoEventPosition(ctx context.Context, userID int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLastTodoEventPosition, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getOutboxEvent = `-- name: GetOutboxEvent :one
+SELECT id, event, user_id, todo_id, payload, created_at, sent_at, position FROM outbox
+WHERE id = $1::bigint
+`
+
+func (q *Queries) GetOutboxEvent(ctx context.Context, id int64) (Outbox, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxEvent, id)
+	var i Outbox
+	err := row.Scan(
+		&i.ID,
+		&i.Event,
+		&i.UserID,
+		&i.TodoID,
+		&i.Payload,
+		&i.CreatedAt,
+		&i.SentAt,
+		&i.Position,
+	)
+	return i, err
+}
+
+const insertOutboxEvent = `-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (event, user_id, todo_id, payload)
+VALUES ($1::text, $2::int, $3::int, $4::jsonb)
+`
+
+type InsertOutboxEventParams struct {
+	Event   string
+	UserID  int32
+	TodoID  int32
+	Payload json.RawMessage
+}
+
+func (q *Queries) InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertOutboxEvent,
+		arg.Event,
+		arg.UserID,
+		arg.TodoID,
+		arg.Payload,
+	)
+	return err
+}
+
+const listTodoEvents = `-- name: ListTodoEvents :many
+SELECT id, event, user_id, todo_id, payload, created_at, sent_at, position FROM outbox
+WHERE user_id = $1::int AND position >= $2::bigint
+ORDER BY position
+LIMIT $3::int
+`
+
+type ListTodoEventsParams struct {
+	UserID       int32
+	FromPosition int64
+	RowLimit     int32
+}
+
+func (q *Queries) ListTodoEvents(ctx context.Context, arg ListTodoEventsParams) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, listTodoEvents, arg.UserID, arg.FromPosition, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.UserID,
+			&i.TodoID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventsSent = `-- name: MarkOutboxEventsSent :exec
+UPDATE outbox
+SET sent_at = NOW()
+WHERE id = ANY($1::bigint[]) AND sent_at IS NULL
+`
+
+func (q *Queries) MarkOutboxEventsSent(ctx context.Context, ids []int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventsSent, pq.Array(ids))
+	return err
+}
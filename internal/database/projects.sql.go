@@ -0,0 +1,294 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: projects.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createProject = `-- name: CreateProject :one
+INSERT INTO projects (owner_id, name, color, archived, sort_order)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, owner_id, name, color, archived, sort_order, created_at, updated_at
+`
+
+type CreateProjectParams struct {
+	OwnerID   int32
+	Name      string
+	Color     string
+	Archived  bool
+	SortOrder int32
+}
+
+func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, createProject,
+		arg.OwnerID,
+		arg.Name,
+		arg.Color,
+		arg.Archived,
+		arg.SortOrder,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.Color,
+		&i.Archived,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteProject = `-- name: DeleteProject :one
+DELETE FROM projects
+WHERE id = $1 AND owner_id = $2
+RETURNING id, owner_id, name, color, archived, sort_order, created_at, updated_at
+`
+
+type DeleteProjectParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) DeleteProject(ctx context.Context, arg DeleteProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, deleteProject, arg.ID, arg.OwnerID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.Color,
+		&i.Archived,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteProjectTodos = `-- name: DeleteProjectTodos :many
+WITH RECURSIVE deleted AS (
+    SELECT todos.id FROM todos
+    WHERE todos.project_id = $1::int AND todos.owner_id = $2::int
+    UNION
+    SELECT todos.id FROM todos
+    JOIN deleted ON todos.parent_id = deleted.id
+)
+DELETE FROM todos
+USING deleted
+WHERE todos.id = deleted.id
+RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
+`
+
+type DeleteProjectTodosParams struct {
+	ProjectID int32
+	OwnerID   int32
+}
+
+func (q *Queries) DeleteProjectTodos(ctx context.Context, arg DeleteProjectTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, deleteProjectTodos, arg.ProjectID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProject = `-- name: GetProject :one
+SELECT id, owner_id, name, color, archived, sort_order, created_at, updated_at FROM projects
+WHERE id = $1 AND owner_id = $2
+`
+
+type GetProjectParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) GetProject(ctx context.Context, arg GetProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, getProject, arg.ID, arg.OwnerID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.Color,
+		&i.Archived,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listProjects = `-- name: ListProjects :many
+SELECT id, owner_id, name, color, archived, sort_order, created_at, updated_at FROM projects
+WHERE owner_id = $1 AND ($2::boolean OR NOT archived)
+ORDER BY sort_order, id
+`
+
+type ListProjectsParams struct {
+	OwnerID         int32
+	IncludeArchived bool
+}
+
+func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjects, arg.OwnerID, arg.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Name,
+			&i.Color,
+			&i.Archived,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveProjectTodos = `-- name: MoveProjectTodos :many
+UPDATE todos
+SET project_id = $1::int, updated_at = NOW(), version = version + 1
+WHERE project_id = $2::int AND owner_id = $3::int
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
+`
+
+type MoveProjectTodosParams struct {
+	TargetProjectID sql.NullInt32
+	ProjectID       int32
+	OwnerID         int32
+}
+
+func (q *Queries) MoveProjectTodos(ctx context.Context, arg MoveProjectTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, moveProjectTodos, arg.TargetProjectID, arg.ProjectID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateProject = `-- name: UpdateProject :one
+UPDATE projects
+SET name = $3, color = $4, archived = $5, sort_order = $6, updated_at = NOW()
+WHERE id = $1 AND owner_id = $2
+RETURNING id, owner_id, name, color, archived, sort_order, created_at, updated_at
+`
+
+type UpdateProjectParams struct {
+	ID        int32
+	OwnerID   int32
+	Name      string
+	Color     string
+	Archived  bool
+	SortOrder int32
+}
+
+func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, updateProject,
+		arg.ID,
+		arg.OwnerID,
+		arg.Name,
+		arg.Color,
+		arg.Archived,
+		arg.SortOrder,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.Color,
+		&i.Archived,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
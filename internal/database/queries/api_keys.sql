@@ -0,0 +1,28 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY id;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: UseAPIKey :one
+WITH api_key AS (
+    SELECT * FROM api_keys
+    WHERE api_keys.key_hash = $1 AND api_keys.revoked_at IS NULL AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW())
+), used AS (
+    UPDATE api_keys
+    SET last_used_at = NOW()
+    FROM api_key
+    WHERE api_keys.id = api_key.id AND (api_key.last_used_at IS NULL OR api_key.last_used_at < NOW() - INTERVAL '1 minute')
+)
+SELECT api_keys.* FROM api_keys
+JOIN api_key ON api_key.id = api_keys.id;
\ No newline at end of file
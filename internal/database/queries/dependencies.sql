@@ -0,0 +1,43 @@
+-- name: LockTodoDependencies :exec
+SELECT pg_advisory_xact_lock(hashtext('todo_dependencies'), @owner_id::int);
+
+-- name: AddTodoBlocker :execrows
+INSERT INTO todo_dependencies (todo_id, blocker_id)
+VALUES (@todo_id, @blocker_id)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveTodoBlocker :one
+DELETE FROM todo_dependencies
+USING todos
+WHERE todo_dependencies.todo_id = @todo_id AND todo_dependencies.blocker_id = @blocker_id
+  AND todos.id = todo_dependencies.todo_id AND todos.owner_id = @owner_id::int
+RETURNING todo_dependencies.*;
+
+-- name: ListTodoBlockerChain :many
+WITH RECURSIVE chain AS (
+    SELECT todos.id FROM todos
+    WHERE todos.id = @id::int AND todos.owner_id = @owner_id::int
+    UNION
+    SELECT todo_dependencies.blocker_id FROM todo_dependencies
+    JOIN chain ON todo_dependencies.todo_id = chain.id
+)
+SELECT chain.id FROM chain;
+
+-- name: TouchBlockedTodos :many
+UPDATE todos
+SET updated_at = NOW(), version = version + 1
+FROM todo_dependencies
+WHERE todo_dependencies.blocker_id = @blocker_id::int AND todos.id = todo_dependencies.todo_id AND todos.owner_id = @owner_id::int
+RETURNING todos.*;
+
+-- name: ListTodoBlockers :many
+SELECT todo_dependencies.todo_id, todo_dependencies.blocker_id, todos.status AS blocker_status
+FROM todo_dependencies
+JOIN todos ON todos.id = todo_dependencies.blocker_id
+WHERE todo_dependencies.todo_id = ANY(@todo_ids::int[])
+ORDER BY todo_dependencies.todo_id, todo_dependencies.blocker_id;
+
+-- name: ListProjectTodos :many
+SELECT * FROM todos
+WHERE project_id = @project_id::int AND owner_id = @owner_id::int
+ORDER BY due_date, id;
\ No newline at end of file
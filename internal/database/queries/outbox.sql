@@ -0,0 +1,33 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (event, user_id, todo_id, payload)
+VALUES (@event::text, @user_id::int, @todo_id::int, @payload::jsonb);
+
+-- name: ClaimOutboxEvents :many
+SELECT * FROM outbox
+WHERE sent_at IS NULL
+ORDER BY id
+LIMIT @row_limit::int
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventsSent :exec
+UPDATE outbox
+SET sent_at = NOW()
+WHERE id = ANY(@ids::bigint[]) AND sent_at IS NULL;
+
+-- name: DeleteSentOutboxEvents :exec
+DELETE FROM outbox
+WHERE sent_at < @sent_before::timestamptz;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox
+WHERE id = @id::bigint;
+
+-- name: ListTodoEvents :many
+SELECT * FROM outbox
+WHERE user_id = @user_id::int AND position >= @from_position::bigint
+ORDER BY position
+LIMIT @row_limit::int;
+
+-- name: GetLastTodoEventPosition :one
+SELECT COALESCE(MAX(position), 0)::bigint FROM outbox
+WHERE user_id = @user_id::int;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- name: CreateProject :one
+INSERT INTO projects (owner_id, name, color, archived, sort_order)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListProjects :many
+SELECT * FROM projects
+WHERE owner_id = $1 AND (@include_archived::boolean OR NOT archived)
+ORDER BY sort_order, id;
+
+-- name: GetProject :one
+SELECT * FROM projects
+WHERE id = $1 AND owner_id = $2;
+
+-- name: UpdateProject :one
+UPDATE projects
+SET name = $3, color = $4, archived = $5, sort_order = $6, updated_at = NOW()
+WHERE id = $1 AND owner_id = $2
+RETURNING *;
+
+-- name: DeleteProject :one
+DELETE FROM projects
+WHERE id = @id AND owner_id = @owner_id
+RETURNING *;
+
+-- name: DeleteProjectTodos :many
+WITH RECURSIVE deleted AS (
+    SELECT todos.id FROM todos
+    WHERE todos.project_id = @project_id::int AND todos.owner_id = @owner_id::int
+    UNION
+    SELECT todos.id FROM todos
+    JOIN deleted ON todos.parent_id = deleted.id
+)
+DELETE FROM todos
+USING deleted
+WHERE todos.id = deleted.id
+RETURNING todos.*;
+
+-- name: MoveProjectTodos :many
+UPDATE todos
+SET project_id = sqlc.narg('target_project_id')::int, updated_at = NOW(), version = version + 1
+WHERE project_id = @project_id::int AND owner_id = @owner_id::int
+RETURNING *;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- name: CreateNextOccurrence :one
+WITH occurrence AS (
+    UPDATE todos
+    SET recurred = TRUE, series_id = COALESCE(todos.series_id, todos.id)
+    WHERE todos.id = @id::int AND todos.recurrence IS NOT NULL AND NOT todos.recurred
+    RETURNING todos.*
+), next_occurrence AS (
+    INSERT INTO todos (title, description, due_date, owner_id, project_id, parent_id, recurrence, recurrence_timezone, series_id)
+    SELECT occurrence.title, occurrence.description, @due_date::timestamptz, occurrence.owner_id, occurrence.project_id, occurrence.parent_id,
+        @recurrence::text, occurrence.recurrence_timezone, occurrence.series_id
+    FROM occurrence
+    RETURNING todos.*
+), next_tags AS (
+    INSERT INTO todo_tags (todo_id, tag_id)
+    SELECT next_occurrence.id, todo_tags.tag_id FROM next_occurrence
+    JOIN todo_tags ON todo_tags.todo_id = @id::int
+)
+SELECT next_occurrence.* FROM next_occurrence;
+
+-- name: EndTodoRecurrence :exec
+UPDATE todos
+SET recurred = TRUE
+WHERE id = @id::int;
+
+-- name: ListDueRecurringTodos :many
+SELECT * FROM todos
+WHERE recurrence IS NOT NULL AND NOT recurred AND status <> 'cancelled' AND due_date <= @due_before::timestamptz
+ORDER BY due_date, id
+LIMIT @row_limit::int;
\ No newline at end of file
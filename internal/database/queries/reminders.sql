@@ -0,0 +1,50 @@
+-- name: CreateReminder :one
+INSERT INTO reminders (todo_id, remind_at, minutes_before, channel, target, secret)
+SELECT todos.id, sqlc.narg('remind_at')::timestamptz, sqlc.narg('minutes_before')::int, @channel::reminder_channel, sqlc.narg('target')::text,
+    sqlc.narg('secret')::text
+FROM todos
+WHERE todos.id = @todo_id::int AND todos.owner_id = @owner_id::int
+RETURNING *;
+
+-- name: ListReminders :many
+SELECT reminders.* FROM reminders
+JOIN todos ON todos.id = reminders.todo_id
+WHERE reminders.todo_id = @todo_id::int AND todos.owner_id = @owner_id::int
+ORDER BY reminders.id;
+
+-- name: DeleteReminder :one
+DELETE FROM reminders
+USING todos
+WHERE reminders.id = @id::int AND reminders.todo_id = @todo_id::int
+  AND todos.id = reminders.todo_id AND todos.owner_id = @owner_id::int
+RETURNING reminders.*;
+
+-- name: ClaimDueReminders :many
+WITH due AS (
+    SELECT reminders.id FROM reminders
+    JOIN todos ON todos.id = reminders.todo_id
+    WHERE reminders.sent_at IS NULL AND reminders.failed_at IS NULL
+      AND (reminders.locked_until IS NULL OR reminders.locked_until <= @now::timestamptz)
+      AND todos.status IN ('open', 'in_progress')
+      AND COALESCE(reminders.remind_at, todos.due_date - reminders.minutes_before * INTERVAL '1 minute') <= @now::timestamptz
+    ORDER BY reminders.id
+    LIMIT @row_limit::int
+    FOR UPDATE OF reminders SKIP LOCKED
+)
+UPDATE reminders
+SET locked_until = @locked_until::timestamptz, attempts = reminders.attempts + 1
+FROM due, todos, users
+WHERE reminders.id = due.id AND todos.id = reminders.todo_id AND users.id = todos.owner_id
+RETURNING reminders.id, reminders.todo_id, reminders.channel, reminders.target, reminders.secret, reminders.attempts,
+    todos.title, todos.due_date, users.email;
+
+-- name: MarkReminderSent :exec
+UPDATE reminders
+SET sent_at = NOW(), locked_until = NULL, last_error = NULL
+WHERE id = @id::int;
+
+-- name: RecordReminderFailure :exec
+UPDATE reminders
+SET last_error = @last_error::text, locked_until = sqlc.narg('retry_at')::timestamptz,
+    failed_at = CASE WHEN sqlc.narg('retry_at')::timestamptz IS NULL THEN NOW() END
+WHERE id = @id::int;
\ No newline at end of file
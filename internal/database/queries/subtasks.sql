@@ -0,0 +1,57 @@
+-- name: ListSubtaskTree :many
+WITH RECURSIVE tree AS (
+    SELECT todos.id FROM todos
+    WHERE todos.parent_id = @id::int AND todos.owner_id = @owner_id::int
+    UNION
+    SELECT todos.id FROM todos
+    JOIN tree ON todos.parent_id = tree.id
+)
+SELECT todos.* FROM todos
+JOIN tree ON tree.id = todos.id
+ORDER BY todos.created_at, todos.id;
+
+-- name: ListTodoAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT todos.id, todos.parent_id FROM todos
+    WHERE todos.id = @id::int AND todos.owner_id = @owner_id::int
+    UNION
+    SELECT todos.id, todos.parent_id FROM todos
+    JOIN ancestors ON todos.id = ancestors.parent_id
+)
+SELECT ancestors.id FROM ancestors;
+
+-- name: SetTodoParent :one
+WITH RECURSIVE ancestors AS (
+    SELECT parents.id, parents.parent_id FROM todos AS parents
+    WHERE parents.id = sqlc.narg('parent_id')::int
+    UNION
+    SELECT parents.id, parents.parent_id FROM todos AS parents
+    JOIN ancestors ON parents.id = ancestors.parent_id
+)
+UPDATE todos
+SET parent_id = sqlc.narg('parent_id')::int, updated_at = NOW(), version = version + 1
+WHERE todos.id = @id::int AND todos.owner_id = @owner_id::int
+  AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR todos.version = ANY(@versions::int[]))
+  AND NOT EXISTS (SELECT 1 FROM ancestors WHERE ancestors.id = todos.id)
+RETURNING todos.*;
+
+-- name: ListSubtaskProgress :many
+SELECT parent_id::int AS todo_id,
+    count(*) FILTER (WHERE status <> 'cancelled')::int AS total,
+    count(*) FILTER (WHERE status = 'done')::int AS done
+FROM todos
+WHERE parent_id = ANY(@todo_ids::int[])
+GROUP BY parent_id;
+
+-- name: CompleteParentTodo :one
+UPDATE todos
+SET status = 'done', completed_at = NOW(), updated_at = NOW(), version = version + 1
+WHERE todos.id = @id::int AND todos.owner_id = @owner_id::int AND todos.status IN ('open', 'in_progress')
+  AND EXISTS (SELECT 1 FROM todos AS subtasks WHERE subtasks.parent_id = todos.id AND subtasks.status = 'done')
+  AND NOT EXISTS (SELECT 1 FROM todos AS subtasks WHERE subtasks.parent_id = todos.id AND subtasks.status IN ('open', 'in_progress'))
+  AND NOT EXISTS (
+      SELECT 1 FROM todo_dependencies
+      JOIN todos AS blockers ON blockers.id = todo_dependencies.blocker_id
+      WHERE todo_dependencies.todo_id = todos.id AND blockers.status IN ('open', 'in_progress')
+  )
+RETURNING *;
\ No newline at end of file
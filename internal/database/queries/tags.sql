@@ -0,0 +1,30 @@
+-- name: SetTodoTags :exec
+WITH new_tags AS (
+    INSERT INTO tags (owner_id, name)
+    SELECT @owner_id::int, unnest(@names::text[])
+    ON CONFLICT (owner_id, name) DO NOTHING
+    RETURNING id
+), wanted_tags AS (
+    SELECT id FROM new_tags
+    UNION
+    SELECT id FROM tags WHERE owner_id = @owner_id::int AND name = ANY(@names::text[])
+), removed_tags AS (
+    DELETE FROM todo_tags
+    WHERE todo_id = @todo_id::int AND tag_id NOT IN (SELECT id FROM wanted_tags)
+)
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT @todo_id::int, id FROM wanted_tags
+ON CONFLICT DO NOTHING;
+
+-- name: ListTodoTags :many
+SELECT todo_tags.todo_id, tags.name FROM todo_tags
+JOIN tags ON tags.id = todo_tags.tag_id
+WHERE todo_tags.todo_id = ANY(@todo_ids::int[])
+ORDER BY todo_tags.todo_id, tags.name;
+
+-- name: ListTags :many
+SELECT tags.id, tags.name, count(todo_tags.todo_id)::int AS todo_count FROM tags
+LEFT JOIN todo_tags ON todo_tags.tag_id = tags.id
+WHERE tags.owner_id = $1
+GROUP BY tags.id
+ORDER BY tags.name;
\ No newline at end of file
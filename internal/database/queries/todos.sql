@@ -1,22 +1,84 @@
 -- name: CreateTodo :one
-INSERT INTO todos (title, description, due_date)
-VALUES ($1, $2, $3)
+INSERT INTO todos (title, description, due_date, owner_id, project_id, parent_id, recurrence, recurrence_timezone)
+VALUES ($1, $2, $3, @owner_id::int, sqlc.narg('project_id')::int, sqlc.narg('parent_id')::int, sqlc.narg('recurrence')::text,
+    sqlc.narg('recurrence_timezone')::text)
 RETURNING *;
 
--- name: GetTodos :many
-SELECT * FROM todos;
-
 -- name: GetTodo :one
 SELECT * FROM todos
-WHERE id = $1;
+WHERE id = $1 AND owner_id = @owner_id::int;
 
 -- name: UpdateTodo :one
 UPDATE todos
-SET title = $2, description = $3, due_date = $4, updated_at = NOW()
-WHERE id = $1
+SET title = $2, description = $3, due_date = $4, recurrence = sqlc.narg('recurrence')::text,
+    recurrence_timezone = CASE WHEN sqlc.narg('recurrence')::text IS NULL THEN NULL
+        ELSE COALESCE(sqlc.narg('recurrence_timezone')::text, recurrence_timezone) END,
+    updated_at = NOW(), version = version + 1
+WHERE id = $1 AND owner_id = @owner_id::int AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR version = ANY(@versions::int[]))
+RETURNING *;
+
+-- name: PatchTodo :one
+UPDATE todos
+SET title = COALESCE(sqlc.narg('title'), title),
+    description = COALESCE(sqlc.narg('description'), description),
+    due_date = COALESCE(sqlc.narg('due_date'), due_date),
+    recurrence = CASE WHEN @remove_recurrence::boolean THEN NULL ELSE COALESCE(sqlc.narg('recurrence')::text, recurrence) END,
+    recurrence_timezone = CASE WHEN @remove_recurrence::boolean THEN NULL
+        ELSE COALESCE(sqlc.narg('recurrence_timezone')::text, recurrence_timezone) END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id AND owner_id = @owner_id::int AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR version = ANY(@versions::int[]))
 RETURNING *;
 
--- name: DeleteTodo :one
+-- name: DeleteTodo :many
+WITH RECURSIVE deleted AS (
+    SELECT todos.id FROM todos
+    WHERE todos.id = @id::int AND todos.owner_id = @owner_id::int
+      AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR todos.version = ANY(@versions::int[]))
+    UNION
+    SELECT todos.id FROM todos
+    JOIN deleted ON todos.parent_id = deleted.id
+)
 DELETE FROM todos
-WHERE id = $1
-RETURNING *;
\ No newline at end of file
+USING deleted
+WHERE todos.id = deleted.id
+RETURNING todos.*;
+
+-- name: TouchTodo :one
+UPDATE todos
+SET updated_at = NOW(), version = version + 1
+WHERE id = @id::int AND owner_id = @owner_id::int
+RETURNING *;
+
+-- name: UpdateTodoStatus :one
+UPDATE todos
+SET status = @status::todo_status,
+    completed_at = CASE WHEN @status::todo_status = 'done' THEN NOW() END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = @id AND owner_id = @owner_id::int AND status = @current_status::todo_status
+  AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR version = ANY(@versions::int[]))
+RETURNING *;
+
+-- name: MoveTodo :one
+UPDATE todos
+SET project_id = sqlc.narg('project_id')::int, updated_at = NOW(), version = version + 1
+WHERE id = @id AND owner_id = @owner_id::int AND (COALESCE(cardinality(@versions::int[]), 0) = 0 OR version = ANY(@versions::int[]))
+RETURNING *;
+
+-- name: SearchTodos :many
+SELECT sqlc.embed(todos),
+    ts_rank(search_vector, search_query)::real AS rank,
+    ts_headline('simple', translate(todos.title, chr(2) || chr(3), ''), search_query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", HighlightAll=true')::text AS title_highlight,
+    ts_headline('simple', translate(todos.description, chr(2) || chr(3), ''), search_query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", MaxFragments=3')::text AS description_highlight
+FROM todos
+CROSS JOIN LATERAL websearch_to_tsquery('simple', @query::text) AS search_query
+CROSS JOIN LATERAL (
+    SELECT setweight(to_tsvector('simple', todos.title), 'A') || setweight(to_tsvector('simple', todos.description), 'B') AS search_vector
+) AS document
+WHERE todos.owner_id = @owner_id::int
+  AND (setweight(to_tsvector('simple', todos.title), 'A') || setweight(to_tsvector('simple', todos.description), 'B')) @@ search_query
+ORDER BY rank DESC, todos.id ASC
+LIMIT @row_limit::int;
\ No newline at end of file
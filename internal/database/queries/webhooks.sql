@@ -0,0 +1,90 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, url, secret, events, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = @url::text, events = @events::text[], active = @active::boolean,
+    consecutive_failures = CASE WHEN @active::boolean THEN 0 ELSE webhooks.consecutive_failures END,
+    disabled_at = CASE WHEN @active::boolean THEN NULL ELSE webhooks.disabled_at END,
+    updated_at = NOW()
+WHERE id = @id::int AND user_id = @user_id::int
+RETURNING *;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ListWebhookDeliveries :many
+SELECT webhook_deliveries.* FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.webhook_id = @webhook_id::int AND webhooks.user_id = @user_id::int
+ORDER BY webhook_deliveries.id DESC
+LIMIT @row_limit::int;
+
+-- name: EnqueueWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (webhook_id, event, payload)
+SELECT webhooks.id, @event::text, @payload::jsonb
+FROM webhooks
+WHERE webhooks.user_id = @user_id::int AND webhooks.active AND @event::text = ANY(webhooks.events);
+
+-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+    SELECT webhook_deliveries.id FROM webhook_deliveries
+    JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+    WHERE webhook_deliveries.delivered_at IS NULL AND webhook_deliveries.failed_at IS NULL
+      AND webhook_deliveries.next_attempt_at <= @now::timestamptz
+      AND webhooks.active
+    ORDER BY webhook_deliveries.id
+    LIMIT @row_limit::int
+    FOR UPDATE OF webhook_deliveries SKIP LOCKED
+)
+UPDATE webhook_deliveries
+SET next_attempt_at = @locked_until::timestamptz, attempts = webhook_deliveries.attempts + 1
+FROM due, webhooks
+WHERE webhook_deliveries.id = due.id AND webhooks.id = webhook_deliveries.webhook_id
+RETURNING webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload,
+    webhook_deliveries.attempts, webhooks.url, webhooks.secret;
+
+-- name: RecordWebhookDeliverySuccess :exec
+WITH delivery AS (
+    UPDATE webhook_deliveries
+    SET delivered_at = NOW(), response_status = @response_status::int, last_error = NULL
+    WHERE webhook_deliveries.id = @id::int
+    RETURNING webhook_deliveries.webhook_id
+)
+UPDATE webhooks
+SET consecutive_failures = 0
+FROM delivery
+WHERE webhooks.id = delivery.webhook_id;
+
+-- name: RecordWebhookDeliveryFailure :one
+WITH delivery AS (
+    UPDATE webhook_deliveries
+    SET response_status = sqlc.narg('response_status')::int, last_error = @last_error::text,
+        next_attempt_at = COALESCE(sqlc.narg('retry_at')::timestamptz, webhook_deliveries.next_attempt_at),
+        failed_at = CASE WHEN sqlc.narg('retry_at')::timestamptz IS NULL THEN NOW() END
+    WHERE webhook_deliveries.id = @id::int
+    RETURNING webhook_deliveries.webhook_id
+)
+UPDATE webhooks
+SET consecutive_failures = webhooks.consecutive_failures + 1,
+    active = webhooks.active AND webhooks.consecutive_failures + 1 < @max_failures::int,
+    disabled_at = CASE
+        WHEN webhooks.active AND webhooks.consecutive_failures + 1 >= @max_failures::int THEN NOW()
+        ELSE webhooks.disabled_at
+    END
+FROM delivery
+WHERE webhooks.id = delivery.webhook_id
+RETURNING webhooks.*;
\ No newline at end of file
@@ -0,0 +1,144 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: recurrence.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createNextOccurrence = `-- name: CreateNextOccurrence :one
+WITH occurrence AS (
+    UPDATE todos
+    SET recurred = TRUE, series_id = COALESCE(todos.series_id, todos.id)
+    WHERE todos.id = $1::int AND todos.recurrence IS NOT NULL AND NOT todos.recurred
+    RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
+), next_occurrence AS (
+    INSERT INTO todos (title, description, due_date, owner_id, project_id, parent_id, recurrence, recurrence_timezone, series_id)
+    SELECT occurrence.title, occurrence.description, $2::timestamptz, occurrence.owner_id, occurrence.project_id, occurrence.parent_id,
+        $3::text, occurrence.recurrence_timezone, occurrence.series_id
+    FROM occurrence
+    RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
+), next_tags AS (
+    INSERT INTO todo_tags (todo_id, tag_id)
+    SELECT next_occurrence.id, todo_tags.tag_id FROM next_occurrence
+    JOIN todo_tags ON todo_tags.todo_id = $1::int
+)
+SELECT next_occurrence.id, next_occurrence.title, next_occurrence.description, next_occurrence.due_date, next_occurrence.created_at, next_occurrence.updated_at, next_occurrence.status, next_occurrence.completed_at, next_occurrence.version, next_occurrence.owner_id, next_occurrence.project_id, next_occurrence.parent_id, next_occurrence.recurrence, next_occurrence.series_id, next_occurrence.recurred, next_occurrence.recurrence_timezone FROM next_occurrence
+`
+
+type CreateNextOccurrenceParams struct {
+	ID         int32
+	DueDate    time.Time
+	Recurrence string
+}
+
+type CreateNextOccurrenceRow struct {
+	ID                 int32
+	Title              string
+	Description        string
+	DueDate            time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	Status             TodoStatus
+	CompletedAt        sql.NullTime
+	Version            int32
+	OwnerID            int32
+	ProjectID          sql.NullInt32
+	ParentID           sql.NullInt32
+	Recurrence         sql.NullString
+	SeriesID           sql.NullInt32
+	Recurred           bool
+	RecurrenceTimezone sql.NullString
+}
+
+func (q *Queries) CreateNextOccurrence(ctx context.Context, arg CreateNextOccurrenceParams) (CreateNextOccurrenceRow, error) {
+	row := q.db.QueryRowContext(ctx, createNextOccurrence, arg.ID, arg.DueDate, arg.Recurrence)
+	var i CreateNextOccurrenceRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
+
+const endTodoRecurrence = `-- name: EndTodoRecurrence :exec
+UPDATE todos
+SET recurred = TRUE
+WHERE id = $1::int
+`
+
+func (q *Queries) EndTodoRecurrence(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, endTodoRecurrence, id)
+	return err
+}
+
+const listDueRecurringTodos = `-- name: ListDueRecurringTodos :many
+SELECT id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone FROM todos
+WHERE recurrence IS NOT NULL AND NOT recurred AND status <> 'cancelled' AND due_date <= $1::timestamptz
+ORDER BY due_date, id
+LIMIT $2::int
+`
+
+type ListDueRecurringTodosParams struct {
+	DueBefore time.Time
+	RowLimit  int32
+}
+
+func (q *Queries) ListDueRecurringTodos(ctx context.Context, arg ListDueRecurringTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, listDueRecurringTodos, arg.DueBefore, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,244 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: reminders.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const claimDueReminders = `-- name: ClaimDueReminders :many
+WITH due AS (
+    SELECT reminders.id FROM reminders
+    JOIN todos ON todos.id = reminders.todo_id
+    WHERE reminders.sent_at IS NULL AND reminders.failed_at IS NULL
+      AND (reminders.locked_until IS NULL OR reminders.locked_until <= $2::timestamptz)
+      AND todos.status IN ('open', 'in_progress')
+      AND COALESCE(reminders.remind_at, todos.due_date - reminders.minutes_before * INTERVAL '1 minute') <= $2::timestamptz
+    ORDER BY reminders.id
+    LIMIT $3::int
+    FOR UPDATE OF reminders SKIP LOCKED
+)
+UPDATE reminders
+SET locked_until = $1::timestamptz, attempts = reminders.attempts + 1
+FROM due, todos, users
+WHERE reminders.id = due.id AND todos.id = reminders.todo_id AND users.id = todos.owner_id
+RETURNING reminders.id, reminders.todo_id, reminders.channel, reminders.target, reminders.secret, reminders.attempts,
+    todos.title, todos.due_date, users.email
+`
+
+type ClaimDueRemindersParams struct {
+	LockedUntil time.Time
+	Now         time.Time
+	RowLimit    int32
+}
+
+type ClaimDueRemindersRow struct {
+	ID       int32
+	TodoID   int32
+	Channel  ReminderChannel
+	Target   sql.NullString
+	Secret   sql.NullString
+	Attempts int32
+	Title    string
+	DueDate  time.Time
+	Email    string
+}
+
+func (q *Queries) ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ClaimDueRemindersRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueReminders, arg.LockedUntil, arg.Now, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimDueRemindersRow
+	for rows.Next() {
+		var i ClaimDueRemindersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.Channel,
+			&i.Target,
+			&i.Secret,
+			&i.Attempts,
+			&i.Title,
+			&i.DueDate,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createReminder = `-- name: CreateReminder :one
+INSERT INTO reminders (todo_id, remind_at, minutes_before, channel, target, secret)
+SELECT todos.id, $1::timestamptz, $2::int, $3::reminder_channel, $4::text,
+    $5::text
+FROM todos
+WHERE todos.id = $6::int AND todos.owner_id = $7::int
+RETURNING id, todo_id, remind_at, minutes_before, channel, target, attempts, last_error, locked_until, sent_at, failed_at, created_at, secret
+`
+
+type CreateReminderParams struct {
+	RemindAt      sql.NullTime
+	MinutesBefore sql.NullInt32
+	Channel       ReminderChannel
+	Target        sql.NullString
+	Secret        sql.NullString
+	TodoID        int32
+	OwnerID       int32
+}
+
+func (q *Queries) CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error) {
+	row := q.db.QueryRowContext(ctx, createReminder,
+		arg.RemindAt,
+		arg.MinutesBefore,
+		arg.Channel,
+		arg.Target,
+		arg.Secret,
+		arg.TodoID,
+		arg.OwnerID,
+	)
+	var i Reminder
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.RemindAt,
+		&i.MinutesBefore,
+		&i.Channel,
+		&i.Target,
+		&i.Attempts,
+		&i.LastError,
+		&i.LockedUntil,
+		&i.SentAt,
+		&i.FailedAt,
+		&i.CreatedAt,
+		&i.Secret,
+	)
+	return i, err
+}
+
+const deleteReminder = `-- name: DeleteReminder :one
+DELETE FROM reminders
+USING todos
+WHERE reminders.id = $1::int AND reminders.todo_id = $2::int
+  AND todos.id = reminders.todo_id AND todos.owner_id = $3::int
+RETURNING reminders.id, reminders.todo_id, reminders.remind_at, reminders.minutes_before, reminders.channel, reminders.target, reminders.attempts, reminders.last_error, reminders.locked_until, reminders.sent_at, reminders.failed_at, reminders.created_at, reminders.secret
+`
+
+type DeleteReminderParams struct {
+	ID      int32
+	TodoID  int32
+	OwnerID int32
+}
+
+func (q *Queries) DeleteReminder(ctx context.Context, arg DeleteReminderParams) (Reminder, error) {
+	row := q.db.QueryRowContext(ctx, deleteReminder, arg.ID, arg.TodoID, arg.OwnerID)
+	var i Reminder
+	err := row.Scan(
+		&i.ID,
+		&i.TodoID,
+		&i.RemindAt,
+		&i.MinutesBefore,
+		&i.Channel,
+		&i.Target,
+		&i.Attempts,
+		&i.LastError,
+		&i.LockedUntil,
+		&i.SentAt,
+		&i.FailedAt,
+		&i.CreatedAt,
+		&i.Secret,
+	)
+	return i, err
+}
+
+const listReminders = `-- name: ListReminders :many
+SELECT reminders.id, reminders.todo_id, reminders.remind_at, reminders.minutes_before, reminders.channel, reminders.target, reminders.attempts, reminders.last_error, reminders.locked_until, reminders.sent_at, reminders.failed_at, reminders.created_at, reminders.secret FROM reminders
+JOIN todos ON todos.id = reminders.todo_id
+WHERE reminders.todo_id = $1::int AND todos.owner_id = $2::int
+ORDER BY reminders.id
+`
+
+type ListRemindersParams struct {
+	TodoID  int32
+	OwnerID int32
+}
+
+func (q *Queries) ListReminders(ctx context.Context, arg ListRemindersParams) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listReminders, arg.TodoID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.TodoID,
+			&i.RemindAt,
+			&i.MinutesBefore,
+			&i.Channel,
+			&i.Target,
+			&i.Attempts,
+			&i.LastError,
+			&i.LockedUntil,
+			&i.SentAt,
+			&i.FailedAt,
+			&i.CreatedAt,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markReminderSent = `-- name: MarkReminderSent :exec
+UPDATE reminders
+SET sent_at = NOW(), locked_until = NULL, last_error = NULL
+WHERE id = $1::int
+`
+
+func (q *Queries) MarkReminderSent(ctx context.Context, id int32) error {
+	_, err := q.db.ExecContext(ctx, markReminderSent, id)
+	return err
+}
+
+const recordReminderFailure = `-- name: RecordReminderFailure :exec
+UPDATE reminders
+SET last_error = $1::text, locked_until = $2::timestamptz,
+    failed_at = CASE WHEN $2::timestamptz IS NULL THEN NOW() END
+WHERE id = $3::int
+`
+
+type RecordReminderFailureParams struct {
+	LastError string
+	RetryAt   sql.NullTime
+	ID        int32
+}
+
+func (q *Queries) RecordReminderFailure(ctx context.Context, arg RecordReminderFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordReminderFailure, arg.LastError, arg.RetryAt, arg.ID)
+	return err
+}
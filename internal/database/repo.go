@@ -1,12 +1,85 @@
 package database
 
-import "context"
+import (
+	"context"
+	"database/sql"
+	"time"
+)
 
-// Repository is an interface that defines the methods for interacting with the todos database.
+// Repository is an interface that defines the methods for interacting with the todos, projects, tags, reminders, webhooks, event outbox, users and API keys database.
 type Repository interface {
 	CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, error)
-	GetTodos(ctx context.Context) ([]Todo, error)
-	GetTodo(ctx context.Context, id int32) (Todo, error)
+	ListTodos(ctx context.Context, arg ListTodosParams) ([]Todo, error)
+	GetTodo(ctx context.Context, arg GetTodoParams) (Todo, error)
 	UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, error)
-	DeleteTodo(ctx context.Context, id int32) (Todo, error)
+	PatchTodo(ctx context.Context, arg PatchTodoParams) (Todo, error)
+	DeleteTodo(ctx context.Context, arg DeleteTodoParams) ([]Todo, error)
+	UpdateTodoStatus(ctx context.Context, arg UpdateTodoStatusParams) (Todo, error)
+	TouchTodo(ctx context.Context, arg TouchTodoParams) (Todo, error)
+	MoveTodo(ctx context.Context, arg MoveTodoParams) (Todo, error)
+	SearchTodos(ctx context.Context, arg SearchTodosParams) ([]SearchTodosRow, error)
+	MigrationVersion(ctx context.Context) (int64, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (APIKey, error)
+	ListAPIKeys(ctx context.Context, userID int32) ([]APIKey, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (APIKey, error)
+	UseAPIKey(ctx context.Context, keyHash string) (APIKey, error)
+	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	GetProject(ctx context.Context, arg GetProjectParams) (Project, error)
+	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	DeleteProject(ctx context.Context, arg DeleteProjectParams) (Project, error)
+	DeleteProjectTodos(ctx context.Context, arg DeleteProjectTodosParams) ([]Todo, error)
+	MoveProjectTodos(ctx context.Context, arg MoveProjectTodosParams) ([]Todo, error)
+	SetTodoTags(ctx context.Context, arg SetTodoTagsParams) error
+	ListTodoTags(ctx context.Context, todoIDs []int32) ([]ListTodoTagsRow, error)
+	ListTags(ctx context.Context, ownerID int32) ([]ListTagsRow, error)
+	ListSubtaskTree(ctx context.Context, arg ListSubtaskTreeParams) ([]Todo, error)
+	ListTodoAncestors(ctx context.Context, arg ListTodoAncestorsParams) ([]int32, error)
+	SetTodoParent(ctx context.Context, arg SetTodoParentParams) (Todo, error)
+	ListSubtaskProgress(ctx context.Context, todoIDs []int32) ([]ListSubtaskProgressRow, error)
+	CompleteParentTodo(ctx context.Context, arg CompleteParentTodoParams) (Todo, error)
+	LockTodoDependencies(ctx context.Context, ownerID int32) error
+	AddTodoBlocker(ctx context.Context, arg AddTodoBlockerParams) (int64, error)
+	RemoveTodoBlocker(ctx context.Context, arg RemoveTodoBlockerParams) (TodoDependency, error)
+	ListTodoBlockerChain(ctx context.Context, arg ListTodoBlockerChainParams) ([]int32, error)
+	ListTodoBlockers(ctx context.Context, todoIDs []int32) ([]ListTodoBlockersRow, error)
+	TouchBlockedTodos(ctx context.Context, arg TouchBlockedTodosParams) ([]Todo, error)
+	ListProjectTodos(ctx context.Context, arg ListProjectTodosParams) ([]Todo, error)
+	CreateNextOccurrence(ctx context.Context, arg CreateNextOccurrenceParams) (CreateNextOccurrenceRow, error)
+	EndTodoRecurrence(ctx context.Context, id int32) error
+	ListDueRecurringTodos(ctx context.Context, arg ListDueRecurringTodosParams) ([]Todo, error)
+	CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error)
+	ListReminders(ctx context.Context, arg ListRemindersParams) ([]Reminder, error)
+	DeleteReminder(ctx context.Context, arg DeleteReminderParams) (Reminder, error)
+	ClaimDueReminders(ctx context.Context, arg ClaimDueRemindersParams) ([]ClaimDueRemindersRow, error)
+	MarkReminderSent(ctx context.Context, id int32) error
+	RecordReminderFailure(ctx context.Context, arg RecordReminderFailureParams) error
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	ListWebhooks(ctx context.Context, userID int32) ([]Webhook, error)
+	GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error)
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (Webhook, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
+	RecordWebhookDeliveryFailure(ctx context.Context, arg RecordWebhookDeliveryFailureParams) (Webhook, error)
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) error
+	RecordWebhookDeliverySuccess(ctx context.Context, arg RecordWebhookDeliverySuccessParams) error
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error
+	MarkOutboxEventsSent(ctx context.Context, ids []int64) error
+	DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) error
+	InTx(ctx context.Context, fn func(repo Repository) error) error
+	ClaimOutboxEvents(ctx context.Context, rowLimit int32) ([]Outbox, error)
+	GetOutboxEvent(ctx context.Context, id int64) (Outbox, error)
+	ListTodoEvents(ctx context.Context, arg ListTodoEventsParams) ([]Outbox, error)
+	GetLastTodoEventPosition(ctx context.Context, userID int32) (int64, error)
+}
+
+// Pool is an interface that defines the methods for checking the database connection pool.
+type Pool interface {
+	PingContext(ctx context.Context) error
+	Stats() sql.DBStats
 }
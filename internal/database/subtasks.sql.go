@@ -0,0 +1,243 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: subtasks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const completeParentTodo = `-- name: CompleteParentTodo :one
+UPDATE todos
+SET status = 'done', completed_at = NOW(), updated_at = NOW(), version = version + 1
+WHERE todos.id = $1::int AND todos.owner_id = $2::int AND todos.status IN ('open', 'in_progress')
+  AND EXISTS (SELECT 1 FROM todos AS subtasks WHERE subtasks.parent_id = todos.id AND subtasks.status = 'done')
+  AND NOT EXISTS (SELECT 1 FROM todos AS subtasks WHERE subtasks.parent_id = todos.id AND subtasks.status IN ('open', 'in_progress'))
+  AND NOT EXISTS (
+      SELECT 1 FROM todo_dependencies
+      JOIN todos AS blockers ON blockers.id = todo_dependencies.blocker_id
+      WHERE todo_dependencies.todo_id = todos.id AND blockers.status IN ('open', 'in_progress')
+  )
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
+`
+
+type CompleteParentTodoParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) CompleteParentTodo(ctx context.Context, arg CompleteParentTodoParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, completeParentTodo, arg.ID, arg.OwnerID)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
+
+const listSubtaskProgress = `-- name: ListSubtaskProgress :many
+SELECT parent_id::int AS todo_id,
+    count(*) FILTER (WHERE status <> 'cancelled')::int AS total,
+    count(*) FILTER (WHERE status = 'done')::int AS done
+FROM todos
+WHERE parent_id = ANY($1::int[])
+GROUP BY parent_id
+`
+
+type ListSubtaskProgressRow struct {
+	TodoID int32
+	Total  int32
+	Done   int32
+}
+
+func (q *Queries) ListSubtaskProgress(ctx context.Context, todoIds []int32) ([]ListSubtaskProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSubtaskProgress, pq.Array(todoIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSubtaskProgressRow
+	for rows.Next() {
+		var i ListSubtaskProgressRow
+		if err := rows.Scan(&i.TodoID, &i.Total, &i.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubtaskTree = `-- name: ListSubtaskTree :many
+WITH RECURSIVE tree AS (
+    SELECT todos.id FROM todos
+    WHERE todos.parent_id = $1::int AND todos.owner_id = $2::int
+    UNION
+    SELECT todos.id FROM todos
+    JOIN tree ON todos.parent_id = tree.id
+)
+SELECT todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone FROM todos
+JOIN tree ON tree.id = todos.id
+ORDER BY todos.created_at, todos.id
+`
+
+type ListSubtaskTreeParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) ListSubtaskTree(ctx context.Context, arg ListSubtaskTreeParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, listSubtaskTree, arg.ID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTodoAncestors = `-- name: ListTodoAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT todos.id, todos.parent_id FROM todos
+    WHERE todos.id = $1::int AND todos.owner_id = $2::int
+    UNION
+    SELECT todos.id, todos.parent_id FROM todos
+    JOIN ancestors ON todos.id = ancestors.parent_id
+)
+SELECT ancestors.id FROM ancestors
+`
+
+type ListTodoAncestorsParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) ListTodoAncestors(ctx context.Context, arg ListTodoAncestorsParams) ([]int32, error) {
+	rows, err := q.db.QueryContext(ctx, listTodoAncestors, arg.ID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTodoParent = `-- name: SetTodoParent :one
+WITH RECURSIVE ancestors AS (
+    SELECT parents.id, parents.parent_id FROM todos AS parents
+    WHERE parents.id = $1::int
+    UNION
+    SELECT parents.id, parents.parent_id FROM todos AS parents
+    JOIN ancestors ON parents.id = ancestors.parent_id
+)
+UPDATE todos
+SET parent_id = $1::int, updated_at = NOW(), version = version + 1
+WHERE todos.id = $2::int AND todos.owner_id = $3::int
+  AND (COALESCE(cardinality($4::int[]), 0) = 0 OR todos.version = ANY($4::int[]))
+  AND NOT EXISTS (SELECT 1 FROM ancestors WHERE ancestors.id = todos.id)
+RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
+`
+
+type SetTodoParentParams struct {
+	ParentID sql.NullInt32
+	ID       int32
+	OwnerID  int32
+	Versions []int32
+}
+
+func (q *Queries) SetTodoParent(ctx context.Context, arg SetTodoParentParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, setTodoParent,
+		arg.ParentID,
+		arg.ID,
+		arg.OwnerID,
+		pq.Array(arg.Versions),
+	)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
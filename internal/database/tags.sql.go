@@ -0,0 +1,114 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: tags.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const listTags = `-- name: ListTags :many
+SELECT tags.id, tags.name, count(todo_tags.todo_id)::int AS todo_count FROM tags
+LEFT JOIN todo_tags ON todo_tags.tag_id = tags.id
+WHERE tags.owner_id = $1
+GROUP BY tags.id
+ORDER BY tags.name
+`
+
+type ListTagsRow struct {
+	ID        int32
+	Name      string
+	TodoCount int32
+}
+
+func (q *Queries) ListTags(ctx context.Context, ownerID int32) ([]ListTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTags, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTagsRow
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.TodoCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTodoTags = `-- name: ListTodoTags :many
+SELECT todo_tags.todo_id, tags.name FROM todo_tags
+JOIN tags ON tags.id = todo_tags.tag_id
+WHERE todo_tags.todo_id = ANY($1::int[])
+ORDER BY todo_tags.todo_id, tags.name
+`
+
+type ListTodoTagsRow struct {
+	TodoID int32
+	Name   string
+}
+
+func (q *Queries) ListTodoTags(ctx context.Context, todoIds []int32) ([]ListTodoTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTodoTags, pq.Array(todoIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTodoTagsRow
+	for rows.Next() {
+		var i ListTodoTagsRow
+		if err := rows.Scan(&i.TodoID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTodoTags = `-- name: SetTodoTags :exec
+WITH new_tags AS (
+    INSERT INTO tags (owner_id, name)
+    SELECT $2::int, unnest($3::text[])
+    ON CONFLICT (owner_id, name) DO NOTHING
+    RETURNING id
+), wanted_tags AS (
+    SELECT id FROM new_tags
+    UNION
+    SELECT id FROM tags WHERE owner_id = $2::int AND name = ANY($3::text[])
+), removed_tags AS (
+    DELETE FROM todo_tags
+    WHERE todo_id = $1::int AND tag_id NOT IN (SELECT id FROM wanted_tags)
+)
+INSERT INTO todo_tags (todo_id, tag_id)
+SELECT $1::int, id FROM wanted_tags
+ON CONFLICT DO NOTHING
+`
+
+type SetTodoTagsParams struct {
+	TodoID  int32
+	OwnerID int32
+	Names   []string
+}
+
+func (q *Queries) SetTodoTags(ctx context.Context, arg SetTodoTagsParams) error {
+	_, err := q.db.ExecContext(ctx, setTodoTags, arg.TodoID, arg.OwnerID, pq.Array(arg.Names))
+	return err
+}
@@ -0,0 +1,151 @@
+package todoquery
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// columns lists the columns List selects, in the order of the fields of database.Todo they are scanned into.
+const columns = "id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone"
+
+// filter selects the todos matching the Params filters; the keyset condition and ORDER BY are appended to it.
+const filter = `SELECT ` + columns + ` FROM todos
+WHERE owner_id = $1::int
+  AND ($2::int IS NULL OR project_id = $2::int)
+  AND (COALESCE(cardinality($3::text[]), 0) = 0 OR status::text = ANY($3::text[]))
+  AND (COALESCE(cardinality($4::text[]), 0) = 0 OR (
+        SELECT count(*) FROM todo_tags
+        JOIN tags ON tags.id = todo_tags.tag_id
+        WHERE todo_tags.todo_id = todos.id AND tags.name = ANY($4::text[])
+    ) >= CASE WHEN $5::boolean THEN cardinality($4::text[]) ELSE 1 END)
+  AND ($6::timestamptz IS NULL OR due_date >= $6::timestamptz)
+  AND ($7::timestamptz IS NULL OR due_date < $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR created_at >= $8::timestamptz)
+  AND ($9::timestamptz IS NULL OR created_at < $9::timestamptz)
+  AND ($10::timestamptz IS NULL OR updated_at >= $10::timestamptz)
+  AND ($11::timestamptz IS NULL OR updated_at < $11::timestamptz)`
+
+// sortColumns lists the columns todos can be sorted by.
+var sortColumns = map[string]bool{
+	"due_date":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Params defines the filters, the sort keys and the cursor of a page of todos of a user.
+// SortKey2 is optional, and the page starts after the cursor when CursorID is set.
+type Params struct {
+	SortKey1     string
+	SortKey2     string
+	OwnerID      int32
+	ProjectID    sql.NullInt32
+	Statuses     []string
+	Tags         []string
+	AllTags      bool
+	DueFrom      sql.NullTime
+	DueTo        sql.NullTime
+	CreatedFrom  sql.NullTime
+	CreatedTo    sql.NullTime
+	UpdatedFrom  sql.NullTime
+	UpdatedTo    sql.NullTime
+	CursorID     sql.NullInt32
+	CursorKey1   sql.NullTime
+	SortKey1Desc bool
+	CursorKey2   sql.NullTime
+	SortKey2Desc bool
+	RowLimit     int32
+}
+
+// sortKey is a column of the keyset ordering together with its value in the cursor.
+type sortKey struct {
+	column string
+	desc   bool
+	cursor any
+}
+
+// List returns the query selecting the page of todos arg defines and its arguments.
+// It is built at run time rather than by sqlc: the keyset condition and ORDER BY name
+// the sort columns directly, so the (owner_id, <key>, id) indexes can serve the page.
+func List(arg Params) (string, []any, error) {
+	keys := []sortKey{{arg.SortKey1, arg.SortKey1Desc, arg.CursorKey1}}
+	if arg.SortKey2 != "" {
+		keys = append(keys, sortKey{arg.SortKey2, arg.SortKey2Desc, arg.CursorKey2})
+	}
+	for _, key := range keys {
+		if !sortColumns[key.column] {
+			return "", nil, fmt.Errorf("unknown todo sort key %q", key.column)
+		}
+	}
+	// Ties are broken by id in the direction of the last key, so that a single key sort
+	// is read from its index in either direction.
+	keys = append(keys, sortKey{"id", keys[len(keys)-1].desc, arg.CursorID})
+
+	args := []any{
+		arg.OwnerID,
+		arg.ProjectID,
+		pq.Array(arg.Statuses),
+		pq.Array(arg.Tags),
+		arg.AllTags,
+		arg.DueFrom,
+		arg.DueTo,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+	}
+
+	var query strings.Builder
+	query.WriteString(filter)
+	if arg.CursorID.Valid {
+		query.WriteString("\n  AND ")
+		query.WriteString(keysetCondition(keys, &args))
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key.column + " ASC"
+		if key.desc {
+			order[i] = key.column + " DESC"
+		}
+	}
+	args = append(args, arg.RowLimit)
+	fmt.Fprintf(&query, "\nORDER BY %s\nLIMIT $%d::int", strings.Join(order, ", "), len(args))
+
+	return query.String(), args, nil
+}
+
+// keysetCondition returns the condition selecting the rows that follow the cursor in the order of keys,
+// appending the cursor values to args. Keys sorted in one direction are compared as a row,
+// which the index scan can start from; mixed directions are compared key by key.
+func keysetCondition(keys []sortKey, args *[]any) string {
+	columns := make([]string, len(keys))
+	values := make([]string, len(keys))
+	sameDirection := true
+	for i, key := range keys {
+		*args = append(*args, key.cursor)
+		columns[i] = key.column
+		values[i] = fmt.Sprintf("$%d", len(*args))
+		sameDirection = sameDirection && key.desc == keys[0].desc
+	}
+
+	if sameDirection {
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), keysetOperator(keys[0].desc), strings.Join(values, ", "))
+	}
+
+	last := len(keys) - 1
+	condition := fmt.Sprintf("%s %s %s", columns[last], keysetOperator(keys[last].desc), values[last])
+	for i := last - 1; i >= 0; i-- {
+		condition = fmt.Sprintf("(%s %s %s OR (%s = %s AND %s))",
+			columns[i], keysetOperator(keys[i].desc), values[i], columns[i], values[i], condition)
+	}
+	return condition
+}
+
+func keysetOperator(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
@@ -0,0 +1,148 @@
+package todoquery
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestListFilters(t *testing.T) {
+	day := time.Date(2024, 9, 5, 0, 0, 0, 0, time.UTC)
+	at := func(days int) sql.NullTime {
+		return sql.NullTime{Time: day.AddDate(0, 0, days), Valid: true}
+	}
+	arg := Params{
+		SortKey1:    "due_date",
+		OwnerID:     1,
+		ProjectID:   sql.NullInt32{Int32: 2, Valid: true},
+		Statuses:    []string{"open", "in_progress"},
+		Tags:        []string{"work", "home"},
+		AllTags:     true,
+		DueFrom:     at(1),
+		DueTo:       at(2),
+		CreatedFrom: at(3),
+		CreatedTo:   at(4),
+		UpdatedFrom: at(5),
+		UpdatedTo:   at(6),
+		RowLimit:    20,
+	}
+
+	query, args, err := List(arg)
+	require.NoError(t, err)
+
+	// Every filter compares its column with the argument holding its value.
+	for predicate, expected := range map[string]any{
+		`owner_id = \$(\d+)::int`:                 arg.OwnerID,
+		`project_id = \$(\d+)::int`:               arg.ProjectID,
+		`status::text = ANY\(\$(\d+)::text\[\]\)`: pq.Array(arg.Statuses),
+		`tags.name = ANY\(\$(\d+)::text\[\]\)`:    pq.Array(arg.Tags),
+		`CASE WHEN \$(\d+)::boolean`:              arg.AllTags,
+		`due_date >= \$(\d+)::timestamptz`:        arg.DueFrom,
+		`due_date < \$(\d+)::timestamptz`:         arg.DueTo,
+		`created_at >= \$(\d+)::timestamptz`:      arg.CreatedFrom,
+		`created_at < \$(\d+)::timestamptz`:       arg.CreatedTo,
+		`updated_at >= \$(\d+)::timestamptz`:      arg.UpdatedFrom,
+		`updated_at < \$(\d+)::timestamptz`:       arg.UpdatedTo,
+		`LIMIT \$(\d+)::int`:                      arg.RowLimit,
+	} {
+		match := regexp.MustCompile(predicate).FindStringSubmatch(query)
+		require.NotNil(t, match, predicate)
+		n, err := strconv.Atoi(match[1])
+		require.NoError(t, err)
+		require.Equal(t, expected, args[n-1], predicate)
+	}
+	require.Len(t, args, 12)
+}
+
+func TestListKeyset(t *testing.T) {
+	cursorKey1 := sql.NullTime{Time: time.Date(2024, 9, 5, 0, 0, 0, 0, time.UTC), Valid: true}
+	cursorKey2 := sql.NullTime{Time: time.Date(2024, 9, 6, 0, 0, 0, 0, time.UTC), Valid: true}
+	cursorID := sql.NullInt32{Int32: 7, Valid: true}
+
+	tests := []struct {
+		name         string
+		arg          Params
+		expectedTail string
+		expectedArgs []any
+		expectedErr  bool
+	}{
+		{
+			name:         "First Page",
+			arg:          Params{SortKey1: "due_date", RowLimit: 20},
+			expectedTail: "\nORDER BY due_date ASC, id ASC\nLIMIT $12::int",
+			expectedArgs: []any{int32(20)},
+		},
+		{
+			name:         "First Page Descending",
+			arg:          Params{SortKey1: "created_at", SortKey1Desc: true, RowLimit: 20},
+			expectedTail: "\nORDER BY created_at DESC, id DESC\nLIMIT $12::int",
+			expectedArgs: []any{int32(20)},
+		},
+		{
+			name:         "Cursor",
+			arg:          Params{SortKey1: "due_date", CursorKey1: cursorKey1, CursorID: cursorID, RowLimit: 20},
+			expectedTail: "\n  AND (due_date, id) > ($12, $13)\nORDER BY due_date ASC, id ASC\nLIMIT $14::int",
+			expectedArgs: []any{cursorKey1, cursorID, int32(20)},
+		},
+		{
+			name:         "Cursor Descending",
+			arg:          Params{SortKey1: "updated_at", SortKey1Desc: true, CursorKey1: cursorKey1, CursorID: cursorID, RowLimit: 20},
+			expectedTail: "\n  AND (updated_at, id) < ($12, $13)\nORDER BY updated_at DESC, id DESC\nLIMIT $14::int",
+			expectedArgs: []any{cursorKey1, cursorID, int32(20)},
+		},
+		{
+			name: "Two Keys",
+			arg: Params{
+				SortKey1: "due_date", SortKey2: "created_at",
+				CursorKey1: cursorKey1, CursorKey2: cursorKey2, CursorID: cursorID, RowLimit: 20,
+			},
+			expectedTail: "\n  AND (due_date, created_at, id) > ($12, $13, $14)\nORDER BY due_date ASC, created_at ASC, id ASC\nLIMIT $15::int",
+			expectedArgs: []any{cursorKey1, cursorKey2, cursorID, int32(20)},
+		},
+		{
+			name: "Two Keys First Page",
+			arg: Params{
+				SortKey1: "due_date", SortKey2: "updated_at", SortKey2Desc: true, RowLimit: 20,
+			},
+			expectedTail: "\nORDER BY due_date ASC, updated_at DESC, id DESC\nLIMIT $12::int",
+			expectedArgs: []any{int32(20)},
+		},
+		{
+			name: "Two Keys Mixed Directions",
+			arg: Params{
+				SortKey1: "due_date", SortKey2: "updated_at", SortKey2Desc: true,
+				CursorKey1: cursorKey1, CursorKey2: cursorKey2, CursorID: cursorID, RowLimit: 20,
+			},
+			expectedTail: "\n  AND (due_date > $12 OR (due_date = $12 AND (updated_at < $13 OR (updated_at = $13 AND id < $14))))" +
+				"\nORDER BY due_date ASC, updated_at DESC, id DESC\nLIMIT $15::int",
+			expectedArgs: []any{cursorKey1, cursorKey2, cursorID, int32(20)},
+		},
+		{
+			name:        "Unknown Sort Key",
+			arg:         Params{SortKey1: "title", RowLimit: 20},
+			expectedErr: true,
+		},
+		{
+			name:        "Unknown Second Sort Key",
+			arg:         Params{SortKey1: "due_date", SortKey2: "id; DROP TABLE todos", RowLimit: 20},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := List(tt.arg)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			if tt.expectedErr {
+				return
+			}
+			require.Equal(t, filter+tt.expectedTail, query)
+			require.Equal(t, tt.expectedArgs, args[11:])
+		})
+	}
+}
@@ -7,22 +7,41 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const createTodo = `-- name: CreateTodo :one
-INSERT INTO todos (title, description, due_date)
-VALUES ($1, $2, $3)
-RETURNING id, title, description, due_date, created_at, updated_at
+INSERT INTO todos (title, description, due_date, owner_id, project_id, parent_id, recurrence, recurrence_timezone)
+VALUES ($1, $2, $3, $4::int, $5::int, $6::int, $7::text,
+    $8::text)
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
 `
 
 type CreateTodoParams struct {
-	Title       string
-	Description string
-	DueDate     string
+	Title              string
+	Description        string
+	DueDate            time.Time
+	OwnerID            int32
+	ProjectID          sql.NullInt32
+	ParentID           sql.NullInt32
+	Recurrence         sql.NullString
+	RecurrenceTimezone sql.NullString
 }
 
 func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, error) {
-	row := q.db.QueryRowContext(ctx, createTodo, arg.Title, arg.Description, arg.DueDate)
+	row := q.db.QueryRowContext(ctx, createTodo,
+		arg.Title,
+		arg.Description,
+		arg.DueDate,
+		arg.OwnerID,
+		arg.ProjectID,
+		arg.ParentID,
+		arg.Recurrence,
+		arg.RecurrenceTimezone,
+	)
 	var i Todo
 	err := row.Scan(
 		&i.ID,
@@ -31,18 +50,93 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (Todo, e
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
 	)
 	return i, err
 }
 
-const deleteTodo = `-- name: DeleteTodo :one
+const deleteTodo = `-- name: DeleteTodo :many
+WITH RECURSIVE deleted AS (
+    SELECT todos.id FROM todos
+    WHERE todos.id = $1::int AND todos.owner_id = $2::int
+      AND (COALESCE(cardinality($3::int[]), 0) = 0 OR todos.version = ANY($3::int[]))
+    UNION
+    SELECT todos.id FROM todos
+    JOIN deleted ON todos.parent_id = deleted.id
+)
 DELETE FROM todos
-WHERE id = $1
-RETURNING id, title, description, due_date, created_at, updated_at
+USING deleted
+WHERE todos.id = deleted.id
+RETURNING todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone
 `
 
-func (q *Queries) DeleteTodo(ctx context.Context, id int32) (Todo, error) {
-	row := q.db.QueryRowContext(ctx, deleteTodo, id)
+type DeleteTodoParams struct {
+	ID       int32
+	OwnerID  int32
+	Versions []int32
+}
+
+func (q *Queries) DeleteTodo(ctx context.Context, arg DeleteTodoParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, deleteTodo, arg.ID, arg.OwnerID, pq.Array(arg.Versions))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.CompletedAt,
+			&i.Version,
+			&i.OwnerID,
+			&i.ProjectID,
+			&i.ParentID,
+			&i.Recurrence,
+			&i.SeriesID,
+			&i.Recurred,
+			&i.RecurrenceTimezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodo = `-- name: GetTodo :one
+SELECT id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone FROM todos
+WHERE id = $1 AND owner_id = $2::int
+`
+
+type GetTodoParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) GetTodo(ctx context.Context, arg GetTodoParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, getTodo, arg.ID, arg.OwnerID)
 	var i Todo
 	err := row.Scan(
 		&i.ID,
@@ -51,17 +145,41 @@ func (q *Queries) DeleteTodo(ctx context.Context, id int32) (Todo, error) {
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
 	)
 	return i, err
 }
 
-const getTodo = `-- name: GetTodo :one
-SELECT id, title, description, due_date, created_at, updated_at FROM todos
-WHERE id = $1
+const moveTodo = `-- name: MoveTodo :one
+UPDATE todos
+SET project_id = $1::int, updated_at = NOW(), version = version + 1
+WHERE id = $2 AND owner_id = $3::int AND (COALESCE(cardinality($4::int[]), 0) = 0 OR version = ANY($4::int[]))
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
 `
 
-func (q *Queries) GetTodo(ctx context.Context, id int32) (Todo, error) {
-	row := q.db.QueryRowContext(ctx, getTodo, id)
+type MoveTodoParams struct {
+	ProjectID sql.NullInt32
+	ID        int32
+	OwnerID   int32
+	Versions  []int32
+}
+
+func (q *Queries) MoveTodo(ctx context.Context, arg MoveTodoParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, moveTodo,
+		arg.ProjectID,
+		arg.ID,
+		arg.OwnerID,
+		pq.Array(arg.Versions),
+	)
 	var i Todo
 	err := row.Scan(
 		&i.ID,
@@ -70,30 +188,140 @@ func (q *Queries) GetTodo(ctx context.Context, id int32) (Todo, error) {
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
 	)
 	return i, err
 }
 
-const getTodos = `-- name: GetTodos :many
-SELECT id, title, description, due_date, created_at, updated_at FROM todos
+const patchTodo = `-- name: PatchTodo :one
+UPDATE todos
+SET title = COALESCE($1, title),
+    description = COALESCE($2, description),
+    due_date = COALESCE($3, due_date),
+    recurrence = CASE WHEN $4::boolean THEN NULL ELSE COALESCE($5::text, recurrence) END,
+    recurrence_timezone = CASE WHEN $4::boolean THEN NULL
+        ELSE COALESCE($6::text, recurrence_timezone) END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $7 AND owner_id = $8::int AND (COALESCE(cardinality($9::int[]), 0) = 0 OR version = ANY($9::int[]))
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
 `
 
-func (q *Queries) GetTodos(ctx context.Context) ([]Todo, error) {
-	rows, err := q.db.QueryContext(ctx, getTodos)
+type PatchTodoParams struct {
+	Title              sql.NullString
+	Description        sql.NullString
+	DueDate            sql.NullTime
+	RemoveRecurrence   bool
+	Recurrence         sql.NullString
+	RecurrenceTimezone sql.NullString
+	ID                 int32
+	OwnerID            int32
+	Versions           []int32
+}
+
+func (q *Queries) PatchTodo(ctx context.Context, arg PatchTodoParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, patchTodo,
+		arg.Title,
+		arg.Description,
+		arg.DueDate,
+		arg.RemoveRecurrence,
+		arg.Recurrence,
+		arg.RecurrenceTimezone,
+		arg.ID,
+		arg.OwnerID,
+		pq.Array(arg.Versions),
+	)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
+
+const searchTodos = `-- name: SearchTodos :many
+SELECT todos.id, todos.title, todos.description, todos.due_date, todos.created_at, todos.updated_at, todos.status, todos.completed_at, todos.version, todos.owner_id, todos.project_id, todos.parent_id, todos.recurrence, todos.series_id, todos.recurred, todos.recurrence_timezone,
+    ts_rank(search_vector, search_query)::real AS rank,
+    ts_headline('simple', translate(todos.title, chr(2) || chr(3), ''), search_query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", HighlightAll=true')::text AS title_highlight,
+    ts_headline('simple', translate(todos.description, chr(2) || chr(3), ''), search_query,
+        'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", MaxFragments=3')::text AS description_highlight
+FROM todos
+CROSS JOIN LATERAL websearch_to_tsquery('simple', $1::text) AS search_query
+CROSS JOIN LATERAL (
+    SELECT setweight(to_tsvector('simple', todos.title), 'A') || setweight(to_tsvector('simple', todos.description), 'B') AS search_vector
+) AS document
+WHERE todos.owner_id = $2::int
+  AND (setweight(to_tsvector('simple', todos.title), 'A') || setweight(to_tsvector('simple', todos.description), 'B')) @@ search_query
+ORDER BY rank DESC, todos.id ASC
+LIMIT $3::int
+`
+
+type SearchTodosParams struct {
+	Query    string
+	OwnerID  int32
+	RowLimit int32
+}
+
+type SearchTodosRow struct {
+	Todo                 Todo
+	Rank                 float32
+	TitleHighlight       string
+	DescriptionHighlight string
+}
+
+func (q *Queries) SearchTodos(ctx context.Context, arg SearchTodosParams) ([]SearchTodosRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchTodos, arg.Query, arg.OwnerID, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Todo
+	var items []SearchTodosRow
 	for rows.Next() {
-		var i Todo
+		var i SearchTodosRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.DueDate,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Todo.ID,
+			&i.Todo.Title,
+			&i.Todo.Description,
+			&i.Todo.DueDate,
+			&i.Todo.CreatedAt,
+			&i.Todo.UpdatedAt,
+			&i.Todo.Status,
+			&i.Todo.CompletedAt,
+			&i.Todo.Version,
+			&i.Todo.OwnerID,
+			&i.Todo.ProjectID,
+			&i.Todo.ParentID,
+			&i.Todo.Recurrence,
+			&i.Todo.SeriesID,
+			&i.Todo.Recurred,
+			&i.Todo.RecurrenceTimezone,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.DescriptionHighlight,
 		); err != nil {
 			return nil, err
 		}
@@ -108,18 +336,61 @@ func (q *Queries) GetTodos(ctx context.Context) ([]Todo, error) {
 	return items, nil
 }
 
+const touchTodo = `-- name: TouchTodo :one
+UPDATE todos
+SET updated_at = NOW(), version = version + 1
+WHERE id = $1::int AND owner_id = $2::int
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
+`
+
+type TouchTodoParams struct {
+	ID      int32
+	OwnerID int32
+}
+
+func (q *Queries) TouchTodo(ctx context.Context, arg TouchTodoParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, touchTodo, arg.ID, arg.OwnerID)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
+
 const updateTodo = `-- name: UpdateTodo :one
 UPDATE todos
-SET title = $2, description = $3, due_date = $4, updated_at = NOW()
-WHERE id = $1
-RETURNING id, title, description, due_date, created_at, updated_at
+SET title = $2, description = $3, due_date = $4, recurrence = $5::text,
+    recurrence_timezone = CASE WHEN $5::text IS NULL THEN NULL
+        ELSE COALESCE($6::text, recurrence_timezone) END,
+    updated_at = NOW(), version = version + 1
+WHERE id = $1 AND owner_id = $7::int AND (COALESCE(cardinality($8::int[]), 0) = 0 OR version = ANY($8::int[]))
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
 `
 
 type UpdateTodoParams struct {
-	ID          int32
-	Title       string
-	Description string
-	DueDate     string
+	ID                 int32
+	Title              string
+	Description        string
+	DueDate            time.Time
+	Recurrence         sql.NullString
+	RecurrenceTimezone sql.NullString
+	OwnerID            int32
+	Versions           []int32
 }
 
 func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, error) {
@@ -128,6 +399,59 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		arg.Title,
 		arg.Description,
 		arg.DueDate,
+		arg.Recurrence,
+		arg.RecurrenceTimezone,
+		arg.OwnerID,
+		pq.Array(arg.Versions),
+	)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.DueDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
+	)
+	return i, err
+}
+
+const updateTodoStatus = `-- name: UpdateTodoStatus :one
+UPDATE todos
+SET status = $1::todo_status,
+    completed_at = CASE WHEN $1::todo_status = 'done' THEN NOW() END,
+    updated_at = NOW(),
+    version = version + 1
+WHERE id = $2 AND owner_id = $3::int AND status = $4::todo_status
+  AND (COALESCE(cardinality($5::int[]), 0) = 0 OR version = ANY($5::int[]))
+RETURNING id, title, description, due_date, created_at, updated_at, status, completed_at, version, owner_id, project_id, parent_id, recurrence, series_id, recurred, recurrence_timezone
+`
+
+type UpdateTodoStatusParams struct {
+	Status        TodoStatus
+	ID            int32
+	OwnerID       int32
+	CurrentStatus TodoStatus
+	Versions      []int32
+}
+
+func (q *Queries) UpdateTodoStatus(ctx context.Context, arg UpdateTodoStatusParams) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, updateTodoStatus,
+		arg.Status,
+		arg.ID,
+		arg.OwnerID,
+		arg.CurrentStatus,
+		pq.Array(arg.Versions),
 	)
 	var i Todo
 	err := row.Scan(
@@ -137,6 +461,16 @@ func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) (Todo, e
 		&i.DueDate,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.CompletedAt,
+		&i.Version,
+		&i.OwnerID,
+		&i.ProjectID,
+		&i.ParentID,
+		&i.Recurrence,
+		&i.SeriesID,
+		&i.Recurred,
+		&i.RecurrenceTimezone,
 	)
 	return i, err
 }
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// beginner is implemented by the connections Queries can start transactions on, e.g. *sql.DB.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// InTx runs fn with a Repository whose queries are made in a single transaction,
+// committing it if fn succeeds and rolling it back otherwise.
+// Queries that are already bound to a transaction run fn in it.
+func (q *Queries) InTx(ctx context.Context, fn func(repo Repository) error) error {
+	db, ok := q.db.(beginner)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: webhooks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+    SELECT webhook_deliveries.id FROM webhook_deliveries
+    JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+    WHERE webhook_deliveries.delivered_at IS NULL AND webhook_deliveries.failed_at IS NULL
+      AND webhook_deliveries.next_attempt_at <= $2::timestamptz
+      AND webhooks.active
+    ORDER BY webhook_deliveries.id
+    LIMIT $3::int
+    FOR UPDATE OF webhook_deliveries SKIP LOCKED
+)
+UPDATE webhook_deliveries
+SET next_attempt_at = $1::timestamptz, attempts = webhook_deliveries.attempts + 1
+FROM due, webhooks
+WHERE webhook_deliveries.id = due.id AND webhooks.id = webhook_deliveries.webhook_id
+RETURNING webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload,
+    webhook_deliveries.attempts, webhooks.url, webhooks.secret
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LockedUntil time.Time
+	Now         time.Time
+	RowLimit    int32
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID        int32
+	WebhookID int32
+	Event     string
+	Payload   json.RawMessage
+	Attempts  int32
+	URL       string
+	Secret    string
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LockedUntil, arg.Now, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDeliveriesRow
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.URL,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, url, secret, events, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, url, secret, events, active, consecutive_failures, disabled_at, created_at, updated_at
+`
+
+type CreateWebhookParams struct {
+	UserID int32
+	URL    string
+	Secret string
+	Events []string
+	Active bool
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, createWebhook,
+		arg.UserID,
+		arg.URL,
+		arg.Secret,
+		pq.Array(arg.Events),
+		arg.Active,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, url, secret, events, active, consecutive_failures, disabled_at, created_at, updated_at
+`
+
+type DeleteWebhookParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, deleteWebhook, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (webhook_id, event, payload)
+SELECT webhooks.id, $1::text, $2::jsonb
+FROM webhooks
+WHERE webhooks.user_id = $3::int AND webhooks.active AND $1::text = ANY(webhooks.events)
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	Event   string
+	Payload json.RawMessage
+	UserID  int32
+}
+
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueWebhookDeliveries, arg.Event, arg.Payload, arg.UserID)
+	return err
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, user_id, url, secret, events, active, consecutive_failures, disabled_at, created_at, updated_at FROM webhooks
+WHERE id = $1 AND user_id = $2
+`
+
+type GetWebhookParams struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT webhook_deliveries.id, webhook_deliveries.webhook_id, webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.response_status, webhook_deliveries.last_error, webhook_deliveries.delivered_at, webhook_deliveries.failed_at, webhook_deliveries.created_at FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.webhook_id = $1::int AND webhooks.user_id = $2::int
+ORDER BY webhook_deliveries.id DESC
+LIMIT $3::int
+`
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID int32
+	UserID    int32
+	RowLimit  int32
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries, arg.WebhookID, arg.UserID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseStatus,
+			&i.LastError,
+			&i.DeliveredAt,
+			&i.FailedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, user_id, url, secret, events, active, consecutive_failures, disabled_at, created_at, updated_at FROM webhooks
+WHERE user_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListWebhooks(ctx context.Context, userID int32) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhooks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.URL,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.Active,
+			&i.ConsecutiveFailures,
+			&i.DisabledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryFailure = `-- name: RecordWebhookDeliveryFailure :one
+WITH delivery AS (
+    UPDATE webhook_deliveries
+    SET response_status = $2::int, last_error = $3::text,
+        next_attempt_at = COALESCE($4::timestamptz, webhook_deliveries.next_attempt_at),
+        failed_at = CASE WHEN $4::timestamptz IS NULL THEN NOW() END
+    WHERE webhook_deliveries.id = $5::int
+    RETURNING webhook_deliveries.webhook_id
+)
+UPDATE webhooks
+SET consecutive_failures = webhooks.consecutive_failures + 1,
+    active = webhooks.active AND webhooks.consecutive_failures + 1 < $1::int,
+    disabled_at = CASE
+        WHEN webhooks.active AND webhooks.consecutive_failures + 1 >= $1::int THEN NOW()
+        ELSE webhooks.disabled_at
+    END
+FROM delivery
+WHERE webhooks.id = delivery.webhook_id
+RETURNING webhooks.id, webhooks.user_id, webhooks.url, webhooks.secret, webhooks.events, webhooks.active, webhooks.consecutive_failures, webhooks.disabled_at, webhooks.created_at, webhooks.updated_at
+`
+
+type RecordWebhookDeliveryFailureParams struct {
+	MaxFailures    int32
+	ResponseStatus sql.NullInt32
+	LastError      string
+	RetryAt        sql.NullTime
+	ID             int32
+}
+
+func (q *Queries) RecordWebhookDeliveryFailure(ctx context.Context, arg RecordWebhookDeliveryFailureParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, recordWebhookDeliveryFailure,
+		arg.MaxFailures,
+		arg.ResponseStatus,
+		arg.LastError,
+		arg.RetryAt,
+		arg.ID,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const recordWebhookDeliverySuccess = `-- name: RecordWebhookDeliverySuccess :exec
+WITH delivery AS (
+    UPDATE webhook_deliveries
+    SET delivered_at = NOW(), response_status = $1::int, last_error = NULL
+    WHERE webhook_deliveries.id = $2::int
+    RETURNING webhook_deliveries.webhook_id
+)
+UPDATE webhooks
+SET consecutive_failures = 0
+FROM delivery
+WHERE webhooks.id = delivery.webhook_id
+`
+
+type RecordWebhookDeliverySuccessParams struct {
+	ResponseStatus int32
+	ID             int32
+}
+
+func (q *Queries) RecordWebhookDeliverySuccess(ctx context.Context, arg RecordWebhookDeliverySuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordWebhookDeliverySuccess, arg.ResponseStatus, arg.ID)
+	return err
+}
+
+const updateWebhook = `-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = $1::text, events = $2::text[], active = $3::boolean,
+    consecutive_failures = CASE WHEN $3::boolean THEN 0 ELSE webhooks.consecutive_failures END,
+    disabled_at = CASE WHEN $3::boolean THEN NULL ELSE webhooks.disabled_at END,
+    updated_at = NOW()
+WHERE id = $4::int AND user_id = $5::int
+RETURNING id, user_id, url, secret, events, active, consecutive_failures, disabled_at, created_at, updated_at
+`
+
+type UpdateWebhookParams struct {
+	URL    string
+	Events []string
+	Active bool
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhook,
+		arg.URL,
+		pq.Array(arg.Events),
+		arg.Active,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.URL,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.Active,
+		&i.ConsecutiveFailures,
+		&i.DisabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
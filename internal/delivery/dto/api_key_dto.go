@@ -0,0 +1,31 @@
+package dto
+
+// APIKeyInputDto represents the input data required to create API keys, with validation rules.
+type APIKeyInputDto struct {
+	Name      string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=read write admin"`
+	ExpiresAt *string  `json:"expires_at" validate:"omitempty,rfc3339"`
+}
+
+// APIKeyResponseDto represents the response data for an API key. The key itself is never returned after creation,
+// the prefix identifies it instead.
+type APIKeyResponseDto struct {
+	ID         int32    `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at"`
+	LastUsedAt *string  `json:"last_used_at"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// APIKeyCreatedDto represents a newly created API key together with the key, which is shown only once.
+type APIKeyCreatedDto struct {
+	APIKeyResponseDto
+	Key string `json:"key"`
+}
+
+// APIKeysDto represents the list of active API keys of a user.
+type APIKeysDto struct {
+	Items []APIKeyResponseDto `json:"items"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// CredentialsDto represents the email and password a user registers or logs in with, with validation rules.
+type CredentialsDto struct {
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,min=8,maxbytes=72"`
+}
+
+// RefreshTokenDto represents the refresh token exchanged for a new pair of tokens.
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// TodoBlockerDto represents a todo that has to be finished before the todo it blocks can be completed.
+type TodoBlockerDto struct {
+	BlockerID int32 `json:"blocker_id" validate:"required,min=1"`
+}
+
+// TodosOrderDto represents todos ordered so that each todo comes after the todos blocking it.
+type TodosOrderDto struct {
+	Items []TodoResponseDto `json:"items"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// ProblemDto represents an RFC 7807 problem details document returned for failed requests.
+type ProblemDto struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Errors   []FieldErrorDto `json:"errors,omitempty"`
+}
+
+// FieldErrorDto describes a single invalid request field: the rule it violates and a machine-readable code.
+type FieldErrorDto struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Code  string `json:"code"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+// ProjectInputDto represents the input data required to create or update projects, with validation rules.
+// When color is empty, the default project color is used.
+type ProjectInputDto struct {
+	Name      string `json:"name" validate:"required,min=1,max=100"`
+	Color     string `json:"color" validate:"omitempty,hexcolor"`
+	Archived  bool   `json:"archived"`
+	SortOrder int32  `json:"sort_order"`
+}
+
+// ProjectResponseDto represents the response data for a project.
+type ProjectResponseDto struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	Archived  bool   `json:"archived"`
+	SortOrder int32  `json:"sort_order"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ProjectsDto represents the list of projects of a user, ordered by their sort order.
+type ProjectsDto struct {
+	Items []ProjectResponseDto `json:"items"`
+}
+
+// ProjectDeleteQueryDto represents the parameters of a project delete request, with validation rules.
+// Todos of the project are either deleted with it or moved to the target project, or out of any project when it is nil.
+type ProjectDeleteQueryDto struct {
+	Todos           string `query:"todos" validate:"oneof=delete move"`
+	TargetProjectID *int32 `query:"target_project_id" validate:"omitempty,min=1"`
+}
+
+// TodoMoveDto represents the project a todo is moved to, or nil to move it out of any project.
+type TodoMoveDto struct {
+	ProjectID *int32 `json:"project_id" validate:"omitempty,min=1"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// ReadinessDto represents the readiness of the application to serve requests and the state of its database.
+type ReadinessDto struct {
+	Status           string         `json:"status"`
+	Database         string         `json:"database"`
+	MigrationVersion *int64         `json:"migration_version"`
+	Pool             DBPoolStatsDto `json:"pool"`
+}
+
+// DBPoolStatsDto represents the statistics of the database connection pool.
+type DBPoolStatsDto struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+// ReminderInputDto represents the input data required to create reminders, with validation rules.
+// A reminder fires either at RemindAt or MinutesBefore the due date of the todo, following its changes.
+// Target is the URL of webhook reminders, email reminders always go to the email of the user.
+type ReminderInputDto struct {
+	RemindAt      *string `json:"remind_at" validate:"required_without=MinutesBefore,excluded_with=MinutesBefore,omitnil,rfc3339"`
+	MinutesBefore *int32  `json:"minutes_before" validate:"omitnil,min=0,max=525600"`
+	Channel       string  `json:"channel" validate:"required,oneof=email webhook log"`
+	Target        *string `json:"target" validate:"required_if=Channel webhook,excluded_unless=Channel webhook,omitnil,max=2048"`
+}
+
+// ReminderResponseDto represents the response data for a reminder.
+// Status is pending until the reminder is sent, or failed once its delivery attempts are exhausted.
+type ReminderResponseDto struct {
+	ID            int32   `json:"id"`
+	TodoID        int32   `json:"todo_id"`
+	RemindAt      *string `json:"remind_at"`
+	MinutesBefore *int32  `json:"minutes_before"`
+	Channel       string  `json:"channel"`
+	Target        *string `json:"target"`
+	Status        string  `json:"status"`
+	Attempts      int32   `json:"attempts"`
+	LastError     *string `json:"last_error"`
+	SentAt        *string `json:"sent_at"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+// ReminderCreatedDto represents a newly created reminder. Webhook reminders come with the secret their requests are signed with,
+// which is shown only once.
+type ReminderCreatedDto struct {
+	ReminderResponseDto
+	Secret *string `json:"secret,omitempty"`
+}
+
+// RemindersDto represents the list of reminders of a todo.
+type RemindersDto struct {
+	Items []ReminderResponseDto `json:"items"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// TodoParentDto represents the todo a todo becomes a subtask of, or nil to make it a top-level todo.
+type TodoParentDto struct {
+	ParentID *int32 `json:"parent_id" validate:"omitempty,min=1"`
+}
+
+// TodoNodeDto represents a todo in a tree of subtasks, with its own subtasks ordered by creation time.
+type TodoNodeDto struct {
+	TodoResponseDto
+	Subtasks []TodoNodeDto `json:"subtasks"`
+}
+
+// SubtasksDto represents the tree of subtasks of a todo.
+type SubtasksDto struct {
+	Items []TodoNodeDto `json:"items"`
+}
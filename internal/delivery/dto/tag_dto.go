@@ -0,0 +1,13 @@
+package dto
+
+// TagResponseDto represents a tag of a user with the number of todos labelled with it.
+type TagResponseDto struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	TodoCount int32  `json:"todo_count"`
+}
+
+// TagsDto represents the list of tags of a user, ordered by name.
+type TagsDto struct {
+	Items []TagResponseDto `json:"items"`
+}
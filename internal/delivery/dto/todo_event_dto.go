@@ -0,0 +1,11 @@
+package dto
+
+import "encoding/json"
+
+// TodoEventDto represents an event of the change stream of todos. ID grows with every event and is sent as the SSE event ID,
+// so clients can resume the stream after it. Data is the same payload webhooks receive about the event.
+type TodoEventDto struct {
+	ID    int64           `json:"id"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
@@ -1,8 +1,14 @@
 package dto
 
 // TodoInputDto represents the input data required to create or update todos, with validation rules.
+// The project is only set on creation, todos are moved between projects with TodoMoveDto.
+// Tags replace the current tags of the todo, tags the user doesn't have yet are created.
+// Recurrence is an RFC 5545 RRULE repeating the todo from its due date, a todo without it doesn't repeat.
 type TodoInputDto struct {
-	Title       string `json:"title" validate:"required,min=1"`
-	Description string `json:"description" validate:"required,min=1"`
-	DueDate     string `json:"due_date" validate:"required,rfc3339"`
+	Title       string   `json:"title" validate:"required,min=1"`
+	Description string   `json:"description" validate:"required,min=1"`
+	DueDate     string   `json:"due_date" validate:"required,rfc3339"`
+	ProjectID   *int32   `json:"project_id" validate:"omitempty,min=1"`
+	Tags        []string `json:"tags" validate:"max=20,dive,min=1,max=50,excludesall=0x2C"`
+	Recurrence  *string  `json:"recurrence" validate:"omitnil,max=500,rrule"`
 }
@@ -0,0 +1,13 @@
+package dto
+
+// TodoPatchDto represents a partial todo update. Nil fields are left unchanged.
+// Tags replace the current tags of the todo like in TodoInputDto, an empty list removes them.
+// RemoveRecurrence stops the todo from repeating, it is set when the patch removes recurrence.
+type TodoPatchDto struct {
+	Title            *string   `json:"title" validate:"omitnil,min=1"`
+	Description      *string   `json:"description" validate:"omitnil,min=1"`
+	DueDate          *string   `json:"due_date" validate:"omitnil,rfc3339"`
+	Tags             *[]string `json:"tags" validate:"omitnil,max=20,dive,min=1,max=50,excludesall=0x2C"`
+	Recurrence       *string   `json:"recurrence" validate:"omitnil,max=500,rrule"`
+	RemoveRecurrence bool      `json:"-"`
+}
@@ -1,11 +1,25 @@
 package dto
 
 // TodoResponseDto represents the response structure.
+// Progress is the percentage of done subtasks, not counting cancelled ones, or nil when the todo has none.
+// BlockedBy lists the IDs of the todos blocking the todo, Blocked tells whether any of them is still open or in progress.
+// SeriesID links the occurrences of a recurring todo, it is the ID of the first one and nil until the todo first repeats.
 type TodoResponseDto struct {
-	ID          int32  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	DueDate     string `json:"due_date"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          int32    `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	DueDate     string   `json:"due_date"`
+	Status      string   `json:"status"`
+	ProjectID   *int32   `json:"project_id"`
+	ParentID    *int32   `json:"parent_id"`
+	Tags        []string `json:"tags"`
+	Progress    *int32   `json:"progress"`
+	Blocked     bool     `json:"blocked"`
+	BlockedBy   []int32  `json:"blocked_by"`
+	Recurrence  *string  `json:"recurrence"`
+	SeriesID    *int32   `json:"series_id"`
+	CompletedAt *string  `json:"completed_at"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+	Version     int32    `json:"version"`
 }
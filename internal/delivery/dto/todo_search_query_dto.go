@@ -0,0 +1,7 @@
+package dto
+
+// TodoSearchQueryDto represents the parameters of a full-text todos search request, with validation rules.
+type TodoSearchQueryDto struct {
+	Query string `query:"q" validate:"required,max=256"`
+	Limit int    `query:"limit" validate:"min=1,max=100"`
+}
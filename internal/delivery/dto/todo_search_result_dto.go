@@ -0,0 +1,19 @@
+package dto
+
+// TodoSearchResultsDto represents the ranked results of a full-text todos search.
+type TodoSearchResultsDto struct {
+	Items []TodoSearchResultDto `json:"items"`
+}
+
+// TodoSearchResultDto represents a single search hit with its rank and highlighted snippets.
+type TodoSearchResultDto struct {
+	Todo       TodoResponseDto   `json:"todo"`
+	Rank       float32           `json:"rank"`
+	Highlights TodoHighlightsDto `json:"highlights"`
+}
+
+// TodoHighlightsDto holds HTML-escaped snippets of the matched fields with matches wrapped in <mark> tags.
+type TodoHighlightsDto struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
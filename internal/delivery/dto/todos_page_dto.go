@@ -0,0 +1,7 @@
+package dto
+
+// TodosPageDto represents a single page of todos with the cursor of the next page, which is null on the last page.
+type TodosPageDto struct {
+	Items      []TodoResponseDto `json:"items"`
+	NextCursor *string           `json:"next_cursor"`
+}
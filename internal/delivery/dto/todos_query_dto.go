@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// TodosQueryDto represents the pagination, filtering and sorting parameters of a todos list request, with validation rules.
+// TagMode tells whether todos must have all or any of the Tags.
+// ProjectID comes from the URL of project todos requests rather than from the query.
+type TodosQueryDto struct {
+	Limit       int        `query:"limit" validate:"min=1,max=100"`
+	Cursor      string     `query:"cursor" validate:"omitempty,base64rawurl"`
+	Sort        []string   `query:"sort" validate:"min=1,max=2,dive,oneof=due_date -due_date created_at -created_at updated_at -updated_at"`
+	Statuses    []string   `query:"status" validate:"dive,oneof=open in_progress done cancelled"`
+	Tags        []string   `query:"tag" validate:"max=20,dive,min=1,max=50"`
+	TagMode     string     `query:"tag_mode" validate:"oneof=all any"`
+	DueFrom     *time.Time `query:"due_from"`
+	DueTo       *time.Time `query:"due_to"`
+	CreatedFrom *time.Time `query:"created_from"`
+	CreatedTo   *time.Time `query:"created_to"`
+	UpdatedFrom *time.Time `query:"updated_from"`
+	UpdatedTo   *time.Time `query:"updated_to"`
+	ProjectID   *int32     `query:"-"`
+}
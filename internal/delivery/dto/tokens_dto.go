@@ -0,0 +1,16 @@
+package dto
+
+// TokensDto represents the access and refresh tokens issued to an authenticated user.
+type TokensDto struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UserResponseDto represents the response data for a user.
+type UserResponseDto struct {
+	ID        int32  `json:"id"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+// WebhookInputDto represents the input data required to create and update webhooks, with validation rules.
+// Events lists the todo events the webhook is subscribed to. Active defaults to true, setting it pauses or re-enables the webhook.
+type WebhookInputDto struct {
+	URL    string   `json:"url" validate:"required,max=2048,http_url"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=todo.created todo.updated todo.completed todo.deleted"`
+	Active *bool    `json:"active"`
+}
+
+// WebhookResponseDto represents the response data for a webhook. The signing secret is never returned after creation.
+// DisabledAt is set when the webhook was disabled after repeated delivery failures.
+type WebhookResponseDto struct {
+	ID                  int32    `json:"id"`
+	URL                 string   `json:"url"`
+	Events              []string `json:"events"`
+	Active              bool     `json:"active"`
+	ConsecutiveFailures int32    `json:"consecutive_failures"`
+	DisabledAt          *string  `json:"disabled_at"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
+}
+
+// WebhookCreatedDto represents a newly created webhook together with its signing secret, which is shown only once.
+type WebhookCreatedDto struct {
+	WebhookResponseDto
+	Secret string `json:"secret"`
+}
+
+// WebhooksDto represents the list of webhooks of a user.
+type WebhooksDto struct {
+	Items []WebhookResponseDto `json:"items"`
+}
+
+// WebhookDeliveryDto represents a delivery of an event to a webhook.
+// Status is pending until the receiver accepts the event, or failed once its delivery attempts are exhausted.
+type WebhookDeliveryDto struct {
+	ID             int32   `json:"id"`
+	Event          string  `json:"event"`
+	Status         string  `json:"status"`
+	Attempts       int32   `json:"attempts"`
+	ResponseStatus *int32  `json:"response_status"`
+	LastError      *string `json:"last_error"`
+	NextAttemptAt  *string `json:"next_attempt_at"`
+	DeliveredAt    *string `json:"delivered_at"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+// WebhookDeliveriesDto represents the latest deliveries of a webhook, newest first.
+type WebhookDeliveriesDto struct {
+	Items []WebhookDeliveryDto `json:"items"`
+}
+
+// WebhookEventDto represents the payload posted to webhooks about a todo event.
+type WebhookEventDto struct {
+	Event      string          `json:"event"`
+	OccurredAt string          `json:"occurred_at"`
+	Data       TodoResponseDto `json:"data"`
+}
@@ -2,20 +2,118 @@ package delivery
 
 type contextKey string
 
-// Defines context keys and error messages for todos operations.
+// Defines context keys and error messages for todos, projects, reminders, webhooks, auth and API keys operations.
 const (
-	TodoInputKey contextKey = "todoInput"
-	TodoIDKey    contextKey = "todoID"
-
-	ErrInvalidInput  = "invalid todo input body(fields title, description and due_date are required and can't be empty, due_date field must be a string in RFC3339 format)"
-	ErrInvalidTodoID = "invalid todo id"
-
-	ErrCreatingTodo = "error creating todo"
-	ErrGettingTodos = "error getting todos"
-	ErrTodoNotFound = "todo with this id not found"
-	ErrGettingTodo  = "error getting todo"
-	ErrUpdatingTodo = "error updating todo"
-	ErrDeletingTodo = "error deleting todo"
+	TodoInputKey       contextKey = "todoInput"
+	TodoIDKey          contextKey = "todoID"
+	TimezoneKey        contextKey = "timezone"
+	TodosQueryKey      contextKey = "todosQuery"
+	TodoSearchQueryKey contextKey = "todoSearchQuery"
+	TodoPatchKey       contextKey = "todoPatch"
+	IfMatchKey         contextKey = "ifMatch"
+	CredentialsKey     contextKey = "credentials"
+	RefreshTokenKey    contextKey = "refreshToken"
+	UserIDKey          contextKey = "userID"
+	ScopesKey          contextKey = "scopes"
+	APIKeyInputKey     contextKey = "apiKeyInput"
+	APIKeyIDKey        contextKey = "apiKeyID"
+	ProjectInputKey    contextKey = "projectInput"
+	ProjectIDKey       contextKey = "projectID"
+	ProjectDeleteKey   contextKey = "projectDelete"
+	TodoMoveKey        contextKey = "todoMove"
+	TodoParentKey      contextKey = "todoParent"
+	TodoBlockerKey     contextKey = "todoBlocker"
+	BlockerIDKey       contextKey = "blockerID"
+	IncludeArchivedKey contextKey = "includeArchived"
+	ReminderInputKey   contextKey = "reminderInput"
+	ReminderIDKey      contextKey = "reminderID"
+	WebhookInputKey    contextKey = "webhookInput"
+	WebhookIDKey       contextKey = "webhookID"
+	LastEventIDKey     contextKey = "lastEventID"
+
+	ErrInvalidInput         = "invalid todo input body(fields title, description and due_date are required and can't be empty, due_date field must be a string in RFC3339 format, project_id field must be a positive integer, tags field must list up to 20 names of 1 to 50 characters without commas)"
+	ErrInvalidTodoID        = "invalid todo id"
+	ErrInvalidTZ            = "invalid timezone(must be an IANA time zone name, e.g. Europe/Moscow)"
+	ErrInvalidTodosQuery    = "invalid todos query(limit must be between 1 and 100, sort must list up to two distinct fields of due_date, created_at, updated_at optionally prefixed with '-', status must be one of open, in_progress, done, cancelled, tag must list up to 20 names of 1 to 50 characters, tag_mode must be one of all, any, date range bounds must be in RFC3339 format)"
+	ErrInvalidPatch         = "invalid todo patch(only title, description, due_date, tags and recurrence can be changed, title, description and due_date can't be empty or removed, due_date field must be a string in RFC3339 format, tags field must list up to 20 names of 1 to 50 characters without commas, recurrence must be a valid RRULE, JSON patch supports add, replace and remove operations only)"
+	ErrUnsupportedPatchType = "unsupported patch content type(use application/merge-patch+json or application/json-patch+json)"
+	ErrInvalidTodoMove      = "invalid todo move body(project_id field must be a positive integer or null)"
+	ErrInvalidTodoParent    = "invalid todo parent body(parent_id field must be a positive integer or null)"
+	ErrInvalidTodoBlocker   = "invalid todo blocker body(field blocker_id is required and must be a positive integer)"
+	ErrInvalidBlockerID     = "invalid blocker todo id"
+	ErrInvalidSearchQuery   = "invalid search query(q is required and can't be longer than 256 characters, limit must be between 1 and 100)"
+
+	ErrCreatingTodo   = "error creating todo"
+	ErrGettingTodos   = "error getting todos"
+	ErrSearchingTodos = "error searching todos"
+	ErrGettingTodo    = "error getting todo"
+	ErrUpdatingTodo   = "error updating todo"
+	ErrPatchingTodo   = "error patching todo"
+	ErrDeletingTodo   = "error deleting todo"
+
+	ErrMovingTodo          = "error moving todo"
+	ErrGettingSubtasks     = "error getting subtasks"
+	ErrCreatingSubtask     = "error creating subtask"
+	ErrSettingTodoParent   = "error setting todo parent"
+	ErrAddingTodoBlocker   = "error adding todo blocker"
+	ErrRemovingTodoBlocker = "error removing todo blocker"
+	ErrChangingTodoStatus  = "error changing todo status"
+	ErrIfMatchFailed       = "If-Match header doesn't list any todo version"
+
+	ErrInvalidCredentialsInput = "invalid credentials body(fields email and password are required, email must be a valid address, password must be at least 8 characters and at most 72 bytes long)"
+	ErrInvalidRefreshInput     = "invalid refresh body(field refresh_token is required)"
+	ErrMissingAccessToken      = "missing or malformed Authorization header(use Bearer access token or API key)"
+	ErrInvalidAPIKeyInput      = "invalid api key body(field name is required and can't be longer than 100 characters, scopes must list at least one of read, write, admin, expires_at field must be a string in RFC3339 format)"
+	ErrInvalidAPIKeyID         = "invalid api key id"
+	ErrInsufficientScope       = "api key doesn't have the scope required for this request"
+
+	ErrRegisteringUser  = "error registering user"
+	ErrLoggingIn        = "error logging in"
+	ErrRefreshingTokens = "error refreshing tokens"
+	ErrAuthenticating   = "error authenticating request"
+
+	ErrCreatingAPIKey = "error creating api key"
+	ErrGettingAPIKeys = "error getting api keys"
+	ErrRevokingAPIKey = "error revoking api key"
+
+	ErrInvalidProjectInput  = "invalid project input body(field name is required and can't be longer than 100 characters, color field must be a hex color, e.g. #ff8800)"
+	ErrInvalidProjectID     = "invalid project id"
+	ErrInvalidProjectDelete = "invalid project delete query(todos must be one of delete, move, target_project_id must be a positive integer and can only be set when todos are moved)"
+	ErrInvalidArchivedQuery = "invalid archived query parameter(must be a boolean)"
+
+	ErrCreatingProject   = "error creating project"
+	ErrGettingProjects   = "error getting projects"
+	ErrGettingProject    = "error getting project"
+	ErrUpdatingProject   = "error updating project"
+	ErrDeletingProject   = "error deleting project"
+	ErrGettingTodosOrder = "error getting todos order"
+
+	ErrGettingTags = "error getting tags"
+
+	ErrInvalidReminderInput = "invalid reminder input body(exactly one of remind_at and minutes_before is required, remind_at field must be a string in RFC3339 format, minutes_before field must be between 0 and 525600, channel must be one of email, webhook, log, target is required for webhook reminders and not allowed for other channels)"
+	ErrInvalidReminderID    = "invalid reminder id"
+
+	ErrCreatingReminder = "error creating reminder"
+	ErrGettingReminders = "error getting reminders"
+	ErrDeletingReminder = "error deleting reminder"
+
+	ErrInvalidWebhookInput = "invalid webhook input body(field url is required and must be an http or https URL of up to 2048 characters, events must list at least one of todo.created, todo.updated, todo.completed, todo.deleted, active field must be a boolean)"
+	ErrInvalidWebhookID    = "invalid webhook id"
+
+	ErrCreatingWebhook          = "error creating webhook"
+	ErrGettingWebhooks          = "error getting webhooks"
+	ErrGettingWebhook           = "error getting webhook"
+	ErrUpdatingWebhook          = "error updating webhook"
+	ErrDeletingWebhook          = "error deleting webhook"
+	ErrGettingWebhookDeliveries = "error getting webhook deliveries"
+
+	ErrInvalidLastEventID = "invalid Last-Event-ID header(must be a non-negative integer)"
+	ErrStreamingEvents    = "error streaming todo events"
+
+	ErrNotReady = "application is not ready"
+
+	ErrRequestTimeout      = "request timed out"
+	ErrClientClosedRequest = "client closed request"
 
 	ErrMarshalingJSON = "failed to marshal JSON response"
 )
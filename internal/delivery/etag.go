@@ -0,0 +1,79 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ETag returns the strong entity tag of the given todo version rendered in loc, like "3@Europe/Moscow",
+// or like "3" when no timezone was requested.
+func ETag(version int32, loc *time.Location) string {
+	tag := strconv.Itoa(int(version))
+	if loc != nil {
+		tag += "@" + loc.String()
+	}
+
+	return `"` + tag + `"`
+}
+
+// SetETag sets the ETag header of the response to the entity tag of the given todo version rendered in loc.
+// The timezone may come from the X-Timezone header and the todo depends on the user the Authorization header identifies,
+// so the response varies on both.
+func SetETag(w http.ResponseWriter, version int32, loc *time.Location) {
+	w.Header().Set("ETag", ETag(version, loc))
+	w.Header().Add("Vary", "Authorization, X-Timezone")
+}
+
+// ParseIfMatch parses the value of an If-Match header into the list of acceptable todo versions.
+// An empty or "*" header yields nil, which means any version matches.
+// Weak and malformed entity tags can never match, so ok is false when the header lists no usable version.
+// The timezone of a tag is ignored, since it changes how the todo is rendered, not the todo itself.
+func ParseIfMatch(header string) (versions []int32, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if version, strong := parseETag(tag); strong {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, len(versions) > 0
+}
+
+// MatchesIfNoneMatch reports whether the value of an If-None-Match header matches the given todo version
+// rendered in loc using the weak comparison.
+func MatchesIfNoneMatch(header string, version int32, loc *time.Location) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == ETag(version, loc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseETag extracts the version from a strong entity tag like "3" or "3@UTC".
+func parseETag(tag string) (int32, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	value, _, _ := strings.Cut(tag[1:len(tag)-1], "@")
+	version, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return int32(version), true
+}
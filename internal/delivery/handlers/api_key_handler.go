@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// APIKeyHandler manages personal API keys of the authenticated user.
+type APIKeyHandler struct {
+	apiKeyService service.APIKeys
+	validator     *validator.Validate
+}
+
+func newAPIKeyHandler(apiKeyService service.APIKeys, validator *validator.Validate) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     validator,
+	}
+}
+
+func (h APIKeyHandler) createAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	apiKeyInput := r.Context().Value(delivery.APIKeyInputKey).(dto.APIKeyInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	apiKey, err := h.apiKeyService.CreateAPIKey(r.Context(), userID, apiKeyInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingAPIKey)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.RespondWithJSON(w, http.StatusCreated, apiKey)
+}
+
+func (h APIKeyHandler) getAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	apiKeys, err := h.apiKeyService.ListAPIKeys(r.Context(), userID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingAPIKeys)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, apiKeys)
+}
+
+func (h APIKeyHandler) revokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	apiKeyID := r.Context().Value(delivery.APIKeyIDKey).(int)
+
+	if err := h.apiKeyService.RevokeAPIKey(r.Context(), userID, apiKeyID); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrRevokingAPIKey)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	apiKey := "tdl_0123456789abcdefghijklmnopqrstuvwxyzABCDEFG"
+	apiKeyHash := sha256.Sum256([]byte(apiKey))
+	readKey := database.APIKey{ID: 3, UserID: 1, Name: "ci", Prefix: "tdl_01234567", Scopes: []string{"read"}, CreatedAt: createdAt}
+
+	tests := []struct {
+		name            string
+		input           io.Reader
+		reqHeaders      map[string]string
+		reqMethod       string
+		reqTarget       string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    interface{}
+		expectedKey     bool
+		mockBehavior    mockBehavior
+	}{
+		// CreateAPIKeyHandler
+		{
+			name:           "CreateAPIKeyHandler Success",
+			input:          bytes.NewBufferString(`{"name": "ci", "scopes": ["write", "read", "write"], "expires_at": "2100-01-01T00:00:00Z"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/api-keys",
+			expectedStatus: http.StatusCreated,
+			expectedKey:    true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.CreateAPIKeyParams) (database.APIKey, error) {
+					return database.APIKey{
+						ID:        3,
+						UserID:    arg.UserID,
+						Name:      arg.Name,
+						Prefix:    arg.Prefix,
+						KeyHash:   arg.KeyHash,
+						Scopes:    arg.Scopes,
+						ExpiresAt: arg.ExpiresAt,
+						CreatedAt: createdAt,
+					}, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "CreateAPIKeyHandler Invalid Scope",
+			input:          bytes.NewBufferString(`{"name": "ci", "scopes": ["delete"]}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/api-keys",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/api-keys", delivery.ErrInvalidAPIKeyInput,
+				dto.FieldErrorDto{Field: "scopes[0]", Rule: "oneof", Code: "not_allowed"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateAPIKeyHandler Expiry In The Past",
+			input:          bytes.NewBufferString(`{"name": "ci", "scopes": ["read"], "expires_at": "2000-01-01T00:00:00Z"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/api-keys",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/api-keys", service.ErrInvalidAPIKeyExpiry.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		// GetAPIKeysHandler
+		{
+			name:           "GetAPIKeysHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/api-keys?tz=Asia/Novosibirsk",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.APIKeysDto{Items: []dto.APIKeyResponseDto{{
+				ID:         3,
+				Name:       "ci",
+				Prefix:     "tdl_01234567",
+				Scopes:     []string{"read"},
+				LastUsedAt: stringPtr("2024-09-05T12:24:16+07:00"),
+				CreatedAt:  "2024-09-05T12:24:16+07:00",
+			}}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				usedKey := readKey
+				usedKey.LastUsedAt = sql.NullTime{Time: createdAt, Valid: true}
+				repo.EXPECT().ListAPIKeys(gomock.Any(), int32(1)).Return([]database.APIKey{usedKey}, nil).Times(1)
+			},
+		},
+		// RevokeAPIKeyHandler
+		{
+			name:           "RevokeAPIKeyHandler Success",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/api-keys/3",
+			expectedStatus: http.StatusNoContent,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().RevokeAPIKey(gomock.Any(), database.RevokeAPIKeyParams{ID: 3, UserID: 1}).Return(readKey, nil).Times(1)
+			},
+		},
+		{
+			name:           "RevokeAPIKeyHandler Not Found",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/api-keys/4",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/api-keys/4", service.ErrAPIKeyNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().RevokeAPIKey(gomock.Any(), database.RevokeAPIKeyParams{ID: 4, UserID: 1}).Return(database.APIKey{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "RevokeAPIKeyHandler Invalid ID",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/api-keys/a",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/api-keys/a", delivery.ErrInvalidAPIKeyID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		// Authenticate with API keys
+		{
+			name:           "Authenticate API Key Read",
+			reqHeaders:     map[string]string{"Authorization": "Bearer " + apiKey},
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:        1,
+				Title:     "test",
+				DueDate:   "2024-09-05T05:24:16Z",
+				Status:    "open",
+				Tags:      []string{},
+				BlockedBy: []int32{},
+				CreatedAt: "2024-09-05T05:24:16Z",
+				UpdatedAt: "2024-09-05T05:24:16Z",
+				Version:   1,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UseAPIKey(gomock.Any(), hex.EncodeToString(apiKeyHash[:])).Return(readKey, nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{
+					ID:        1,
+					Title:     "test",
+					DueDate:   createdAt,
+					Status:    database.TodoStatusOpen,
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+					Version:   1,
+				}, nil).Times(1)
+				expectTodoDefaults(repo)
+			},
+		},
+		{
+			name:            "Authenticate API Key Insufficient Scope",
+			input:           bytes.NewBufferString(`{"title": "test", "description": "test", "due_date": "2024-09-05T12:40:16+07:00"}`),
+			reqHeaders:      map[string]string{"Authorization": "Bearer " + apiKey},
+			reqMethod:       http.MethodPost,
+			reqTarget:       "/tasks",
+			expectedStatus:  http.StatusForbidden,
+			expectedHeaders: map[string]string{"WWW-Authenticate": `Bearer error="insufficient_scope", scope="write"`},
+			expectedBody:    problem(http.StatusForbidden, "/tasks", delivery.ErrInsufficientScope),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UseAPIKey(gomock.Any(), gomock.Any()).Return(readKey, nil).Times(1)
+			},
+		},
+		{
+			name:           "Authenticate API Key Not Admin",
+			reqHeaders:     map[string]string{"Authorization": "Bearer " + apiKey},
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/api-keys",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   problem(http.StatusForbidden, "/api-keys", delivery.ErrInsufficientScope),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UseAPIKey(gomock.Any(), gomock.Any()).Return(readKey, nil).Times(1)
+			},
+		},
+		{
+			name:            "Authenticate Revoked API Key",
+			reqHeaders:      map[string]string{"Authorization": "Bearer " + apiKey},
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedHeaders: map[string]string{"WWW-Authenticate": `Bearer error="invalid_token"`},
+			expectedBody:    problem(http.StatusUnauthorized, "/tasks/1", service.ErrInvalidAPIKey.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UseAPIKey(gomock.Any(), gomock.Any()).Return(database.APIKey{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+			for key, value := range tt.reqHeaders {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedKey {
+				var created dto.APIKeyCreatedDto
+				require.NoError(t, json.Unmarshal(data, &created))
+				require.True(t, strings.HasPrefix(created.Key, "tdl_"))
+				require.Equal(t, created.Key[:12], created.Prefix)
+				require.Equal(t, []string{"read", "write"}, created.Scopes)
+				require.Equal(t, stringPtr("2100-01-01T00:00:00Z"), created.ExpiresAt)
+				require.Nil(t, created.LastUsedAt)
+				require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			} else {
+				jsonExpected, _ := json.Marshal(tt.expectedBody)
+				require.Equal(t, jsonExpected, data)
+			}
+			for key, value := range tt.expectedHeaders {
+				require.Equal(t, value, res.Header.Get(key))
+			}
+		})
+	}
+}
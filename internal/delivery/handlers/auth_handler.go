@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// AuthHandler manages user registration and token issuing.
+type AuthHandler struct {
+	authService service.Auth
+	validator   *validator.Validate
+}
+
+func newAuthHandler(authService service.Auth, validator *validator.Validate) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		validator:   validator,
+	}
+}
+
+func (h AuthHandler) registerHandler(w http.ResponseWriter, r *http.Request) {
+	credentials := r.Context().Value(delivery.CredentialsKey).(dto.CredentialsDto)
+
+	user, err := h.authService.Register(r.Context(), credentials)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrRegisteringUser)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, user)
+}
+
+func (h AuthHandler) loginHandler(w http.ResponseWriter, r *http.Request) {
+	credentials := r.Context().Value(delivery.CredentialsKey).(dto.CredentialsDto)
+
+	tokens, err := h.authService.Login(r.Context(), credentials)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrLoggingIn)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.RespondWithJSON(w, http.StatusOK, tokens)
+}
+
+func (h AuthHandler) refreshHandler(w http.ResponseWriter, r *http.Request) {
+	refreshToken := r.Context().Value(delivery.RefreshTokenKey).(string)
+
+	tokens, err := h.authService.Refresh(r.Context(), refreshToken)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrRefreshingTokens)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.RespondWithJSON(w, http.StatusOK, tokens)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestAuthHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := database.User{ID: 1, Email: "user@example.com", PasswordHash: string(passwordHash), CreatedAt: createdAt}
+	refreshTokenHash := sha256.Sum256([]byte("refresh-token"))
+
+	tests := []struct {
+		name            string
+		input           io.Reader
+		reqHeaders      map[string]string
+		reqMethod       string
+		reqTarget       string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    interface{}
+		expectedTokens  bool
+		mockBehavior    mockBehavior
+	}{
+		// RegisterHandler
+		{
+			name:           "RegisterHandler Success",
+			input:          bytes.NewBufferString(`{"email": "User@Example.com", "password": "password123"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/register",
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.UserResponseDto{
+				ID:        1,
+				Email:     "user@example.com",
+				CreatedAt: "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.CreateUserParams) (database.User, error) {
+					if arg.Email != "user@example.com" || bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("password123")) != nil {
+						return database.User{}, errors.New("unexpected user params")
+					}
+					return user, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "RegisterHandler Email Taken",
+			input:          bytes.NewBufferString(`{"email": "user@example.com", "password": "password123"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/register",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/auth/register", service.ErrEmailTaken.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(database.User{}, &pq.Error{Code: "23505"}).Times(1)
+			},
+		},
+		{
+			name:           "RegisterHandler Invalid Credentials Input",
+			input:          bytes.NewBufferString(`{"email": "user", "password": "short"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/register",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/auth/register", delivery.ErrInvalidCredentialsInput,
+				dto.FieldErrorDto{Field: "email", Rule: "email", Code: "invalid_format"},
+				dto.FieldErrorDto{Field: "password", Rule: "min", Code: "too_short"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "RegisterHandler Password Too Long In Bytes",
+			input:          bytes.NewBufferString(`{"email": "user@example.com", "password": "` + strings.Repeat("пароль", 7) + `"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/register",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/auth/register", delivery.ErrInvalidCredentialsInput,
+				dto.FieldErrorDto{Field: "password", Rule: "maxbytes", Code: "too_long"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		// LoginHandler
+		{
+			name:           "LoginHandler Success",
+			input:          bytes.NewBufferString(`{"email": "user@example.com", "password": "password123"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/login",
+			expectedStatus: http.StatusOK,
+			expectedTokens: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetUserByEmail(gomock.Any(), "user@example.com").Return(user, nil).Times(1)
+				repo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(database.RefreshToken{}, nil).Times(1)
+			},
+		},
+		{
+			name:           "LoginHandler Wrong Password",
+			input:          bytes.NewBufferString(`{"email": "user@example.com", "password": "password456"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/login",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   problem(http.StatusUnauthorized, "/auth/login", service.ErrInvalidCredentials.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetUserByEmail(gomock.Any(), "user@example.com").Return(user, nil).Times(1)
+			},
+		},
+		{
+			name:           "LoginHandler Unknown Email",
+			input:          bytes.NewBufferString(`{"email": "other@example.com", "password": "password123"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/login",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   problem(http.StatusUnauthorized, "/auth/login", service.ErrInvalidCredentials.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetUserByEmail(gomock.Any(), "other@example.com").Return(database.User{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// RefreshHandler
+		{
+			name:           "RefreshHandler Success",
+			input:          bytes.NewBufferString(`{"refresh_token": "refresh-token"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/refresh",
+			expectedStatus: http.StatusOK,
+			expectedTokens: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ConsumeRefreshToken(gomock.Any(), hex.EncodeToString(refreshTokenHash[:])).Return(database.RefreshToken{UserID: 1}, nil).Times(1)
+				repo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(database.RefreshToken{}, nil).Times(1)
+			},
+		},
+		{
+			name:           "RefreshHandler Used Token",
+			input:          bytes.NewBufferString(`{"refresh_token": "refresh-token"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/auth/refresh",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   problem(http.StatusUnauthorized, "/auth/refresh", service.ErrInvalidRefreshToken.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ConsumeRefreshToken(gomock.Any(), gomock.Any()).Return(database.RefreshToken{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// Authenticate
+		{
+			name:            "Authenticate Missing Token",
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedHeaders: map[string]string{"WWW-Authenticate": "Bearer"},
+			expectedBody:    problem(http.StatusUnauthorized, "/tasks/1", delivery.ErrMissingAccessToken),
+			mockBehavior:    func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:            "Authenticate Expired Token",
+			reqHeaders:      map[string]string{"Authorization": "Bearer " + signToken(t, testSecret, "1", time.Now().Add(-time.Minute))},
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedHeaders: map[string]string{"WWW-Authenticate": `Bearer error="invalid_token"`},
+			expectedBody:    problem(http.StatusUnauthorized, "/tasks/1", service.ErrInvalidAccessToken.Error()),
+			mockBehavior:    func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "Authenticate Foreign Signature",
+			reqHeaders:     map[string]string{"Authorization": "Bearer " + accessToken(t, "other-secret", 1)},
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   problem(http.StatusUnauthorized, "/tasks/1", service.ErrInvalidAccessToken.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "Authenticate Other User Todo",
+			reqHeaders:     map[string]string{"Authorization": "Bearer " + accessToken(t, testSecret, 2)},
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 2}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			for key, value := range tt.reqHeaders {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedTokens {
+				var tokens dto.TokensDto
+				require.NoError(t, json.Unmarshal(data, &tokens))
+				require.Equal(t, "Bearer", tokens.TokenType)
+				require.Equal(t, int64(60), tokens.ExpiresIn)
+				require.NotEmpty(t, tokens.RefreshToken)
+				userID, err := s.Auth.ParseAccessToken(tokens.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, int32(1), userID)
+				require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			} else {
+				jsonExpected, _ := json.Marshal(tt.expectedBody)
+				require.Equal(t, jsonExpected, data)
+			}
+			for key, value := range tt.expectedHeaders {
+				require.Equal(t, value, res.Header.Get(key))
+			}
+		})
+	}
+}
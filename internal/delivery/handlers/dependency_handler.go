@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+func (h TodoHandler) addTodoBlockerHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	todoBlocker := r.Context().Value(delivery.TodoBlockerKey).(dto.TodoBlockerDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	todo, err := h.todoService.AddTodoBlocker(r.Context(), userID, todoID, todoBlocker, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrAddingTodoBlocker)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, todo)
+}
+
+func (h TodoHandler) removeTodoBlockerHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	blockerID := r.Context().Value(delivery.BlockerIDKey).(int)
+
+	if err := h.todoService.RemoveTodoBlocker(r.Context(), userID, todoID, blockerID); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrRemovingTodoBlocker)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
+}
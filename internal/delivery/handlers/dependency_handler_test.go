@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestDependencyHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	todo := func(id int32, status database.TodoStatus) database.Todo {
+		return database.Todo{
+			ID:          id,
+			Title:       "test",
+			Description: "test",
+			DueDate:     createdAt,
+			Status:      status,
+			ProjectID:   sql.NullInt32{Int32: 5, Valid: true},
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			Version:     1,
+		}
+	}
+	todoDto := func(id int32, status database.TodoStatus, blocked bool, blockedBy ...int32) dto.TodoResponseDto {
+		projectID := int32(5)
+		if blockedBy == nil {
+			blockedBy = []int32{}
+		}
+
+		return dto.TodoResponseDto{
+			ID:          id,
+			Title:       "test",
+			Description: "test",
+			DueDate:     "2024-09-05T05:24:16Z",
+			Status:      string(status),
+			ProjectID:   &projectID,
+			Tags:        []string{},
+			Blocked:     blocked,
+			BlockedBy:   blockedBy,
+			CreatedAt:   "2024-09-05T05:24:16Z",
+			UpdatedAt:   "2024-09-05T05:24:16Z",
+			Version:     1,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		input          io.Reader
+		reqMethod      string
+		reqTarget      string
+		expectedStatus int
+		expectedBody   interface{}
+		mockBehavior   mockBehavior
+	}{
+		// AddTodoBlockerHandler
+		{
+			name:           "AddTodoBlockerHandler Success",
+			input:          bytes.NewBufferString(`{"blocker_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusOK,
+			expectedBody: func() dto.TodoResponseDto {
+				blockedTodo := todoDto(1, database.TodoStatusOpen, true, 2)
+				blockedTodo.Version = 2
+				return blockedTodo
+			}(),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				touchedTodo := todo(1, database.TodoStatusOpen)
+				touchedTodo.Version = 2
+
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListTodoBlockerChain(gomock.Any(), database.ListTodoBlockerChainParams{ID: 2, OwnerID: 1}).Return([]int32{2, 3}, nil).Times(1)
+				repo.EXPECT().AddTodoBlocker(gomock.Any(), database.AddTodoBlockerParams{TodoID: 1, BlockerID: 2}).Return(int64(1), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 1, OwnerID: 1}).Return(touchedTodo, nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 2, BlockerStatus: database.TodoStatusOpen},
+				}, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+					require.Equal(t, "todo.updated", arg.Event)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Already Blocked",
+			input:          bytes.NewBufferString(`{"blocker_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(1, database.TodoStatusOpen, true, 2),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListTodoBlockerChain(gomock.Any(), database.ListTodoBlockerChainParams{ID: 2, OwnerID: 1}).Return([]int32{2}, nil).Times(1)
+				repo.EXPECT().AddTodoBlocker(gomock.Any(), database.AddTodoBlockerParams{TodoID: 1, BlockerID: 2}).Return(int64(0), nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 2, BlockerStatus: database.TodoStatusOpen},
+				}, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Cycle",
+			input:          bytes.NewBufferString(`{"blocker_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/blockers", service.ErrDependencyCycle.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListTodoBlockerChain(gomock.Any(), database.ListTodoBlockerChainParams{ID: 2, OwnerID: 1}).Return([]int32{2, 3, 1}, nil).Times(1)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Itself",
+			input:          bytes.NewBufferString(`{"blocker_id": 1}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/blockers", service.ErrDependencyCycle.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListTodoBlockerChain(gomock.Any(), database.ListTodoBlockerChainParams{ID: 1, OwnerID: 1}).Return([]int32{1}, nil).Times(1)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Blocker Not Found",
+			input:          bytes.NewBufferString(`{"blocker_id": 9}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/blockers", service.ErrBlockerTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListTodoBlockerChain(gomock.Any(), database.ListTodoBlockerChainParams{ID: 9, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Todo Not Found",
+			input:          bytes.NewBufferString(`{"blocker_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/blockers", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "AddTodoBlockerHandler Invalid Body",
+			input:          bytes.NewBufferString(`{"blocker_id": 0}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/blockers",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1/blockers", delivery.ErrInvalidTodoBlocker,
+				dto.FieldErrorDto{Field: "blocker_id", Rule: "required", Code: "required"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+
+		// RemoveTodoBlockerHandler
+		{
+			name:           "RemoveTodoBlockerHandler Success",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/blockers/2",
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   nil,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().RemoveTodoBlocker(gomock.Any(), database.RemoveTodoBlockerParams{TodoID: 1, BlockerID: 2, OwnerID: 1}).Return(database.TodoDependency{TodoID: 1, BlockerID: 2}, nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+					require.Equal(t, "todo.updated", arg.Event)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "RemoveTodoBlockerHandler Not Found",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/blockers/2",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/blockers/2", service.ErrTodoBlockerNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().RemoveTodoBlocker(gomock.Any(), database.RemoveTodoBlockerParams{TodoID: 1, BlockerID: 2, OwnerID: 1}).Return(database.TodoDependency{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "RemoveTodoBlockerHandler Invalid Blocker ID",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/blockers/a",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1/blockers/a", delivery.ErrInvalidBlockerID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+
+		// Completing blocked todos
+		{
+			name:           "CompleteHandler Blocked",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/complete", service.ErrTodoBlocked.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusInProgress), nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 2, BlockerStatus: database.TodoStatusDone},
+					{TodoID: 1, BlockerID: 3, BlockerStatus: database.TodoStatusInProgress},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Blockers Finished",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(1, database.TodoStatusDone, false, 2, 3),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, database.TodoStatusInProgress), nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 2, BlockerStatus: database.TodoStatusDone},
+					{TodoID: 1, BlockerID: 3, BlockerStatus: database.TodoStatusCancelled},
+				}, nil).Times(2)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusInProgress,
+					OwnerID:       1,
+				}).Return(todo(1, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Touches Blocked Todos",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/2/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(2, database.TodoStatusDone, false),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				blockedTodo := todo(1, database.TodoStatusOpen)
+				blockedTodo.Version = 2
+
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            2,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					OwnerID:       1,
+				}).Return(todo(2, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 2, OwnerID: 1}).Return([]database.Todo{blockedTodo}, nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{2}).Return(nil, nil).Times(2)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 2, BlockerStatus: database.TodoStatusDone},
+				}, nil).Times(1)
+				gomock.InOrder(
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, "todo.updated", arg.Event)
+						require.Equal(t, int32(1), arg.TodoID)
+						return nil
+					}),
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, "todo.completed", arg.Event)
+						require.Equal(t, int32(2), arg.TodoID)
+						return nil
+					}),
+				)
+			},
+		},
+
+		// GetProjectTodosOrderHandler
+		{
+			name:           "GetProjectTodosOrderHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects/5/tasks/order",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodosOrderDto{Items: []dto.TodoResponseDto{
+				todoDto(3, database.TodoStatusOpen, false),
+				todoDto(1, database.TodoStatusOpen, true, 3),
+				todoDto(2, database.TodoStatusOpen, true, 1, 3),
+				todoDto(4, database.TodoStatusOpen, false, 9),
+			}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 5, OwnerID: 1}).Return(database.Project{ID: 5}, nil).Times(1)
+				repo.EXPECT().ListProjectTodos(gomock.Any(), database.ListProjectTodosParams{ProjectID: 5, OwnerID: 1}).Return([]database.Todo{
+					todo(1, database.TodoStatusOpen),
+					todo(2, database.TodoStatusOpen),
+					todo(3, database.TodoStatusOpen),
+					todo(4, database.TodoStatusOpen),
+				}, nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1, 2, 3, 4}).Return([]database.ListTodoBlockersRow{
+					{TodoID: 1, BlockerID: 3, BlockerStatus: database.TodoStatusOpen},
+					{TodoID: 2, BlockerID: 1, BlockerStatus: database.TodoStatusOpen},
+					{TodoID: 2, BlockerID: 3, BlockerStatus: database.TodoStatusOpen},
+					{TodoID: 4, BlockerID: 9, BlockerStatus: database.TodoStatusDone},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetProjectTodosOrderHandler Project Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects/5/tasks/order",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/projects/5/tasks/order", service.ErrProjectNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 5, OwnerID: 1}).Return(database.Project{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTodoDefaults(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			require.Equal(t, jsonExpected, data)
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
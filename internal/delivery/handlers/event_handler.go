@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/service"
+)
+
+const (
+	// eventsHeartbeat is how often an idle event stream sends a comment, so proxies don't close it.
+	eventsHeartbeat = 15 * time.Second
+	// eventsRetry is the delay in milliseconds clients wait before reconnecting to a closed stream.
+	eventsRetry = 3000
+	// eventsReset is the event telling clients that the stream can't be resumed after Last-Event-ID,
+	// so they have to reload the todos instead.
+	eventsReset = "reset"
+)
+
+// EventHandler streams the changes of todos of the authenticated user as Server-Sent Events.
+type EventHandler struct {
+	eventService service.Events
+	heartbeat    time.Duration
+}
+
+func newEventHandler(eventService service.Events) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+		heartbeat:    eventsHeartbeat,
+	}
+}
+
+// streamEventsHandler sends the events saved after Last-Event-ID first, then the events committed while the stream is open.
+// The user is subscribed before the log is read, so no event committed meanwhile is missed, and events found in both are sent once.
+// When Last-Event-ID is no longer in the log, a reset event with the ID of the latest event is sent instead of the missed events.
+// The stream ends when the subscription is dropped, and the client resumes it with the ID of the last event it received.
+func (h EventHandler) streamEventsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	lastEventID, resume := r.Context().Value(delivery.LastEventIDKey).(int64)
+
+	events, unsubscribe := h.eventService.SubscribeEvents(userID)
+	defer unsubscribe()
+
+	var page []dto.TodoEventDto
+	var reset bool
+	if resume {
+		var err error
+		page, err = h.eventService.ListEvents(r.Context(), userID, lastEventID)
+		if errors.Is(err, service.ErrEventsExpired) {
+			logger.FromContext(r.Context()).Info("todo event stream can't be resumed, resetting it", "user_id", userID, "last_event_id", lastEventID)
+			reset = true
+			lastEventID, err = h.eventService.LastEventID(r.Context(), userID)
+		}
+		if err != nil {
+			delivery.RespondWithServiceError(w, r, err, delivery.ErrStreamingEvents)
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the write timeout of the server.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrStreamingEvents)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", eventsRetry)
+
+	if reset {
+		if err := writeEvent(w, dto.TodoEventDto{ID: lastEventID, Event: eventsReset, Data: json.RawMessage(`{}`)}); err != nil {
+			return
+		}
+	}
+
+	for len(page) > 0 {
+		for _, event := range page {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			lastEventID = event.ID
+		}
+
+		var err error
+		page, err = h.eventService.ListEvents(r.Context(), userID, lastEventID)
+		if err != nil {
+			logger.FromContext(r.Context()).Error(delivery.ErrStreamingEvents, "error", err)
+			return
+		}
+	}
+
+	if err := rc.Flush(); err != nil {
+		logger.FromContext(r.Context()).Error(delivery.ErrStreamingEvents, "error", err)
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.ID <= lastEventID {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes event as an SSE message, with the data compacted into a single line.
+func writeEvent(w http.ResponseWriter, event dto.TodoEventDto) error {
+	var data bytes.Buffer
+	if err := json.Compact(&data, event.Data); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event, data.Bytes())
+	return err
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+// readFrame reads an SSE message or comment up to the blank line ending it.
+func readFrame(r *bufio.Reader) (string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n"), nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+// outbox returns an event saved to the outbox with the same ID and position.
+func outbox(id int64, userID int32, event string) database.Outbox {
+	return database.Outbox{
+		ID:       id,
+		Event:    event,
+		UserID:   userID,
+		TodoID:   1,
+		Payload:  json.RawMessage(`{"event": "` + event + `", "data": {"id": 1}}`),
+		Position: sql.NullInt64{Int64: id, Valid: true},
+	}
+}
+
+func TestEventHandlerStream(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	repo := mock_repo.NewMockRepository(ctl)
+	repo.EXPECT().ListTodoEvents(gomock.Any(), database.ListTodoEventsParams{UserID: 1, FromPosition: 4, RowLimit: 101}).
+		Return([]database.Outbox{outbox(4, 1, "todo.created"), outbox(5, 1, "todo.created"), outbox(6, 1, "todo.updated")}, nil).Times(1)
+	repo.EXPECT().ListTodoEvents(gomock.Any(), database.ListTodoEventsParams{UserID: 1, FromPosition: 6, RowLimit: 101}).
+		Return([]database.Outbox{outbox(6, 1, "todo.updated")}, nil).Times(1)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), int64(6)).Return(outbox(6, 1, "todo.updated"), nil).Times(1)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), int64(7)).Return(outbox(7, 2, "todo.created"), nil).Times(1)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), int64(8)).Return(outbox(8, 1, "todo.deleted"), nil).Times(1)
+
+	s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+	v, _ := validator.InitValidator()
+	h := NewHandler(s, v)
+	h.EventHandler.heartbeat = 20 * time.Millisecond
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/tasks/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "4")
+	req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+
+	body := bufio.NewReader(res.Body)
+	for _, expected := range []string{
+		"retry: 3000",
+		"id: 5\nevent: todo.created\ndata: {\"event\":\"todo.created\",\"data\":{\"id\":1}}",
+		"id: 6\nevent: todo.updated\ndata: {\"event\":\"todo.updated\",\"data\":{\"id\":1}}",
+	} {
+		frame, err := readFrame(body)
+		require.NoError(t, err)
+		require.Equal(t, expected, frame)
+	}
+
+	// The replayed event is sent once, and events of other users are not sent at all.
+	for _, payload := range []string{"6", "7", "8"} {
+		s.Events.HandleNotification(context.Background(), payload)
+	}
+
+	var frames []string
+	for len(frames) == 0 || frames[len(frames)-1] != ": heartbeat" {
+		frame, err := readFrame(body)
+		require.NoError(t, err)
+		if frame != ": heartbeat" || len(frames) > 0 {
+			frames = append(frames, frame)
+		}
+	}
+	require.Equal(t, "id: 8\nevent: todo.deleted\ndata: {\"event\":\"todo.deleted\",\"data\":{\"id\":1}}", frames[0])
+	for _, frame := range frames[1:] {
+		require.Equal(t, ": heartbeat", frame)
+	}
+
+	s.Events.Close()
+	for {
+		frame, err := readFrame(body)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, ": heartbeat", frame)
+	}
+}
+
+func TestEventHandlerStreamReset(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	repo := mock_repo.NewMockRepository(ctl)
+	repo.EXPECT().ListTodoEvents(gomock.Any(), database.ListTodoEventsParams{UserID: 1, FromPosition: 4, RowLimit: 101}).
+		Return([]database.Outbox{outbox(9, 1, "todo.created")}, nil).Times(1)
+	repo.EXPECT().GetLastTodoEventPosition(gomock.Any(), int32(1)).Return(int64(12), nil).Times(1)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), int64(12)).Return(outbox(12, 1, "todo.updated"), nil).Times(1)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), int64(13)).Return(outbox(13, 1, "todo.deleted"), nil).Times(1)
+
+	s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+	v, _ := validator.InitValidator()
+	h := NewHandler(s, v)
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/tasks/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "4")
+	req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body := bufio.NewReader(res.Body)
+	for _, expected := range []string{"retry: 3000", "id: 12\nevent: reset\ndata: {}"} {
+		frame, err := readFrame(body)
+		require.NoError(t, err)
+		require.Equal(t, expected, frame)
+	}
+
+	// Events up to the one the stream was reset to are not sent again.
+	for _, payload := range []string{"12", "13"} {
+		s.Events.HandleNotification(context.Background(), payload)
+	}
+	frame, err := readFrame(body)
+	require.NoError(t, err)
+	require.Equal(t, "id: 13\nevent: todo.deleted\ndata: {\"event\":\"todo.deleted\",\"data\":{\"id\":1}}", frame)
+
+	s.Events.Close()
+}
+
+func TestEventHandlerErrors(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	tests := []struct {
+		name           string
+		lastEventID    string
+		expectedStatus int
+		expectedBody   dto.ProblemDto
+		mockBehavior   mockBehavior
+	}{
+		{
+			name:           "Invalid Last-Event-ID",
+			lastEventID:    "-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/events", delivery.ErrInvalidLastEventID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "Repo Error",
+			lastEventID:    "4",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/events", delivery.ErrStreamingEvents),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodoEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("some db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/tasks/events", nil)
+			req.Header.Set("Accept", "text/event-stream")
+			req.Header.Set("Last-Event-ID", tt.lastEventID)
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+			require.Equal(t, jsonExpected, data)
+		})
+	}
+}
@@ -3,29 +3,128 @@ package handlers
 import (
 	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
+	"net/http"
 	"to-do-list-go/internal/delivery/middleware"
 	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/tracing"
 )
 
-// Handler manages the endpoints, including the TodoHandler for handling todos-related requests.
+// EventStreamPath is the path of the long-lived stream of todo events, which request deadlines don't apply to.
+const EventStreamPath = "/tasks/events"
+
+// Handler manages the endpoints, with a handler per area, e.g. the TodoHandler for handling todos-related requests.
 type Handler struct {
-	TodoHandler *TodoHandler
+	TodoHandler     *TodoHandler
+	ProjectHandler  *ProjectHandler
+	TagHandler      *TagHandler
+	ReminderHandler *ReminderHandler
+	WebhookHandler  *WebhookHandler
+	EventHandler    *EventHandler
+	AuthHandler     *AuthHandler
+	APIKeyHandler   *APIKeyHandler
+	HealthHandler   *HealthHandler
 }
 
 // NewHandler creates a new Handler.
 func NewHandler(service *service.Service, validator *validator.Validate) *Handler {
 	todoHandler := newTodoHandler(service.Todos, validator)
+	projectHandler := newProjectHandler(service.Projects, validator)
+	tagHandler := newTagHandler(service.Tags)
+	reminderHandler := newReminderHandler(service.Reminders, validator)
+	webhookHandler := newWebhookHandler(service.Webhooks, validator)
+	eventHandler := newEventHandler(service.Events)
+	authHandler := newAuthHandler(service.Auth, validator)
+	apiKeyHandler := newAPIKeyHandler(service.APIKeys, validator)
+	healthHandler := newHealthHandler(service.Health)
 
 	return &Handler{
-		TodoHandler: todoHandler,
+		TodoHandler:     todoHandler,
+		ProjectHandler:  projectHandler,
+		TagHandler:      tagHandler,
+		ReminderHandler: reminderHandler,
+		WebhookHandler:  webhookHandler,
+		EventHandler:    eventHandler,
+		AuthHandler:     authHandler,
+		APIKeyHandler:   apiKeyHandler,
+		HealthHandler:   healthHandler,
 	}
 }
 
-// RegisterRoutes manages route registration for todos endpoints with associated middlewares.
+// RegisterRoutes manages route registration for the endpoints, including event streams, with associated middlewares.
+// Only the authenticated API renders times, so the health probes and auth endpoints ignore the requested timezone.
 func (h Handler) RegisterRoutes(r *chi.Mux) {
-	r.With(middleware.CheckTodoInput(h.TodoHandler.validator)).Post("/tasks", h.TodoHandler.createTodoHandler)
-	r.Get("/tasks", h.TodoHandler.getTodosHandler)
-	r.With(middleware.GetTodoID).Get("/tasks/{id}", h.TodoHandler.getTodoHandler)
-	r.With(middleware.CheckTodoInput(h.TodoHandler.validator), middleware.GetTodoID).Put("/tasks/{id}", h.TodoHandler.updateTodoHandler)
-	r.With(middleware.GetTodoID).Delete("/tasks/{id}", h.TodoHandler.deleteTodoHandler)
+	r.Get("/healthz", h.HealthHandler.livenessHandler)
+	r.Get("/readyz", h.HealthHandler.readinessHandler)
+
+	r.With(middleware.CheckCredentials(h.AuthHandler.validator)).Post("/auth/register", traced("AuthHandler.register", h.AuthHandler.registerHandler))
+	r.With(middleware.CheckCredentials(h.AuthHandler.validator)).Post("/auth/login", traced("AuthHandler.login", h.AuthHandler.loginHandler))
+	r.With(middleware.CheckRefreshToken(h.AuthHandler.validator)).Post("/auth/refresh", traced("AuthHandler.refresh", h.AuthHandler.refreshHandler))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Authenticate(h.AuthHandler.authService, h.APIKeyHandler.apiKeyService))
+		r.Use(middleware.GetTimezone)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(service.ScopeRead))
+
+			r.With(middleware.GetTodosQuery(h.TodoHandler.validator)).Get("/tasks", traced("TodoHandler.getTodos", h.TodoHandler.getTodosHandler))
+			r.With(middleware.GetTodoSearchQuery(h.TodoHandler.validator)).Get("/tasks/search", traced("TodoHandler.searchTodos", h.TodoHandler.searchTodosHandler))
+			r.With(middleware.GetLastEventID).Get(EventStreamPath, traced("EventHandler.streamEvents", h.EventHandler.streamEventsHandler))
+			r.With(middleware.GetTodoID).Get("/tasks/{id}", traced("TodoHandler.getTodo", h.TodoHandler.getTodoHandler))
+			r.With(middleware.GetTodoID).Get("/tasks/{id}/subtasks", traced("TodoHandler.getSubtasks", h.TodoHandler.getSubtasksHandler))
+			r.With(middleware.GetTodoID).Get("/tasks/{id}/reminders", traced("ReminderHandler.getReminders", h.ReminderHandler.getRemindersHandler))
+			r.With(middleware.GetIncludeArchived).Get("/projects", traced("ProjectHandler.getProjects", h.ProjectHandler.getProjectsHandler))
+			r.With(middleware.GetProjectID).Get("/projects/{id}", traced("ProjectHandler.getProject", h.ProjectHandler.getProjectHandler))
+			r.With(middleware.GetProjectID, middleware.GetTodosQuery(h.ProjectHandler.validator)).Get("/projects/{id}/tasks", traced("ProjectHandler.getProjectTodos", h.ProjectHandler.getProjectTodosHandler))
+			r.With(middleware.GetProjectID).Get("/projects/{id}/tasks/order", traced("ProjectHandler.getProjectTodosOrder", h.ProjectHandler.getProjectTodosOrderHandler))
+			r.Get("/tags", traced("TagHandler.getTags", h.TagHandler.getTagsHandler))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(service.ScopeWrite))
+
+			r.With(middleware.CheckTodoInput(h.TodoHandler.validator)).Post("/tasks", traced("TodoHandler.createTodo", h.TodoHandler.createTodoHandler))
+			r.With(middleware.CheckTodoInput(h.TodoHandler.validator), middleware.GetTodoID, middleware.GetIfMatch).Put("/tasks/{id}", traced("TodoHandler.updateTodo", h.TodoHandler.updateTodoHandler))
+			r.With(middleware.CheckTodoPatch(h.TodoHandler.validator), middleware.GetTodoID, middleware.GetIfMatch).Patch("/tasks/{id}", traced("TodoHandler.patchTodo", h.TodoHandler.patchTodoHandler))
+			r.With(middleware.GetTodoID, middleware.GetIfMatch).Delete("/tasks/{id}", traced("TodoHandler.deleteTodo", h.TodoHandler.deleteTodoHandler))
+			r.With(middleware.CheckTodoMove(h.TodoHandler.validator), middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/move", traced("TodoHandler.moveTodo", h.TodoHandler.moveTodoHandler))
+			r.With(middleware.CheckTodoInput(h.TodoHandler.validator), middleware.GetTodoID).Post("/tasks/{id}/subtasks", traced("TodoHandler.createSubtask", h.TodoHandler.createSubtaskHandler))
+			r.With(middleware.CheckTodoParent(h.TodoHandler.validator), middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/parent", traced("TodoHandler.setTodoParent", h.TodoHandler.setTodoParentHandler))
+			r.With(middleware.CheckTodoBlocker(h.TodoHandler.validator), middleware.GetTodoID).Post("/tasks/{id}/blockers", traced("TodoHandler.addTodoBlocker", h.TodoHandler.addTodoBlockerHandler))
+			r.With(middleware.GetTodoID, middleware.GetBlockerID).Delete("/tasks/{id}/blockers/{blockerID}", traced("TodoHandler.removeTodoBlocker", h.TodoHandler.removeTodoBlockerHandler))
+			r.With(middleware.CheckReminderInput(h.ReminderHandler.validator), middleware.GetTodoID).Post("/tasks/{id}/reminders", traced("ReminderHandler.createReminder", h.ReminderHandler.createReminderHandler))
+			r.With(middleware.GetTodoID, middleware.GetReminderID).Delete("/tasks/{id}/reminders/{reminderID}", traced("ReminderHandler.deleteReminder", h.ReminderHandler.deleteReminderHandler))
+			r.With(middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/start", traced("TodoHandler.startTodo", h.TodoHandler.startTodoHandler))
+			r.With(middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/complete", traced("TodoHandler.completeTodo", h.TodoHandler.completeTodoHandler))
+			r.With(middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/cancel", traced("TodoHandler.cancelTodo", h.TodoHandler.cancelTodoHandler))
+			r.With(middleware.GetTodoID, middleware.GetIfMatch).Post("/tasks/{id}/reopen", traced("TodoHandler.reopenTodo", h.TodoHandler.reopenTodoHandler))
+			r.With(middleware.CheckProjectInput(h.ProjectHandler.validator)).Post("/projects", traced("ProjectHandler.createProject", h.ProjectHandler.createProjectHandler))
+			r.With(middleware.CheckProjectInput(h.ProjectHandler.validator), middleware.GetProjectID).Put("/projects/{id}", traced("ProjectHandler.updateProject", h.ProjectHandler.updateProjectHandler))
+			r.With(middleware.GetProjectID, middleware.GetProjectDeleteQuery(h.ProjectHandler.validator)).Delete("/projects/{id}", traced("ProjectHandler.deleteProject", h.ProjectHandler.deleteProjectHandler))
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireScope(service.ScopeAdmin))
+
+			r.With(middleware.CheckAPIKeyInput(h.APIKeyHandler.validator)).Post("/api-keys", traced("APIKeyHandler.createAPIKey", h.APIKeyHandler.createAPIKeyHandler))
+			r.Get("/api-keys", traced("APIKeyHandler.getAPIKeys", h.APIKeyHandler.getAPIKeysHandler))
+			r.With(middleware.GetAPIKeyID).Delete("/api-keys/{id}", traced("APIKeyHandler.revokeAPIKey", h.APIKeyHandler.revokeAPIKeyHandler))
+			r.With(middleware.CheckWebhookInput(h.WebhookHandler.validator)).Post("/webhooks", traced("WebhookHandler.createWebhook", h.WebhookHandler.createWebhookHandler))
+			r.Get("/webhooks", traced("WebhookHandler.getWebhooks", h.WebhookHandler.getWebhooksHandler))
+			r.With(middleware.GetWebhookID).Get("/webhooks/{id}", traced("WebhookHandler.getWebhook", h.WebhookHandler.getWebhookHandler))
+			r.With(middleware.CheckWebhookInput(h.WebhookHandler.validator), middleware.GetWebhookID).Put("/webhooks/{id}", traced("WebhookHandler.updateWebhook", h.WebhookHandler.updateWebhookHandler))
+			r.With(middleware.GetWebhookID).Delete("/webhooks/{id}", traced("WebhookHandler.deleteWebhook", h.WebhookHandler.deleteWebhookHandler))
+			r.With(middleware.GetWebhookID).Get("/webhooks/{id}/deliveries", traced("WebhookHandler.getWebhookDeliveries", h.WebhookHandler.getWebhookDeliveriesHandler))
+		})
+	})
+}
+
+// traced runs handler within its own span, so time spent in the handler shows apart from middlewares and the service.
+func traced(name string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracing.Tracer().Start(r.Context(), name)
+		defer span.End()
+
+		handler(w, r.WithContext(ctx))
+	}
 }
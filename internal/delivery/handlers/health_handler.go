@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/service"
+)
+
+// HealthHandler manages liveness and readiness probes.
+type HealthHandler struct {
+	healthService service.Health
+}
+
+func newHealthHandler(healthService service.Health) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+func (h HealthHandler) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	type liveness struct {
+		Status string `json:"status"`
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, liveness{Status: "ok"})
+}
+
+func (h HealthHandler) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	readiness, err := h.healthService.Readiness(r.Context())
+	if err != nil {
+		logger.FromContext(r.Context()).Warn(delivery.ErrNotReady, "error", err)
+		delivery.RespondWithJSON(w, http.StatusServiceUnavailable, readiness)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, readiness)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestHealthHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool)
+
+	stats := sql.DBStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDuration:       1500 * time.Millisecond,
+	}
+	poolStats := dto.DBPoolStatsDto{
+		MaxOpenConnections: 10,
+		OpenConnections:    3,
+		InUse:              1,
+		Idle:               2,
+		WaitCount:          4,
+		WaitDurationMs:     1500,
+	}
+	version := int64(20261018084000)
+
+	tests := []struct {
+		name           string
+		reqTarget      string
+		shuttingDown   bool
+		expectedStatus int
+		expectedBody   interface{}
+		mockBehavior   mockBehavior
+	}{
+		{
+			name:           "Liveness",
+			reqTarget:      "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody: struct {
+				Status string `json:"status"`
+			}{
+				Status: "ok",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {},
+		},
+		{
+			name:           "Liveness Ignores Timezone",
+			reqTarget:      "/healthz?tz=Mars/Olympus",
+			expectedStatus: http.StatusOK,
+			expectedBody: struct {
+				Status string `json:"status"`
+			}{
+				Status: "ok",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {},
+		},
+		{
+			name:           "Readiness OK",
+			reqTarget:      "/readyz",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.ReadinessDto{
+				Status:           "ready",
+				Database:         "up",
+				MigrationVersion: &version,
+				Pool:             poolStats,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {
+				pool.EXPECT().Stats().Return(stats).Times(1)
+				pool.EXPECT().PingContext(gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().MigrationVersion(gomock.Any()).Return(version, nil).Times(1)
+			},
+		},
+		{
+			name:           "Readiness Database Down",
+			reqTarget:      "/readyz",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: dto.ReadinessDto{
+				Status:   "not_ready",
+				Database: "down",
+				Pool:     poolStats,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {
+				pool.EXPECT().Stats().Return(stats).Times(1)
+				pool.EXPECT().PingContext(gomock.Any()).Return(errors.New("connection refused")).Times(1)
+			},
+		},
+		{
+			name:           "Readiness Migrations Missing",
+			reqTarget:      "/readyz",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: dto.ReadinessDto{
+				Status:   "not_ready",
+				Database: "up",
+				Pool:     poolStats,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {
+				pool.EXPECT().Stats().Return(stats).Times(1)
+				pool.EXPECT().PingContext(gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().MigrationVersion(gomock.Any()).Return(int64(0), errors.New(`relation "goose_db_version" does not exist`)).Times(1)
+			},
+		},
+		{
+			name:           "Readiness Shutting Down",
+			reqTarget:      "/readyz",
+			shuttingDown:   true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: dto.ReadinessDto{
+				Status:           "shutting_down",
+				Database:         "up",
+				MigrationVersion: &version,
+				Pool:             poolStats,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository, pool *mock_repo.MockPool) {
+				pool.EXPECT().Stats().Return(stats).Times(1)
+				pool.EXPECT().PingContext(gomock.Any()).Return(nil).Times(1)
+				repo.EXPECT().MigrationVersion(gomock.Any()).Return(version, nil).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			pool := mock_repo.NewMockPool(ctl)
+			tt.mockBehavior(repo, pool)
+
+			s := service.NewService(repo, pool, service.AuthConfig{}, service.TodoConfig{})
+			if tt.shuttingDown {
+				s.Health.SetShuttingDown()
+			}
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.reqTarget, nil)
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			require.Equal(t, jsonExpected, data)
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
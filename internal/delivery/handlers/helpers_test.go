@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+const testSecret = "test-secret"
+
+// expectTransactions makes repo run the queries of its transactions on itself.
+func expectTransactions(repo *mock_repo.MockRepository) {
+	repo.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(database.Repository) error) error {
+		return fn(repo)
+	}).AnyTimes()
+}
+
+// expectTodoDefaults makes rendered todos have no tags, subtasks and blockers unless the case expects ListTodoTags,
+// ListSubtaskProgress or ListTodoBlockers itself, and saves their events to the outbox and queues them for no webhooks
+// unless it expects InsertOutboxEvent or EnqueueWebhookDeliveries. It goes after the expectations of the case.
+func expectTodoDefaults(repo *mock_repo.MockRepository) {
+	repo.EXPECT().ListTodoTags(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().ListSubtaskProgress(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().ListTodoBlockers(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	repo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func accessToken(t *testing.T, secret string, userID int) string {
+	return signToken(t, secret, strconv.Itoa(userID), time.Now().Add(time.Minute))
+}
+
+func signToken(t *testing.T, secret, subject string, expiresAt time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func problem(status int, instance, detail string, fieldErrors ...dto.FieldErrorDto) dto.ProblemDto {
+	title := http.StatusText(status)
+	if status == delivery.StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+
+	return dto.ProblemDto{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Errors:   fieldErrors,
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// ProjectHandler manages projects-related operations.
+type ProjectHandler struct {
+	projectService service.Projects
+	validator      *validator.Validate
+}
+
+func newProjectHandler(projectService service.Projects, validator *validator.Validate) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+		validator:      validator,
+	}
+}
+
+func (h ProjectHandler) createProjectHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectInput := r.Context().Value(delivery.ProjectInputKey).(dto.ProjectInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	project, err := h.projectService.CreateProject(r.Context(), userID, projectInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingProject)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusCreated, project)
+}
+
+func (h ProjectHandler) getProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	includeArchived := r.Context().Value(delivery.IncludeArchivedKey).(bool)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	projects, err := h.projectService.GetProjects(r.Context(), userID, includeArchived, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingProjects)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, projects)
+}
+
+func (h ProjectHandler) getProjectHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectID := r.Context().Value(delivery.ProjectIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	project, err := h.projectService.GetProject(r.Context(), userID, projectID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingProject)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, project)
+}
+
+func (h ProjectHandler) updateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectID := r.Context().Value(delivery.ProjectIDKey).(int)
+	projectInput := r.Context().Value(delivery.ProjectInputKey).(dto.ProjectInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	project, err := h.projectService.UpdateProject(r.Context(), userID, projectID, projectInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrUpdatingProject)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, project)
+}
+
+func (h ProjectHandler) deleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectID := r.Context().Value(delivery.ProjectIDKey).(int)
+	deleteQuery := r.Context().Value(delivery.ProjectDeleteKey).(dto.ProjectDeleteQueryDto)
+
+	if err := h.projectService.DeleteProject(r.Context(), userID, projectID, deleteQuery); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrDeletingProject)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+func (h ProjectHandler) getProjectTodosHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectID := r.Context().Value(delivery.ProjectIDKey).(int)
+	todosQuery := r.Context().Value(delivery.TodosQueryKey).(dto.TodosQueryDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	todos, err := h.projectService.GetProjectTodos(r.Context(), userID, projectID, todosQuery, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingTodos)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, todos)
+}
+
+func (h ProjectHandler) getProjectTodosOrderHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	projectID := r.Context().Value(delivery.ProjectIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	order, err := h.projectService.GetProjectTodosOrder(r.Context(), userID, projectID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingTodosOrder)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, order)
+}
@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestProjectHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	project := database.Project{ID: 2, OwnerID: 1, Name: "work", Color: "#ff8800", SortOrder: 1, CreatedAt: createdAt, UpdatedAt: createdAt}
+	projectDto := dto.ProjectResponseDto{ID: 2, Name: "work", Color: "#ff8800", SortOrder: 1, CreatedAt: "2024-09-05T05:24:16Z", UpdatedAt: "2024-09-05T05:24:16Z"}
+	projectID := int32(2)
+	todo := database.Todo{ID: 1, Title: "test", Description: "test", DueDate: createdAt, Status: database.TodoStatusOpen, ProjectID: sql.NullInt32{Int32: 2, Valid: true}, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 2}
+	todoDto := dto.TodoResponseDto{ID: 1, Title: "test", Description: "test", DueDate: "2024-09-05T05:24:16Z", Status: "open", ProjectID: &projectID, Tags: []string{}, BlockedBy: []int32{}, CreatedAt: "2024-09-05T05:24:16Z", UpdatedAt: "2024-09-05T05:24:16Z", Version: 2}
+
+	tests := []struct {
+		name            string
+		input           io.Reader
+		reqHeaders      map[string]string
+		reqMethod       string
+		reqTarget       string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    interface{}
+		mockBehavior    mockBehavior
+	}{
+		// CreateProjectHandler
+		{
+			name:           "CreateProjectHandler Success",
+			input:          bytes.NewBufferString(`{"name": "work", "color": "#ff8800", "sort_order": 1}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/projects",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   projectDto,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateProject(gomock.Any(), database.CreateProjectParams{OwnerID: 1, Name: "work", Color: "#ff8800", SortOrder: 1}).Return(project, nil).Times(1)
+			},
+		},
+		{
+			name:           "CreateProjectHandler Default Color",
+			input:          bytes.NewBufferString(`{"name": "work"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/projects",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   projectDto,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateProject(gomock.Any(), database.CreateProjectParams{OwnerID: 1, Name: "work", Color: "#808080"}).Return(project, nil).Times(1)
+			},
+		},
+		{
+			name:           "CreateProjectHandler Invalid Color",
+			input:          bytes.NewBufferString(`{"name": "work", "color": "orange"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/projects",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/projects", delivery.ErrInvalidProjectInput,
+				dto.FieldErrorDto{Field: "color", Rule: "hexcolor", Code: "invalid_format"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		// GetProjectsHandler
+		{
+			name:           "GetProjectsHandler Including Archived",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects?archived=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.ProjectsDto{Items: []dto.ProjectResponseDto{projectDto}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListProjects(gomock.Any(), database.ListProjectsParams{OwnerID: 1, IncludeArchived: true}).Return([]database.Project{project}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetProjectsHandler Invalid Archived",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects?archived=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/projects", delivery.ErrInvalidArchivedQuery),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		// GetProjectHandler
+		{
+			name:           "GetProjectHandler Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects/3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/projects/3", service.ErrProjectNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 3, OwnerID: 1}).Return(database.Project{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// UpdateProjectHandler
+		{
+			name:           "UpdateProjectHandler Archive",
+			input:          bytes.NewBufferString(`{"name": "work", "color": "#ff8800", "archived": true, "sort_order": 1}`),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/projects/2",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.ProjectResponseDto{
+				ID: 2, Name: "work", Color: "#ff8800", Archived: true, SortOrder: 1, CreatedAt: "2024-09-05T05:24:16Z", UpdatedAt: "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				archived := project
+				archived.Archived = true
+				repo.EXPECT().UpdateProject(gomock.Any(), database.UpdateProjectParams{ID: 2, OwnerID: 1, Name: "work", Color: "#ff8800", Archived: true, SortOrder: 1}).Return(archived, nil).Times(1)
+			},
+		},
+		// DeleteProjectHandler
+		{
+			name:           "DeleteProjectHandler Move Todos Out By Default",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2",
+			expectedStatus: http.StatusNoContent,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				movedTodo := todo
+				movedTodo.ProjectID = sql.NullInt32{}
+				repo.EXPECT().MoveProjectTodos(gomock.Any(), database.MoveProjectTodosParams{ProjectID: 2, OwnerID: 1}).Return([]database.Todo{movedTodo}, nil).Times(1)
+				repo.EXPECT().DeleteProject(gomock.Any(), database.DeleteProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+					require.Equal(t, "todo.updated", arg.Event)
+					require.Equal(t, int32(1), arg.TodoID)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "DeleteProjectHandler Move Todos To Target",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2?todos=move&target_project_id=5",
+			expectedStatus: http.StatusNoContent,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 5, OwnerID: 1}).Return(database.Project{ID: 5}, nil).Times(1)
+				repo.EXPECT().MoveProjectTodos(gomock.Any(), database.MoveProjectTodosParams{
+					ProjectID:       2,
+					OwnerID:         1,
+					TargetProjectID: sql.NullInt32{Int32: 5, Valid: true},
+				}).Return(nil, nil).Times(1)
+				repo.EXPECT().DeleteProject(gomock.Any(), database.DeleteProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+			},
+		},
+		{
+			name:           "DeleteProjectHandler Delete Todos",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2?todos=delete",
+			expectedStatus: http.StatusNoContent,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				subtask := database.Todo{ID: 3, Title: "subtask", ParentID: sql.NullInt32{Int32: 1, Valid: true}, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1}
+				repo.EXPECT().DeleteProjectTodos(gomock.Any(), database.DeleteProjectTodosParams{ProjectID: 2, OwnerID: 1}).Return([]database.Todo{todo, subtask}, nil).Times(1)
+				repo.EXPECT().DeleteProject(gomock.Any(), database.DeleteProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+					require.Equal(t, "todo.deleted", arg.Event)
+					return nil
+				}).Times(2)
+			},
+		},
+		{
+			name:           "DeleteProjectHandler Not Found",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/3?todos=delete",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/projects/3", service.ErrProjectNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteProjectTodos(gomock.Any(), database.DeleteProjectTodosParams{ProjectID: 3, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().DeleteProject(gomock.Any(), database.DeleteProjectParams{ID: 3, OwnerID: 1}).Return(database.Project{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "DeleteProjectHandler Target Is Deleted Project",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2?target_project_id=2",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/projects/2", service.ErrInvalidTargetProject.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "DeleteProjectHandler Target With Delete",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2?todos=delete&target_project_id=5",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/projects/2", delivery.ErrInvalidProjectDelete),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "DeleteProjectHandler Invalid Mode",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/projects/2?todos=keep",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/projects/2", delivery.ErrInvalidProjectDelete,
+				dto.FieldErrorDto{Field: "todos", Rule: "oneof", Code: "not_allowed"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		// GetProjectTodosHandler
+		{
+			name:           "GetProjectTodosHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects/2/tasks?limit=10",
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.TodosPageDto{Items: []dto.TodoResponseDto{todoDto}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+				repo.EXPECT().ListTodos(gomock.Any(), database.ListTodosParams{
+					SortKey1:  "created_at",
+					RowLimit:  11,
+					OwnerID:   1,
+					ProjectID: sql.NullInt32{Int32: 2, Valid: true},
+				}).Return([]database.Todo{todo}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetProjectTodosHandler Project Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/projects/3/tasks",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/projects/3/tasks", service.ErrProjectNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 3, OwnerID: 1}).Return(database.Project{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// CreateTodoHandler and MoveTodoHandler with projects
+		{
+			name:           "CreateTodoHandler In Project",
+			input:          bytes.NewBufferString(`{"title": "test", "description": "test", "due_date": "2024-09-05T05:24:16Z", "project_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   todoDto,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
+					Title:       "test",
+					Description: "test",
+					DueDate:     createdAt,
+					OwnerID:     1,
+					ProjectID:   sql.NullInt32{Int32: 2, Valid: true},
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:            "MoveTodoHandler Success",
+			input:           bytes.NewBufferString(`{"project_id": 2}`),
+			reqHeaders:      map[string]string{"If-Match": `"1"`},
+			reqMethod:       http.MethodPost,
+			reqTarget:       "/tasks/1/move",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"2"`},
+			expectedBody:    todoDto,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 2, OwnerID: 1}).Return(project, nil).Times(1)
+				repo.EXPECT().MoveTodo(gomock.Any(), database.MoveTodoParams{
+					ID:        1,
+					OwnerID:   1,
+					ProjectID: sql.NullInt32{Int32: 2, Valid: true},
+					Versions:  []int32{1},
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:           "MoveTodoHandler Out Of Project Error",
+			input:          bytes.NewBufferString(`{"project_id": null}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/move",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/1/move", delivery.ErrMovingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().MoveTodo(gomock.Any(), database.MoveTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, errors.New("some db error")).Times(1)
+			},
+		},
+		{
+			name:           "MoveTodoHandler Foreign Project",
+			input:          bytes.NewBufferString(`{"project_id": 7}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/move",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/move", service.ErrProjectNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetProject(gomock.Any(), database.GetProjectParams{ID: 7, OwnerID: 1}).Return(database.Project{}, sql.ErrNoRows).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTodoDefaults(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+			for key, value := range tt.reqHeaders {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			require.Equal(t, jsonExpected, data)
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			for key, value := range tt.expectedHeaders {
+				require.Equal(t, value, res.Header.Get(key))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// ReminderHandler manages reminders about todos of the authenticated user.
+type ReminderHandler struct {
+	reminderService service.Reminders
+	validator       *validator.Validate
+}
+
+func newReminderHandler(reminderService service.Reminders, validator *validator.Validate) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+		validator:       validator,
+	}
+}
+
+func (h ReminderHandler) createReminderHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	reminderInput := r.Context().Value(delivery.ReminderInputKey).(dto.ReminderInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	reminder, err := h.reminderService.CreateReminder(r.Context(), userID, todoID, reminderInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingReminder)
+		return
+	}
+
+	if reminder.Secret != nil {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	delivery.RespondWithJSON(w, http.StatusCreated, reminder)
+}
+
+func (h ReminderHandler) getRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	reminders, err := h.reminderService.ListReminders(r.Context(), userID, todoID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingReminders)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, reminders)
+}
+
+func (h ReminderHandler) deleteReminderHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	reminderID := r.Context().Value(delivery.ReminderIDKey).(int)
+
+	if err := h.reminderService.DeleteReminder(r.Context(), userID, todoID, reminderID); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrDeletingReminder)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
+}
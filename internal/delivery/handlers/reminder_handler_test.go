@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestReminderHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	remindAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	minutesBefore := int32(30)
+	target := "https://example.com/hooks/reminders"
+
+	tests := []struct {
+		name           string
+		input          io.Reader
+		reqMethod      string
+		reqTarget      string
+		expectedStatus int
+		expectedBody   interface{}
+		expectedSecret bool
+		mockBehavior   mockBehavior
+	}{
+		// CreateReminderHandler
+		{
+			name:           "CreateReminderHandler Minutes Before",
+			input:          bytes.NewBufferString(`{"minutes_before": 30, "channel": "webhook", "target": "https://example.com/hooks/reminders"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.ReminderResponseDto{
+				ID:            7,
+				TodoID:        1,
+				MinutesBefore: &minutesBefore,
+				Channel:       "webhook",
+				Target:        &target,
+				Status:        "pending",
+				CreatedAt:     "2024-09-05T05:24:16Z",
+			},
+			expectedSecret: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateReminder(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.CreateReminderParams) (database.Reminder, error) {
+					require.True(t, strings.HasPrefix(arg.Secret.String, "whsec_"))
+					arg.Secret = sql.NullString{}
+					require.Equal(t, database.CreateReminderParams{
+						MinutesBefore: sql.NullInt32{Int32: 30, Valid: true},
+						Channel:       database.ReminderChannelWebhook,
+						Target:        sql.NullString{String: target, Valid: true},
+						TodoID:        1,
+						OwnerID:       1,
+					}, arg)
+
+					return database.Reminder{
+						ID:            7,
+						TodoID:        1,
+						MinutesBefore: sql.NullInt32{Int32: 30, Valid: true},
+						Channel:       database.ReminderChannelWebhook,
+						Target:        sql.NullString{String: target, Valid: true},
+						CreatedAt:     createdAt,
+					}, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "CreateReminderHandler Remind At",
+			input:          bytes.NewBufferString(`{"remind_at": "` + remindAt.Format(time.RFC3339) + `", "channel": "email"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.ReminderResponseDto{
+				ID:        7,
+				TodoID:    1,
+				RemindAt:  stringPtr(remindAt.Format(time.RFC3339)),
+				Channel:   "email",
+				Status:    "pending",
+				CreatedAt: "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateReminder(gomock.Any(), database.CreateReminderParams{
+					RemindAt: sql.NullTime{Time: remindAt, Valid: true},
+					Channel:  database.ReminderChannelEmail,
+					TodoID:   1,
+					OwnerID:  1,
+				}).Return(database.Reminder{
+					ID:        7,
+					TodoID:    1,
+					RemindAt:  sql.NullTime{Time: remindAt, Valid: true},
+					Channel:   database.ReminderChannelEmail,
+					CreatedAt: createdAt,
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CreateReminderHandler Remind At In The Past",
+			input:          bytes.NewBufferString(`{"remind_at": "2024-09-05T05:24:16Z", "channel": "log"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1/reminders", service.ErrInvalidReminderTime.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Invalid Webhook Target",
+			input:          bytes.NewBufferString(`{"minutes_before": 30, "channel": "webhook", "target": "ftp://example.com"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1/reminders", service.ErrInvalidReminderTarget.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Private Webhook Target",
+			input:          bytes.NewBufferString(`{"minutes_before": 30, "channel": "webhook", "target": "http://10.0.0.1/hooks"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1/reminders", service.ErrInvalidReminderTarget.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Email Target",
+			input:          bytes.NewBufferString(`{"minutes_before": 30, "channel": "email", "target": "someone@example.com"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1/reminders", delivery.ErrInvalidReminderInput,
+				dto.FieldErrorDto{Field: "target", Rule: "excluded_unless", Code: "not_allowed"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Both Times",
+			input:          bytes.NewBufferString(`{"remind_at": "2024-09-05T05:24:16Z", "minutes_before": 30, "channel": "log"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1/reminders", delivery.ErrInvalidReminderInput,
+				dto.FieldErrorDto{Field: "remind_at", Rule: "excluded_with", Code: "not_allowed"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Invalid Input",
+			input:          bytes.NewBufferString(`{"channel": "webhook"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1/reminders", delivery.ErrInvalidReminderInput,
+				dto.FieldErrorDto{Field: "remind_at", Rule: "required_without", Code: "required"},
+				dto.FieldErrorDto{Field: "target", Rule: "required_if", Code: "required"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateReminderHandler Todo Not Found",
+			input:          bytes.NewBufferString(`{"minutes_before": 0, "channel": "log"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/reminders", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateReminder(gomock.Any(), gomock.Any()).Return(database.Reminder{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// GetRemindersHandler
+		{
+			name:           "GetRemindersHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.RemindersDto{Items: []dto.ReminderResponseDto{
+				{
+					ID:            7,
+					TodoID:        1,
+					MinutesBefore: &minutesBefore,
+					Channel:       "log",
+					Status:        "sent",
+					Attempts:      1,
+					SentAt:        stringPtr("2024-09-05T05:24:16Z"),
+					CreatedAt:     "2024-09-05T05:24:16Z",
+				},
+				{
+					ID:            8,
+					TodoID:        1,
+					MinutesBefore: &minutesBefore,
+					Channel:       "webhook",
+					Target:        &target,
+					Status:        "failed",
+					Attempts:      5,
+					LastError:     stringPtr("webhook responded with status 500"),
+					CreatedAt:     "2024-09-05T05:24:16Z",
+				},
+			}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1}, nil).Times(1)
+				repo.EXPECT().ListReminders(gomock.Any(), database.ListRemindersParams{TodoID: 1, OwnerID: 1}).Return([]database.Reminder{
+					{
+						ID:            7,
+						TodoID:        1,
+						MinutesBefore: sql.NullInt32{Int32: 30, Valid: true},
+						Channel:       database.ReminderChannelLog,
+						Attempts:      1,
+						SentAt:        sql.NullTime{Time: createdAt, Valid: true},
+						CreatedAt:     createdAt,
+					},
+					{
+						ID:            8,
+						TodoID:        1,
+						MinutesBefore: sql.NullInt32{Int32: 30, Valid: true},
+						Channel:       database.ReminderChannelWebhook,
+						Target:        sql.NullString{String: target, Valid: true},
+						Attempts:      5,
+						LastError:     sql.NullString{String: "webhook responded with status 500", Valid: true},
+						FailedAt:      sql.NullTime{Time: createdAt, Valid: true},
+						CreatedAt:     createdAt,
+					},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetRemindersHandler Todo Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1/reminders",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/reminders", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// DeleteReminderHandler
+		{
+			name:           "DeleteReminderHandler Success",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/reminders/7",
+			expectedStatus: http.StatusNoContent,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteReminder(gomock.Any(), database.DeleteReminderParams{ID: 7, TodoID: 1, OwnerID: 1}).Return(database.Reminder{ID: 7}, nil).Times(1)
+			},
+		},
+		{
+			name:           "DeleteReminderHandler Not Found",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/reminders/7",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/reminders/7", service.ErrReminderNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteReminder(gomock.Any(), gomock.Any()).Return(database.Reminder{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "DeleteReminderHandler Invalid ID",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1/reminders/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1/reminders/abc", delivery.ErrInvalidReminderID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedSecret {
+				var created dto.ReminderCreatedDto
+				require.NoError(t, json.Unmarshal(data, &created))
+				require.NotNil(t, created.Secret)
+				require.True(t, strings.HasPrefix(*created.Secret, "whsec_"))
+				require.Equal(t, tt.expectedBody, created.ReminderResponseDto)
+				require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			} else {
+				jsonExpected, _ := json.Marshal(tt.expectedBody)
+				require.Equal(t, jsonExpected, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+func (h TodoHandler) getSubtasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	subtasks, err := h.todoService.GetSubtasks(r.Context(), userID, todoID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingSubtasks)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, subtasks)
+}
+
+func (h TodoHandler) createSubtaskHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	todoInput := r.Context().Value(delivery.TodoInputKey).(dto.TodoInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	subtask, err := h.todoService.CreateSubtask(r.Context(), userID, todoID, todoInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingSubtask)
+		return
+	}
+
+	delivery.SetETag(w, subtask.Version, loc)
+	delivery.RespondWithJSON(w, http.StatusCreated, subtask)
+}
+
+func (h TodoHandler) setTodoParentHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	todoParent := r.Context().Value(delivery.TodoParentKey).(dto.TodoParentDto)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	updatedTodo, err := h.todoService.SetTodoParent(r.Context(), userID, todoID, todoParent, versions, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrSettingTodoParent)
+		return
+	}
+
+	delivery.SetETag(w, updatedTodo.Version, loc)
+	delivery.RespondWithJSON(w, http.StatusOK, updatedTodo)
+}
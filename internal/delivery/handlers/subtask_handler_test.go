@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestSubtaskHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	todo := func(id, parentID int32, status database.TodoStatus) database.Todo {
+		return database.Todo{
+			ID:          id,
+			Title:       "test",
+			Description: "test",
+			DueDate:     createdAt,
+			Status:      status,
+			ParentID:    sql.NullInt32{Int32: parentID, Valid: parentID != 0},
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			Version:     1,
+		}
+	}
+	todoDto := func(id int32, parentID *int32, status database.TodoStatus) dto.TodoResponseDto {
+		return dto.TodoResponseDto{
+			ID:          id,
+			Title:       "test",
+			Description: "test",
+			DueDate:     "2024-09-05T05:24:16Z",
+			Status:      string(status),
+			ParentID:    parentID,
+			Tags:        []string{},
+			BlockedBy:   []int32{},
+			CreatedAt:   "2024-09-05T05:24:16Z",
+			UpdatedAt:   "2024-09-05T05:24:16Z",
+			Version:     1,
+		}
+	}
+	int32Ptr := func(n int32) *int32 {
+		return &n
+	}
+
+	tests := []struct {
+		name           string
+		input          io.Reader
+		reqMethod      string
+		reqTarget      string
+		todoCfg        service.TodoConfig
+		expectedStatus int
+		expectedBody   interface{}
+		mockBehavior   mockBehavior
+	}{
+		// GetSubtasksHandler
+		{
+			name:           "GetSubtasksHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1/subtasks",
+			expectedStatus: http.StatusOK,
+			expectedBody: func() dto.SubtasksDto {
+				first := todoDto(2, int32Ptr(1), database.TodoStatusOpen)
+				first.Progress = int32Ptr(50)
+				return dto.SubtasksDto{Items: []dto.TodoNodeDto{
+					{TodoResponseDto: first, Subtasks: []dto.TodoNodeDto{
+						{TodoResponseDto: todoDto(4, int32Ptr(2), database.TodoStatusDone), Subtasks: []dto.TodoNodeDto{}},
+						{TodoResponseDto: todoDto(5, int32Ptr(2), database.TodoStatusOpen), Subtasks: []dto.TodoNodeDto{}},
+					}},
+					{TodoResponseDto: todoDto(3, int32Ptr(1), database.TodoStatusOpen), Subtasks: []dto.TodoNodeDto{}},
+				}}
+			}(),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, 0, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().ListSubtaskTree(gomock.Any(), database.ListSubtaskTreeParams{ID: 1, OwnerID: 1}).Return([]database.Todo{
+					todo(2, 1, database.TodoStatusOpen),
+					todo(3, 1, database.TodoStatusOpen),
+					todo(4, 2, database.TodoStatusDone),
+					todo(5, 2, database.TodoStatusOpen),
+				}, nil).Times(1)
+				repo.EXPECT().ListSubtaskProgress(gomock.Any(), []int32{2, 3, 4, 5}).Return([]database.ListSubtaskProgressRow{
+					{TodoID: 2, Total: 2, Done: 1},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetSubtasksHandler Todo Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1/subtasks",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/subtasks", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+
+		// CreateSubtaskHandler
+		{
+			name:           "CreateSubtaskHandler Success",
+			input:          bytes.NewBufferString(`{"title": "test", "description": "test", "due_date": "2024-09-05T05:24:16Z"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/subtasks",
+			expectedStatus: http.StatusCreated,
+			expectedBody: func() dto.TodoResponseDto {
+				subtask := todoDto(2, int32Ptr(1), database.TodoStatusOpen)
+				subtask.ProjectID = int32Ptr(5)
+				return subtask
+			}(),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				parent := todo(1, 0, database.TodoStatusOpen)
+				parent.ProjectID = sql.NullInt32{Int32: 5, Valid: true}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(parent, nil).Times(1)
+
+				subtask := todo(2, 1, database.TodoStatusOpen)
+				subtask.ProjectID = sql.NullInt32{Int32: 5, Valid: true}
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
+					Title:       "test",
+					Description: "test",
+					DueDate:     createdAt,
+					OwnerID:     1,
+					ProjectID:   sql.NullInt32{Int32: 5, Valid: true},
+					ParentID:    sql.NullInt32{Int32: 1, Valid: true},
+				}).Return(subtask, nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 1, OwnerID: 1}).Return(parent, nil).Times(1)
+			},
+		},
+		{
+			name:           "CreateSubtaskHandler Parent Not Found",
+			input:          bytes.NewBufferString(`{"title": "test", "description": "test", "due_date": "2024-09-05T05:24:16Z"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/subtasks",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/subtasks", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+
+		// SetTodoParentHandler
+		{
+			name:           "SetTodoParentHandler Success",
+			input:          bytes.NewBufferString(`{"parent_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(1, int32Ptr(2), database.TodoStatusOpen),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().ListTodoAncestors(gomock.Any(), database.ListTodoAncestorsParams{ID: 2, OwnerID: 1}).Return([]int32{2, 7}, nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, 3, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().SetTodoParent(gomock.Any(), database.SetTodoParentParams{
+					ID:       1,
+					ParentID: sql.NullInt32{Int32: 2, Valid: true},
+					OwnerID:  1,
+				}).Return(todo(1, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 3, OwnerID: 1}).Return(todo(3, 0, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 0, database.TodoStatusOpen), nil).Times(1)
+			},
+		},
+		{
+			name:           "SetTodoParentHandler Remove Parent",
+			input:          bytes.NewBufferString(`{"parent_id": null}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(1, nil, database.TodoStatusOpen),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().SetTodoParent(gomock.Any(), database.SetTodoParentParams{ID: 1, OwnerID: 1}).Return(todo(1, 0, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 0, database.TodoStatusOpen), nil).Times(1)
+			},
+		},
+		{
+			name:           "SetTodoParentHandler Same Parent",
+			input:          bytes.NewBufferString(`{"parent_id": 2}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(1, int32Ptr(2), database.TodoStatusOpen),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().ListTodoAncestors(gomock.Any(), database.ListTodoAncestorsParams{ID: 2, OwnerID: 1}).Return([]int32{2}, nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(1, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().SetTodoParent(gomock.Any(), database.SetTodoParentParams{
+					ID:       1,
+					ParentID: sql.NullInt32{Int32: 2, Valid: true},
+					OwnerID:  1,
+				}).Return(todo(1, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:           "SetTodoParentHandler Cycle",
+			input:          bytes.NewBufferString(`{"parent_id": 3}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/parent", service.ErrTodoCycle.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().ListTodoAncestors(gomock.Any(), database.ListTodoAncestorsParams{ID: 3, OwnerID: 1}).Return([]int32{3, 2, 1}, nil).Times(1)
+			},
+		},
+		{
+			name:           "SetTodoParentHandler Parent Not Found",
+			input:          bytes.NewBufferString(`{"parent_id": 9}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/1/parent", service.ErrParentTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().LockTodoDependencies(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().ListTodoAncestors(gomock.Any(), database.ListTodoAncestorsParams{ID: 9, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "SetTodoParentHandler Invalid Body",
+			input:          bytes.NewBufferString(`{"parent_id": 0}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/parent",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1/parent", delivery.ErrInvalidTodoParent,
+				dto.FieldErrorDto{Field: "parent_id", Rule: "min", Code: "too_small"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+
+		// Auto-completing parents
+		{
+			name:           "CompleteHandler Completes Parents",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/3/complete",
+			todoCfg:        service.TodoConfig{AutoCompleteParents: true},
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(3, int32Ptr(2), database.TodoStatusDone),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 3, OwnerID: 1}).Return(todo(3, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            3,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					OwnerID:       1,
+				}).Return(todo(3, 2, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 3, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CompleteParentTodo(gomock.Any(), database.CompleteParentTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 1, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, OwnerID: 1}, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 2, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CompleteParentTodo(gomock.Any(), database.CompleteParentTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+				events := []database.InsertOutboxEventParams{
+					{Event: "todo.updated", TodoID: 2},
+					{Event: "todo.completed", TodoID: 2},
+					{Event: "todo.updated", TodoID: 1},
+					{Event: "todo.completed", TodoID: 3},
+				}
+				for _, event := range events {
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, event.Event, arg.Event)
+						require.Equal(t, event.TodoID, arg.TodoID)
+						return nil
+					}).Times(1)
+				}
+			},
+		},
+		{
+			name:           "CompleteHandler Creates Next Occurrence Of Completed Parent",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/3/complete",
+			todoCfg:        service.TodoConfig{AutoCompleteParents: true},
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(3, int32Ptr(2), database.TodoStatusDone),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				parent := todo(2, 0, database.TodoStatusDone)
+				parent.Recurrence = sql.NullString{String: "FREQ=DAILY", Valid: true}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 3, OwnerID: 1}).Return(todo(3, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(3, 2, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 0, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 3, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CompleteParentTodo(gomock.Any(), database.CompleteParentTodoParams{ID: 2, OwnerID: 1}).Return(parent, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), database.CreateNextOccurrenceParams{
+					ID:         2,
+					DueDate:    createdAt.AddDate(0, 0, 1),
+					Recurrence: "FREQ=DAILY",
+				}).Return(database.CreateNextOccurrenceRow{ID: 4, SeriesID: sql.NullInt32{Int32: 2, Valid: true}}, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 2, OwnerID: 1}).Return(nil, nil).Times(1)
+				events := []database.InsertOutboxEventParams{
+					{Event: "todo.updated", TodoID: 2},
+					{Event: "todo.created", TodoID: 4},
+					{Event: "todo.completed", TodoID: 2},
+					{Event: "todo.completed", TodoID: 3},
+				}
+				for _, event := range events {
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, event.Event, arg.Event)
+						require.Equal(t, event.TodoID, arg.TodoID)
+						return nil
+					}).Times(1)
+				}
+			},
+		},
+		{
+			name:           "CompleteHandler Leaves Parents",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/3/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(3, int32Ptr(2), database.TodoStatusDone),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 3, OwnerID: 1}).Return(todo(3, 2, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            3,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					OwnerID:       1,
+				}).Return(todo(3, 2, database.TodoStatusDone), nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 2, OwnerID: 1}).Return(todo(2, 1, database.TodoStatusOpen), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 3, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTodoDefaults(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, tt.todoCfg)
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			require.Equal(t, jsonExpected, data)
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/service"
+)
+
+// TagHandler manages tags-related operations.
+type TagHandler struct {
+	tagService service.Tags
+}
+
+func newTagHandler(tagService service.Tags) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+func (h TagHandler) getTagsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+
+	tags, err := h.tagService.GetTags(r.Context(), userID)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingTags)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, tags)
+}
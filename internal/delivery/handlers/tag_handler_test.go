@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestTagHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	tests := []struct {
+		name           string
+		reqTarget      string
+		expectedStatus int
+		expectedBody   interface{}
+		mockBehavior   mockBehavior
+	}{
+		{
+			name:           "GetTagsHandler Success",
+			reqTarget:      "/tags",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TagsDto{Items: []dto.TagResponseDto{
+				{ID: 2, Name: "bug", TodoCount: 3},
+				{ID: 1, Name: "frontend", TodoCount: 0},
+			}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTags(gomock.Any(), int32(1)).Return([]database.ListTagsRow{
+					{ID: 2, Name: "bug", TodoCount: 3},
+					{ID: 1, Name: "frontend", TodoCount: 0},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetTagsHandler No Tags",
+			reqTarget:      "/tags",
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.TagsDto{Items: []dto.TagResponseDto{}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTags(gomock.Any(), int32(1)).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetTagsHandler Repo Error",
+			reqTarget:      "/tags",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tags", delivery.ErrGettingTags),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTags(gomock.Any(), int32(1)).Return(nil, errors.New("some db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.reqTarget, nil)
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			require.Equal(t, jsonExpected, data)
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
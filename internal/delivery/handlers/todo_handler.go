@@ -1,10 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"github.com/go-playground/validator/v10"
-	"log"
 	"net/http"
-	"strings"
+	"time"
 	"to-do-list-go/internal/delivery"
 	"to-do-list-go/internal/delivery/dto"
 	"to-do-list-go/internal/service"
@@ -24,42 +24,62 @@ func newTodoHandler(todoService service.Todos, validator *validator.Validate) *T
 }
 
 func (h TodoHandler) createTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
 	todoInput := r.Context().Value(delivery.TodoInputKey).(dto.TodoInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
 
-	todo, err := h.todoService.CreateTodo(todoInput)
+	todo, err := h.todoService.CreateTodo(r.Context(), userID, todoInput, loc)
 	if err != nil {
-		log.Printf(delivery.ErrCreatingTodo+": %s\n", err)
-		delivery.RespondWithError(w, http.StatusInternalServerError, delivery.ErrCreatingTodo)
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingTodo)
 		return
 	}
 
+	delivery.SetETag(w, todo.Version, loc)
 	delivery.RespondWithJSON(w, http.StatusCreated, todo)
 }
 
 func (h TodoHandler) getTodosHandler(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.todoService.GetTodos()
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todosQuery := r.Context().Value(delivery.TodosQueryKey).(dto.TodosQueryDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	todos, err := h.todoService.GetTodos(r.Context(), userID, todosQuery, loc)
 	if err != nil {
-		log.Printf(delivery.ErrGettingTodos+": %s\n", err)
-		delivery.RespondWithError(w, http.StatusInternalServerError, delivery.ErrGettingTodos)
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingTodos)
 		return
 	}
 
 	delivery.RespondWithJSON(w, http.StatusOK, todos)
 }
 
+func (h TodoHandler) searchTodosHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	searchQuery := r.Context().Value(delivery.TodoSearchQueryKey).(dto.TodoSearchQueryDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	results, err := h.todoService.SearchTodos(r.Context(), userID, searchQuery, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrSearchingTodos)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, results)
+}
+
 func (h TodoHandler) getTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
 	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
 
-	todo, err := h.todoService.GetTodo(todoID)
+	todo, err := h.todoService.GetTodo(r.Context(), userID, todoID, loc)
 	if err != nil {
-		if strings.HasPrefix(err.Error(), delivery.ErrTodoNotFound) {
-			log.Println(err)
-			delivery.RespondWithError(w, http.StatusNotFound, delivery.ErrTodoNotFound)
-			return
-		}
-
-		log.Printf(delivery.ErrGettingTodo+": %s\n", err)
-		delivery.RespondWithError(w, http.StatusInternalServerError, delivery.ErrGettingTodo)
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingTodo)
+		return
+	}
+
+	delivery.SetETag(w, todo.Version, loc)
+	if delivery.MatchesIfNoneMatch(r.Header.Get("If-None-Match"), todo.Version, loc) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -67,39 +87,97 @@ func (h TodoHandler) getTodoHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h TodoHandler) updateTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
 	todoID := r.Context().Value(delivery.TodoIDKey).(int)
 	todoInput := r.Context().Value(delivery.TodoInputKey).(dto.TodoInputDto)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
 
-	updatedTodo, err := h.todoService.UpdateTodo(todoID, todoInput)
+	updatedTodo, err := h.todoService.UpdateTodo(r.Context(), userID, todoID, todoInput, versions, loc)
 	if err != nil {
-		if strings.HasPrefix(err.Error(), delivery.ErrTodoNotFound) {
-			log.Println(err)
-			delivery.RespondWithError(w, http.StatusNotFound, delivery.ErrTodoNotFound)
-			return
-		}
-
-		log.Printf(delivery.ErrUpdatingTodo+": %s\n", err)
-		delivery.RespondWithError(w, http.StatusInternalServerError, delivery.ErrUpdatingTodo)
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrUpdatingTodo)
 		return
 	}
 
+	delivery.SetETag(w, updatedTodo.Version, loc)
 	delivery.RespondWithJSON(w, http.StatusOK, updatedTodo)
 }
 
-func (h TodoHandler) deleteTodoHandler(w http.ResponseWriter, r *http.Request) {
+func (h TodoHandler) patchTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
 	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	todoPatch := r.Context().Value(delivery.TodoPatchKey).(dto.TodoPatchDto)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
 
-	if err := h.todoService.DeleteTodo(todoID); err != nil {
-		if strings.HasPrefix(err.Error(), delivery.ErrTodoNotFound) {
-			log.Println(err)
-			delivery.RespondWithError(w, http.StatusNotFound, delivery.ErrTodoNotFound)
-			return
-		}
+	patchedTodo, err := h.todoService.PatchTodo(r.Context(), userID, todoID, todoPatch, versions, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrPatchingTodo)
+		return
+	}
 
-		log.Printf(delivery.ErrDeletingTodo+": %s\n", err)
-		delivery.RespondWithError(w, http.StatusInternalServerError, delivery.ErrDeletingTodo)
+	delivery.SetETag(w, patchedTodo.Version, loc)
+	delivery.RespondWithJSON(w, http.StatusOK, patchedTodo)
+}
+
+func (h TodoHandler) deleteTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+
+	if err := h.todoService.DeleteTodo(r.Context(), userID, todoID, versions); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrDeletingTodo)
 		return
 	}
 
 	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
 }
+
+func (h TodoHandler) moveTodoHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	todoMove := r.Context().Value(delivery.TodoMoveKey).(dto.TodoMoveDto)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	movedTodo, err := h.todoService.MoveTodo(r.Context(), userID, todoID, todoMove, versions, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrMovingTodo)
+		return
+	}
+
+	delivery.SetETag(w, movedTodo.Version, loc)
+	delivery.RespondWithJSON(w, http.StatusOK, movedTodo)
+}
+
+func (h TodoHandler) startTodoHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTodoStatus(w, r, h.todoService.StartTodo)
+}
+
+func (h TodoHandler) completeTodoHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTodoStatus(w, r, h.todoService.CompleteTodo)
+}
+
+func (h TodoHandler) cancelTodoHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTodoStatus(w, r, h.todoService.CancelTodo)
+}
+
+func (h TodoHandler) reopenTodoHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTodoStatus(w, r, h.todoService.ReopenTodo)
+}
+
+func (h TodoHandler) changeTodoStatus(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	todoID := r.Context().Value(delivery.TodoIDKey).(int)
+	versions := r.Context().Value(delivery.IfMatchKey).([]int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	todo, err := change(r.Context(), userID, todoID, versions, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrChangingTodoStatus)
+		return
+	}
+
+	delivery.SetETag(w, todo.Version, loc)
+	delivery.RespondWithJSON(w, http.StatusOK, todo)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi"
@@ -23,16 +24,19 @@ import (
 )
 
 func TestCreateTodoHandler(t *testing.T) {
-	type mockBehavior func(ctx context.Context, repo *mock_repo.MockRepository)
+	type mockBehavior func(repo *mock_repo.MockRepository)
 
 	tests := []struct {
-		name           string
-		input          io.Reader
-		reqMethod      string
-		reqTarget      string
-		expectedStatus int
-		expectedBody   interface{}
-		mockBehavior   mockBehavior
+		name            string
+		input           io.Reader
+		contentType     string
+		reqHeaders      map[string]string
+		reqMethod       string
+		reqTarget       string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    interface{}
+		mockBehavior    mockBehavior
 	}{
 		// CreateHandler
 		{
@@ -50,26 +54,90 @@ func TestCreateTodoHandler(t *testing.T) {
 				Title:       "test",
 				Description: "test",
 				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				newTodo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					OwnerID:     1,
+				}).Return(newTodo, nil).Times(1)
+			},
+		},
+		{
+			name: "CreateHandler With Tags",
+			input: bytes.NewBuffer([]byte(`{
+				"title": "test",
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00",
+				"tags": ["frontend", "Bug"]
+			}`)),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusCreated,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{"bug", "frontend"},
+				BlockedBy:   []int32{},
 				CreatedAt:   "2024-09-05T12:24:16+07:00",
 				UpdatedAt:   "2024-09-05T12:24:16+07:00",
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
 				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
 				newTodo := database.Todo{
 					ID:          1,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
 					CreatedAt:   createdUpdatedAt,
 					UpdatedAt:   createdUpdatedAt,
 				}
-				repo.EXPECT().CreateTodo(ctx, database.CreateTodoParams{
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
+					OwnerID:     1,
 				}).Return(newTodo, nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{
+					TodoID:  1,
+					OwnerID: 1,
+					Names:   []string{"bug", "frontend"},
+				}).Return(nil).Times(1)
 			},
 		},
+		{
+			name: "CreateHandler Invalid Tags",
+			input: bytes.NewBuffer([]byte(`{
+				"title": "test",
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00",
+				"tags": ["bug,urgent"]
+			}`)),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "tags[0]", Rule: "excludesall", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
 		{
 			name: "CreateHandler Invalid Input 1",
 			input: bytes.NewBuffer([]byte(`{
@@ -80,12 +148,23 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPost,
 			reqTarget:      "/tasks",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidInput,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name: "CreateHandler Invalid Field Type",
+			input: bytes.NewBuffer([]byte(`{
+				"title": 1,
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00"
+			}`)),
+			reqMethod:       http.MethodPost,
+			reqTarget:       "/tasks",
+			expectedStatus:  http.StatusBadRequest,
+			expectedHeaders: map[string]string{"Content-Type": "application/problem+json"},
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "title", Rule: "type", Code: "invalid_type"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name: "CreateHandler Invalid Input 2",
@@ -96,12 +175,9 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPost,
 			reqTarget:      "/tasks",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidInput,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "title", Rule: "required", Code: "required"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name: "CreateHandler Repo Error",
@@ -113,19 +189,38 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPost,
 			reqTarget:      "/tasks",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrCreatingTodo,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
-				repo.EXPECT().CreateTodo(ctx, database.CreateTodoParams{
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks", delivery.ErrCreatingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
+					OwnerID:     1,
 				}).Return(database.Todo{}, errors.New("some db error")).Times(1)
 			},
 		},
+		{
+			name: "CreateHandler Outbox Error",
+			input: bytes.NewBuffer([]byte(`{
+				"title": "test",
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00"
+			}`)),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks", delivery.ErrCreatingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateTodo(gomock.Any(), gomock.Any()).Return(database.Todo{ID: 1}, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+					require.Equal(t, "todo.created", arg.Event)
+					require.Equal(t, int32(1), arg.UserID)
+					require.Equal(t, int32(1), arg.TodoID)
+					return errors.New("some db error")
+				}).Times(1)
+			},
+		},
 
 		// GetTodosHandler
 		{
@@ -134,29 +229,216 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks",
 			expectedStatus: http.StatusOK,
-			expectedBody: []dto.TodoResponseDto{
-				{
-					ID:          1,
-					Title:       "test",
-					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
-					CreatedAt:   "2024-09-05T12:24:16+07:00",
-					UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			expectedBody: dto.TodosPageDto{
+				Items: []dto.TodoResponseDto{
+					{
+						ID:          1,
+						Title:       "test",
+						Description: "test",
+						DueDate:     "2024-09-05T12:40:16+07:00",
+						Tags:        []string{},
+						BlockedBy:   []int32{},
+						CreatedAt:   "2024-09-05T12:24:16+07:00",
+						UpdatedAt:   "2024-09-05T12:24:16+07:00",
+					},
+				},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todos := []database.Todo{
+					{
+						ID:          1,
+						Title:       "test",
+						Description: "test",
+						DueDate:     dueDate,
+						CreatedAt:   createdUpdatedAt,
+						UpdatedAt:   createdUpdatedAt,
+					},
+				}
+				repo.EXPECT().ListTodos(gomock.Any(), database.ListTodosParams{
+					SortKey1: "created_at",
+					RowLimit: 21,
+					OwnerID:  1,
+				}).Return(todos, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetTodosHandler Next Page Cursor",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?limit=1&sort=-due_date,created_at&status=open,in_progress&due_from=2024-09-01T00:00:00Z",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodosPageDto{
+				Items: []dto.TodoResponseDto{
+					{
+						ID:          2,
+						Title:       "test",
+						Description: "test",
+						DueDate:     "2024-09-05T12:40:16+07:00",
+						Status:      "open",
+						Tags:        []string{},
+						BlockedBy:   []int32{},
+						CreatedAt:   "2024-09-05T12:24:16+07:00",
+						UpdatedAt:   "2024-09-05T12:24:16+07:00",
+					},
 				},
+				NextCursor: stringPtr(base64.RawURLEncoding.EncodeToString([]byte(
+					`{"s":"-due_date,created_at","k1":"2024-09-05T12:40:16+07:00","k2":"2024-09-05T12:24:16+07:00","id":2}`,
+				))),
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				dueFrom, _ := time.Parse(time.RFC3339, "2024-09-01T00:00:00Z")
 				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
 				todos := []database.Todo{
+					{
+						ID:          2,
+						Title:       "test",
+						Description: "test",
+						DueDate:     dueDate,
+						Status:      database.TodoStatusOpen,
+						CreatedAt:   createdUpdatedAt,
+						UpdatedAt:   createdUpdatedAt,
+					},
 					{
 						ID:          1,
 						Title:       "test",
 						Description: "test",
+						DueDate:     dueDate,
+						Status:      database.TodoStatusOpen,
+						CreatedAt:   createdUpdatedAt,
+						UpdatedAt:   createdUpdatedAt,
+					},
+				}
+				repo.EXPECT().ListTodos(gomock.Any(), database.ListTodosParams{
+					SortKey1:     "due_date",
+					SortKey1Desc: true,
+					SortKey2:     "created_at",
+					Statuses:     []string{"open", "in_progress"},
+					DueFrom:      sql.NullTime{Time: dueFrom, Valid: true},
+					RowLimit:     2,
+					OwnerID:      1,
+				}).Return(todos, nil).Times(1)
+			},
+		},
+		{
+			name:      "GetTodosHandler With Cursor",
+			input:     nil,
+			reqMethod: http.MethodGet,
+			reqTarget: "/tasks?sort=updated_at&cursor=" + base64.RawURLEncoding.EncodeToString([]byte(
+				`{"s":"updated_at","k1":"2024-09-05T12:24:16+07:00","id":7}`,
+			)),
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodosPageDto{
+				Items: []dto.TodoResponseDto{},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				cursorKey, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				repo.EXPECT().ListTodos(gomock.Any(), database.ListTodosParams{
+					SortKey1:   "updated_at",
+					CursorID:   sql.NullInt32{Int32: 7, Valid: true},
+					CursorKey1: sql.NullTime{Time: cursorKey, Valid: true},
+					RowLimit:   21,
+					OwnerID:    1,
+				}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:      "GetTodosHandler Cursor For Another Sort",
+			input:     nil,
+			reqMethod: http.MethodGet,
+			reqTarget: "/tasks?sort=due_date&cursor=" + base64.RawURLEncoding.EncodeToString([]byte(
+				`{"s":"updated_at","k1":"2024-09-05T12:24:16+07:00","id":7}`,
+			)),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks", service.ErrInvalidCursor.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "GetTodosHandler Invalid Query 1",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidTodosQuery,
+				dto.FieldErrorDto{Field: "limit", Rule: "max", Code: "too_large"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "GetTodosHandler Invalid Query 2",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?sort=due_date,-due_date",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidTodosQuery),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "GetTodosHandler Invalid Query 3",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?status=archived",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidTodosQuery,
+				dto.FieldErrorDto{Field: "status[0]", Rule: "oneof", Code: "not_allowed"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "GetTodosHandler Invalid Query 4",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?tag=bug&tag_mode=none",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidTodosQuery,
+				dto.FieldErrorDto{Field: "tag_mode", Rule: "oneof", Code: "not_allowed"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "GetTodosHandler Tag Filter",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks?tag=Urgent&tag=bug,frontend&tag_mode=all",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodosPageDto{
+				Items: []dto.TodoResponseDto{
+					{
+						ID:          3,
+						Title:       "test",
+						Description: "test",
 						DueDate:     "2024-09-05T12:40:16+07:00",
+						Tags:        []string{"bug", "frontend", "urgent"},
+						BlockedBy:   []int32{},
+						CreatedAt:   "2024-09-05T12:24:16+07:00",
+						UpdatedAt:   "2024-09-05T12:24:16+07:00",
+					},
+				},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todos := []database.Todo{
+					{
+						ID:          3,
+						Title:       "test",
+						Description: "test",
+						DueDate:     dueDate,
 						CreatedAt:   createdUpdatedAt,
 						UpdatedAt:   createdUpdatedAt,
 					},
 				}
-				repo.EXPECT().GetTodos(ctx).Return(todos, nil).Times(1)
+				repo.EXPECT().ListTodos(gomock.Any(), database.ListTodosParams{
+					SortKey1: "created_at",
+					RowLimit: 21,
+					OwnerID:  1,
+					Tags:     []string{"bug", "frontend", "urgent"},
+					AllTags:  true,
+				}).Return(todos, nil).Times(1)
+				repo.EXPECT().ListTodoTags(gomock.Any(), []int32{3}).Return([]database.ListTodoTagsRow{
+					{TodoID: 3, Name: "bug"},
+					{TodoID: 3, Name: "frontend"},
+					{TodoID: 3, Name: "urgent"},
+				}, nil).Times(1)
 			},
 		},
 		{
@@ -165,13 +447,88 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrGettingTodos,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks", delivery.ErrGettingTodos),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodos(gomock.Any(), gomock.Any()).Return(nil, errors.New("some db error")).Times(1)
+			},
+		},
+
+		// SearchTodosHandler
+		{
+			name:           "SearchTodosHandler Success",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/search?q=report&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoSearchResultsDto{
+				Items: []dto.TodoSearchResultDto{
+					{
+						Todo: dto.TodoResponseDto{
+							ID:          1,
+							Title:       "weekly report",
+							Description: "send <b>report</b>",
+							DueDate:     "2024-09-05T12:40:16+07:00",
+							Tags:        []string{},
+							BlockedBy:   []int32{},
+							CreatedAt:   "2024-09-05T12:24:16+07:00",
+							UpdatedAt:   "2024-09-05T12:24:16+07:00",
+						},
+						Rank: 0.6,
+						Highlights: dto.TodoHighlightsDto{
+							Title:       "weekly <mark>report</mark>",
+							Description: "send &lt;b&gt;<mark>report</mark>&lt;/b&gt; &lt;mark&gt;",
+						},
+					},
+				},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				rows := []database.SearchTodosRow{
+					{
+						Todo: database.Todo{
+							ID:          1,
+							Title:       "weekly report",
+							Description: "send <b>report</b>",
+							DueDate:     dueDate,
+							CreatedAt:   createdUpdatedAt,
+							UpdatedAt:   createdUpdatedAt,
+						},
+						Rank:                 0.6,
+						TitleHighlight:       "weekly \x02report\x03",
+						DescriptionHighlight: "send <b>\x02report\x03</b> <mark>",
+					},
+				}
+				repo.EXPECT().SearchTodos(gomock.Any(), database.SearchTodosParams{
+					Query:    "report",
+					RowLimit: 5,
+					OwnerID:  1,
+				}).Return(rows, nil).Times(1)
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
-				repo.EXPECT().GetTodos(ctx).Return(nil, errors.New("some db error")).Times(1)
+		},
+		{
+			name:           "SearchTodosHandler Empty Query",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/search?q=%20",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/search", delivery.ErrInvalidSearchQuery,
+				dto.FieldErrorDto{Field: "q", Rule: "required", Code: "required"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "SearchTodosHandler Repo Error",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/search?q=report",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/search", delivery.ErrSearchingTodos),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().SearchTodos(gomock.Any(), database.SearchTodosParams{
+					Query:    "report",
+					RowLimit: 20,
+					OwnerID:  1,
+				}).Return(nil, errors.New("some db error")).Times(1)
 			},
 		},
 
@@ -187,21 +544,24 @@ func TestCreateTodoHandler(t *testing.T) {
 				Title:       "test",
 				Description: "test",
 				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
 				CreatedAt:   "2024-09-05T12:24:16+07:00",
 				UpdatedAt:   "2024-09-05T12:24:16+07:00",
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
 				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
 				todo := database.Todo{
 					ID:          1,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
 					CreatedAt:   createdUpdatedAt,
 					UpdatedAt:   createdUpdatedAt,
 				}
 				todoID := int32(1)
-				repo.EXPECT().GetTodo(ctx, todoID).Return(todo, nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: todoID, OwnerID: 1}).Return(todo, nil).Times(1)
 			},
 		},
 		{
@@ -210,12 +570,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks/a",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/a", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name:           "GetTodoHandler Invalid ID 2",
@@ -223,12 +579,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks/-1",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/-1", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name:           "GetTodoHandler Todo Not Found",
@@ -236,14 +588,10 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks/11",
 			expectedStatus: http.StatusNotFound,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrTodoNotFound,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
 				todoID := int32(11)
-				repo.EXPECT().GetTodo(ctx, todoID).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: todoID, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
 			},
 		},
 		{
@@ -252,18 +600,146 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodGet,
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrGettingTodo,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/1", delivery.ErrGettingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
 				todoID := int32(1)
-				repo.EXPECT().GetTodo(ctx, todoID).Return(database.Todo{}, errors.New("some db error")).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: todoID, OwnerID: 1}).Return(database.Todo{}, errors.New("some db error")).Times(1)
+			},
+		},
+
+		{
+			name:           "GetTodoHandler Timezone Query",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1?tz=UTC",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T05:40:16Z",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T05:24:16Z",
+				UpdatedAt:   "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetTodoHandler Invalid Timezone",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1?tz=Mars/Olympus",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidTZ),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+
+		{
+			name:            "GetTodoHandler ETag",
+			input:           nil,
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"3"`},
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+				Version:     3,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+					Version:     3,
+				}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:            "GetTodoHandler Not Modified",
+			input:           nil,
+			reqHeaders:      map[string]string{"If-None-Match": `"2", W/"3"`},
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusNotModified,
+			expectedHeaders: map[string]string{"ETag": `"3"`},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Version: 3}, nil).Times(1)
+			},
+		},
+		{
+			name:            "GetTodoHandler Modified In Another Timezone",
+			input:           nil,
+			reqHeaders:      map[string]string{"If-None-Match": `"3"`, "X-Timezone": "UTC"},
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"3@UTC"`, "Vary": "Authorization, X-Timezone"},
+			expectedBody: dto.TodoResponseDto{
+				ID:        1,
+				DueDate:   "2024-09-05T05:40:16Z",
+				Tags:      []string{},
+				BlockedBy: []int32{},
+				CreatedAt: "2024-09-05T05:24:16Z",
+				UpdatedAt: "2024-09-05T05:24:16Z",
+				Version:   3,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{ID: 1, DueDate: dueDate, CreatedAt: createdUpdatedAt, UpdatedAt: createdUpdatedAt, Version: 3}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
 			},
 		},
 
 		// UpdateHandler
+		{
+			name:           "GetTodoHandler Timeout",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   problem(http.StatusGatewayTimeout, "/tasks/1", delivery.ErrRequestTimeout),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, context.DeadlineExceeded).Times(1)
+			},
+		},
+		{
+			name:           "GetTodoHandler Client Closed Request",
+			input:          nil,
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/tasks/1",
+			expectedStatus: delivery.StatusClientClosedRequest,
+			expectedBody:   problem(delivery.StatusClientClosedRequest, "/tasks/1", delivery.ErrClientClosedRequest),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, context.Canceled).Times(1)
+			},
+		},
 		{
 			name: "UpdateHandler Success",
 			input: bytes.NewBuffer([]byte(`{
@@ -279,48 +755,99 @@ func TestCreateTodoHandler(t *testing.T) {
 				Title:       "test",
 				Description: "test",
 				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
 				CreatedAt:   "2024-09-05T12:24:16+07:00",
 				UpdatedAt:   "2024-09-05T12:24:16+07:00",
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
 				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
 				todo := database.Todo{
 					ID:          1,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
 					CreatedAt:   createdUpdatedAt,
 					UpdatedAt:   createdUpdatedAt,
 				}
 				todoID := int32(1)
-				repo.EXPECT().UpdateTodo(ctx, database.UpdateTodoParams{
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
 					ID:          todoID,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
+					OwnerID:     1,
 				}).Return(todo, nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{TodoID: 1, OwnerID: 1}).Return(nil).Times(1)
 			},
 		},
 		{
-			name: "UpdateHandler Invalid Input 1",
+			name: "UpdateHandler With Tags",
 			input: bytes.NewBuffer([]byte(`{
 				"title": "test",
 				"description": "test",
-				"due_date": "2024-09-05T12:40:16+07:00"
+				"due_date": "2024-09-05T12:40:16+07:00",
+				"tags": ["Urgent", " bug", "bug"]
 			}`)),
 			reqMethod:      http.MethodPut,
-			reqTarget:      "/tasks/a",
-			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
-		},
-		{
-			name: "UpdateHandler Invalid Input 2",
-			input: bytes.NewBuffer([]byte(`{
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{"bug", "urgent"},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					OwnerID:     1,
+				}).Return(todo, nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{
+					TodoID:  1,
+					OwnerID: 1,
+					Names:   []string{"bug", "urgent"},
+				}).Return(nil).Times(1)
+				repo.EXPECT().ListTodoTags(gomock.Any(), []int32{1}).Return([]database.ListTodoTagsRow{
+					{TodoID: 1, Name: "bug"},
+					{TodoID: 1, Name: "urgent"},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name: "UpdateHandler Invalid Input 1",
+			input: bytes.NewBuffer([]byte(`{
+				"title": "test",
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00"
+			}`)),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/tasks/a",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/a", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name: "UpdateHandler Invalid Input 2",
+			input: bytes.NewBuffer([]byte(`{
 				"title": "test",
 				"description": "test",
 				"due_date": "2024-09-05T12:40:16+07:00"
@@ -328,12 +855,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPut,
 			reqTarget:      "/tasks/-1",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/-1", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name: "UpdateHandler Invalid Input 3",
@@ -345,12 +868,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPut,
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidInput,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidInput),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name: "UpdateHandler Invalid Input 4",
@@ -361,12 +880,9 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPut,
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidInput,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "title", Rule: "required", Code: "required"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name: "UpdateHandler Todo Not Found",
@@ -378,18 +894,16 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPut,
 			reqTarget:      "/tasks/11",
 			expectedStatus: http.StatusNotFound,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrTodoNotFound,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
 				todoID := int32(11)
-				repo.EXPECT().UpdateTodo(ctx, database.UpdateTodoParams{
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
 					ID:          todoID,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
+					OwnerID:     1,
 				}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
 			},
 		},
@@ -403,22 +917,386 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodPut,
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrUpdatingTodo,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/1", delivery.ErrUpdatingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
 				todoID := int32(1)
-				repo.EXPECT().UpdateTodo(ctx, database.UpdateTodoParams{
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
 					ID:          todoID,
 					Title:       "test",
 					Description: "test",
-					DueDate:     "2024-09-05T12:40:16+07:00",
+					DueDate:     dueDate,
+					OwnerID:     1,
 				}).Return(database.Todo{}, errors.New("some db error")).Times(1)
 			},
 		},
 
+		{
+			name: "UpdateHandler Precondition Failed",
+			input: bytes.NewBuffer([]byte(`{
+				"title": "test",
+				"description": "test",
+				"due_date": "2024-09-05T12:40:16+07:00"
+			}`)),
+			reqHeaders:     map[string]string{"If-Match": `"2"`},
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   problem(http.StatusPreconditionFailed, "/tasks/1", service.ErrTodoModified.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					Versions:    []int32{2},
+					OwnerID:     1,
+				}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Version: 3}, nil).Times(1)
+			},
+		},
+		{
+			name:            "GetTodoHandler Modified In Another Timezone",
+			input:           nil,
+			reqHeaders:      map[string]string{"If-None-Match": `"3"`, "X-Timezone": "UTC"},
+			reqMethod:       http.MethodGet,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"3@UTC"`, "Vary": "Authorization, X-Timezone"},
+			expectedBody: dto.TodoResponseDto{
+				ID:        1,
+				DueDate:   "2024-09-05T05:40:16Z",
+				Tags:      []string{},
+				BlockedBy: []int32{},
+				CreatedAt: "2024-09-05T05:24:16Z",
+				UpdatedAt: "2024-09-05T05:24:16Z",
+				Version:   3,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{ID: 1, DueDate: dueDate, CreatedAt: createdUpdatedAt, UpdatedAt: createdUpdatedAt, Version: 3}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+			},
+		},
+
+		// PatchHandler
+		{
+			name:           "PatchHandler Merge Patch Success",
+			input:          bytes.NewBuffer([]byte(`{"title": "patched"}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "patched",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "patched",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:      1,
+					Title:   sql.NullString{String: "patched", Valid: true},
+					OwnerID: 1,
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:            "PatchHandler If-Match Success",
+			input:           bytes.NewBuffer([]byte(`{"title": "patched"}`)),
+			reqHeaders:      map[string]string{"If-Match": `"3"`},
+			reqMethod:       http.MethodPatch,
+			reqTarget:       "/tasks/1",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"4"`},
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "patched",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+				Version:     4,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "patched",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+					Version:     4,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:       1,
+					Title:    sql.NullString{String: "patched", Valid: true},
+					Versions: []int32{3},
+					OwnerID:  1,
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:           "PatchHandler JSON Patch Success",
+			input:          bytes.NewBuffer([]byte(`[{"op": "replace", "path": "/due_date", "value": "2024-09-05T12:40:16+07:00"}]`)),
+			contentType:    "application/json-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:      1,
+					DueDate: sql.NullTime{Time: dueDate, Valid: true},
+					OwnerID: 1,
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:           "PatchHandler Merge Patch Removes Field",
+			input:          bytes.NewBuffer([]byte(`{"description": null}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler Invalid Due Date",
+			input:          bytes.NewBuffer([]byte(`{"due_date": "tomorrow"}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch,
+				dto.FieldErrorDto{Field: "due_date", Rule: "rfc3339", Code: "invalid_datetime"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler Merge Patch Tags And Recurrence",
+			input:          bytes.NewBuffer([]byte(`{"tags": ["Urgent", "bug"], "recurrence": "RRULE:freq=daily"}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{"bug", "urgent"},
+				BlockedBy:   []int32{},
+				Recurrence:  stringPtr("FREQ=DAILY"),
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					Recurrence:  sql.NullString{String: "FREQ=DAILY", Valid: true},
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:         1,
+					Recurrence: sql.NullString{String: "FREQ=DAILY", Valid: true},
+					OwnerID:    1,
+				}).Return(todo, nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{
+					TodoID:  1,
+					OwnerID: 1,
+					Names:   []string{"bug", "urgent"},
+				}).Return(nil).Times(1)
+				repo.EXPECT().ListTodoTags(gomock.Any(), []int32{1}).Return([]database.ListTodoTagsRow{
+					{TodoID: 1, Name: "bug"},
+					{TodoID: 1, Name: "urgent"},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "PatchHandler Merge Patch Removes Tags And Recurrence",
+			input:          bytes.NewBuffer([]byte(`{"tags": null, "recurrence": null}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:               1,
+					RemoveRecurrence: true,
+					OwnerID:          1,
+				}).Return(todo, nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{TodoID: 1, OwnerID: 1}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:           "PatchHandler JSON Patch Removes Recurrence",
+			input:          bytes.NewBuffer([]byte(`[{"op": "remove", "path": "/recurrence"}]`)),
+			contentType:    "application/json-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:               1,
+					RemoveRecurrence: true,
+					OwnerID:          1,
+				}).Return(todo, nil).Times(1)
+			},
+		},
+		{
+			name:           "PatchHandler Invalid Recurrence",
+			input:          bytes.NewBuffer([]byte(`{"recurrence": "FREQ=FORTNIGHTLY"}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch,
+				dto.FieldErrorDto{Field: "recurrence", Rule: "rrule", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler Invalid Tag",
+			input:          bytes.NewBuffer([]byte(`{"tags": ["a,b"]}`)),
+			contentType:    "application/merge-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch,
+				dto.FieldErrorDto{Field: "tags[0]", Rule: "excludesall", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler JSON Patch Removes Required Field",
+			input:          bytes.NewBuffer([]byte(`[{"op": "remove", "path": "/title"}]`)),
+			contentType:    "application/json-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler JSON Patch Unsupported Operation",
+			input:          bytes.NewBuffer([]byte(`[{"op": "move", "from": "/title", "path": "/description"}]`)),
+			contentType:    "application/json-patch+json",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/1", delivery.ErrInvalidPatch),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler Unsupported Content Type",
+			input:          bytes.NewBuffer([]byte(`title=patched`)),
+			contentType:    "application/x-www-form-urlencoded",
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedBody:   problem(http.StatusUnsupportedMediaType, "/tasks/1", delivery.ErrUnsupportedPatchType),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "PatchHandler Todo Not Found",
+			input:          bytes.NewBuffer([]byte(`{"title": "patched"}`)),
+			reqMethod:      http.MethodPatch,
+			reqTarget:      "/tasks/11",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().PatchTodo(gomock.Any(), database.PatchTodoParams{
+					ID:      11,
+					Title:   sql.NullString{String: "patched", Valid: true},
+					OwnerID: 1,
+				}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+
 		// DeleteHandler
 		{
 			name:           "DeleteHandler Success",
@@ -427,9 +1305,52 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusNoContent,
 			expectedBody:   nil,
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
-				todoID := int32(1)
-				repo.EXPECT().DeleteTodo(ctx, todoID).Return(database.Todo{}, nil).Times(1)
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteTodo(gomock.Any(), database.DeleteTodoParams{ID: 1, OwnerID: 1}).Return([]database.Todo{{ID: 1}}, nil).Times(1)
+			},
+		},
+		{
+			name:           "DeleteHandler Deletes Subtasks",
+			input:          nil,
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   nil,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteTodo(gomock.Any(), database.DeleteTodoParams{ID: 1, OwnerID: 1}).Return([]database.Todo{
+					{ID: 1},
+					{ID: 2, ParentID: sql.NullInt32{Int32: 1, Valid: true}},
+				}, nil).Times(1)
+				for _, todoID := range []int32{1, 2} {
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, "todo.deleted", arg.Event)
+						require.Equal(t, todoID, arg.TodoID)
+						return nil
+					}).Times(1)
+				}
+			},
+		},
+		{
+			name:           "DeleteHandler Weak If-Match",
+			input:          nil,
+			reqHeaders:     map[string]string{"If-Match": `W/"3"`},
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   problem(http.StatusPreconditionFailed, "/tasks/1", delivery.ErrIfMatchFailed),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "DeleteHandler If-Match Todo Not Found",
+			input:          nil,
+			reqHeaders:     map[string]string{"If-Match": `"3"`},
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/tasks/11",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteTodo(gomock.Any(), database.DeleteTodoParams{ID: 11, Versions: []int32{3}, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 11, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
 			},
 		},
 		{
@@ -438,12 +1359,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodDelete,
 			reqTarget:      "/tasks/a",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/a", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name:           "DeleteHandler Invalid ID 2",
@@ -451,12 +1368,8 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodDelete,
 			reqTarget:      "/tasks/-1",
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrInvalidTodoID,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {},
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/-1", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
 		},
 		{
 			name:           "DeleteHandler Todo Not Found",
@@ -464,14 +1377,9 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodDelete,
 			reqTarget:      "/tasks/11",
 			expectedStatus: http.StatusNotFound,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrTodoNotFound,
-			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
-				todoID := int32(11)
-				repo.EXPECT().DeleteTodo(ctx, todoID).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteTodo(gomock.Any(), database.DeleteTodoParams{ID: 11, OwnerID: 1}).Return(nil, nil).Times(1)
 			},
 		},
 		{
@@ -480,16 +1388,248 @@ func TestCreateTodoHandler(t *testing.T) {
 			reqMethod:      http.MethodDelete,
 			reqTarget:      "/tasks/1",
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: struct {
-				Error string `json:"error"`
-			}{
-				Error: delivery.ErrDeletingTodo,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/1", delivery.ErrDeletingTodo),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteTodo(gomock.Any(), database.DeleteTodoParams{ID: 1, OwnerID: 1}).Return(nil, errors.New("some db error")).Times(1)
 			},
-			mockBehavior: func(ctx context.Context, repo *mock_repo.MockRepository) {
-				todoID := int32(1)
-				repo.EXPECT().DeleteTodo(ctx, todoID).Return(database.Todo{}, errors.New("some db error")).Times(1)
+		},
+		// CompleteHandler
+		{
+			name:           "CompleteHandler Success",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Status:      "done",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CompletedAt: stringPtr("2024-09-05T12:30:16+07:00"),
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:30:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				completedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:30:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					Status:      database.TodoStatusOpen,
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+				}
+				completedTodo := todo
+				completedTodo.Status = database.TodoStatusDone
+				completedTodo.CompletedAt = sql.NullTime{Time: completedAt, Valid: true}
+				completedTodo.UpdatedAt = completedAt
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					OwnerID:       1,
+				}).Return(completedTodo, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
 			},
 		},
+		{
+			name:           "CompleteHandler Already Done",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/complete", service.ErrInvalidStatusTransition.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusDone}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Concurrent Status Change",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/complete", service.ErrInvalidStatusTransition.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusOpen}, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					OwnerID:       1,
+				}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:            "CompleteHandler If-Match Success",
+			reqHeaders:      map[string]string{"If-Match": `"3"`, "X-Timezone": "UTC"},
+			reqMethod:       http.MethodPost,
+			reqTarget:       "/tasks/1/complete",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"4@UTC"`, "Vary": "Authorization, X-Timezone"},
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T05:40:16Z",
+				Status:      "done",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CompletedAt: stringPtr("2024-09-05T05:30:16Z"),
+				CreatedAt:   "2024-09-05T05:24:16Z",
+				UpdatedAt:   "2024-09-05T05:30:16Z",
+				Version:     4,
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				completedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:30:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					Status:      database.TodoStatusOpen,
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+					Version:     3,
+				}
+				completedTodo := todo
+				completedTodo.Status = database.TodoStatusDone
+				completedTodo.CompletedAt = sql.NullTime{Time: completedAt, Valid: true}
+				completedTodo.UpdatedAt = completedAt
+				completedTodo.Version = 4
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					Versions:      []int32{3},
+					OwnerID:       1,
+				}).Return(completedTodo, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Precondition Failed",
+			reqHeaders:     map[string]string{"If-Match": `"2"`},
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   problem(http.StatusPreconditionFailed, "/tasks/1/complete", service.ErrTodoModified.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusOpen, Version: 3}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Precondition Failed Concurrently",
+			reqHeaders:     map[string]string{"If-Match": `"3"`},
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody:   problem(http.StatusPreconditionFailed, "/tasks/1/complete", service.ErrTodoModified.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusOpen, Version: 3}, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusDone,
+					CurrentStatus: database.TodoStatusOpen,
+					Versions:      []int32{3},
+					OwnerID:       1,
+				}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusOpen, Version: 4}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Todo Not Found",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/11/complete",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/tasks/11/complete", service.ErrTodoNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 11, OwnerID: 1}).Return(database.Todo{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Repo Error",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problem(http.StatusInternalServerError, "/tasks/1/complete", delivery.ErrChangingTodoStatus),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{}, errors.New("some db error")).Times(1)
+			},
+		},
+
+		// ReopenHandler
+		{
+			name:           "ReopenHandler Success",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reopen",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16+07:00",
+				Status:      "open",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CreatedAt:   "2024-09-05T12:24:16+07:00",
+				UpdatedAt:   "2024-09-05T12:24:16+07:00",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				dueDate, _ := time.Parse(time.RFC3339, "2024-09-05T12:40:16+07:00")
+				createdUpdatedAt, _ := time.Parse(time.RFC3339, "2024-09-05T12:24:16+07:00")
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     dueDate,
+					Status:      database.TodoStatusOpen,
+					CreatedAt:   createdUpdatedAt,
+					UpdatedAt:   createdUpdatedAt,
+				}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusCancelled}, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), database.UpdateTodoStatusParams{
+					ID:            1,
+					Status:        database.TodoStatusOpen,
+					CurrentStatus: database.TodoStatusCancelled,
+					OwnerID:       1,
+				}).Return(todo, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "ReopenHandler Already Open",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/reopen",
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problem(http.StatusConflict, "/tasks/1/reopen", service.ErrInvalidStatusTransition.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1, Status: database.TodoStatusOpen}, nil).Times(1)
+			},
+		},
+		{
+			name:           "ReopenHandler Invalid ID",
+			input:          nil,
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/a/reopen",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/tasks/a/reopen", delivery.ErrInvalidTodoID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
 	}
 
 	for _, tt := range tests {
@@ -498,9 +1638,11 @@ func TestCreateTodoHandler(t *testing.T) {
 			defer ctl.Finish()
 
 			repo := mock_repo.NewMockRepository(ctl)
-			tt.mockBehavior(context.Background(), repo)
+			tt.mockBehavior(repo)
+			expectTodoDefaults(repo)
+			expectTransactions(repo)
 
-			s := service.NewService(repo)
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
 			v, _ := validator.InitValidator()
 			h := NewHandler(s, v)
 			r := chi.NewRouter()
@@ -509,6 +1651,333 @@ func TestCreateTodoHandler(t *testing.T) {
 			rec := httptest.NewRecorder()
 			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
 			req.Header.Set("Content-Type", "Application/Json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			for key, value := range tt.reqHeaders {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+			jsonExpected, _ := json.Marshal(tt.expectedBody)
+
+			if tt.expectedStatus == http.StatusNotModified {
+				require.Empty(t, data)
+			} else {
+				require.Equal(t, jsonExpected, data)
+			}
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			for key, value := range tt.expectedHeaders {
+				require.Equal(t, value, res.Header.Get(key))
+			}
+		})
+	}
+}
+
+func TestTodoRecurrence(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	// 2024-09-05 is a Thursday.
+	dueDate := time.Date(2024, 9, 5, 9, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	todo := func(status database.TodoStatus, recurrence string) database.Todo {
+		return database.Todo{
+			ID:          1,
+			Title:       "standup",
+			Description: "daily standup",
+			DueDate:     dueDate,
+			Status:      status,
+			Recurrence:  sql.NullString{String: recurrence, Valid: recurrence != ""},
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			Version:     1,
+		}
+	}
+	todoDto := func(status database.TodoStatus, recurrence string, seriesID *int32) dto.TodoResponseDto {
+		todoDto := dto.TodoResponseDto{
+			ID:          1,
+			Title:       "standup",
+			Description: "daily standup",
+			DueDate:     "2024-09-05T09:00:00Z",
+			Status:      string(status),
+			Tags:        []string{},
+			BlockedBy:   []int32{},
+			SeriesID:    seriesID,
+			CreatedAt:   "2024-09-05T05:24:16Z",
+			UpdatedAt:   "2024-09-05T05:24:16Z",
+			Version:     1,
+		}
+		if recurrence != "" {
+			todoDto.Recurrence = &recurrence
+		}
+
+		return todoDto
+	}
+	seriesID := int32(1)
+
+	tests := []struct {
+		name           string
+		input          io.Reader
+		reqMethod      string
+		reqTarget      string
+		expectedStatus int
+		expectedBody   interface{}
+		mockBehavior   mockBehavior
+	}{
+		// CreateHandler
+		{
+			name: "CreateHandler With Recurrence",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z",
+				"recurrence": "RRULE:freq=weekly;byday=mo,we,fr"
+			}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   todoDto(database.TodoStatusOpen, "FREQ=WEEKLY;BYDAY=MO,WE,FR", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
+					Title:       "standup",
+					Description: "daily standup",
+					DueDate:     dueDate,
+					OwnerID:     1,
+					Recurrence:  sql.NullString{String: "FREQ=WEEKLY;BYDAY=MO,WE,FR", Valid: true},
+				}).Return(todo(database.TodoStatusOpen, "FREQ=WEEKLY;BYDAY=MO,WE,FR"), nil).Times(1)
+			},
+		},
+		{
+			name: "CreateHandler Stores Recurrence Timezone",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z",
+				"recurrence": "FREQ=DAILY"
+			}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks?tz=UTC",
+			expectedStatus: http.StatusCreated,
+			expectedBody:   todoDto(database.TodoStatusOpen, "FREQ=DAILY", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateTodo(gomock.Any(), database.CreateTodoParams{
+					Title:              "standup",
+					Description:        "daily standup",
+					DueDate:            dueDate,
+					OwnerID:            1,
+					Recurrence:         sql.NullString{String: "FREQ=DAILY", Valid: true},
+					RecurrenceTimezone: sql.NullString{String: "UTC", Valid: true},
+				}).Return(todo(database.TodoStatusOpen, "FREQ=DAILY"), nil).Times(1)
+			},
+		},
+		{
+			name: "CreateHandler Invalid Recurrence",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z",
+				"recurrence": "FREQ=FORTNIGHTLY"
+			}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "recurrence", Rule: "rrule", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name: "CreateHandler Recurrence With DTSTART",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z",
+				"recurrence": "DTSTART:20240905T090000Z\nRRULE:FREQ=DAILY"
+			}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "recurrence", Rule: "rrule", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name: "CreateHandler Recurrence Too Frequent",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z",
+				"recurrence": "FREQ=MINUTELY;INTERVAL=5"
+			}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/tasks", delivery.ErrInvalidInput,
+				dto.FieldErrorDto{Field: "recurrence", Rule: "rrule", Code: "invalid_format"}),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		// UpdateHandler
+		{
+			name: "UpdateHandler Clears Recurrence",
+			input: bytes.NewBufferString(`{
+				"title": "standup",
+				"description": "daily standup",
+				"due_date": "2024-09-05T09:00:00Z"
+			}`),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/tasks/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusOpen, "", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UpdateTodo(gomock.Any(), database.UpdateTodoParams{
+					ID:          1,
+					Title:       "standup",
+					Description: "daily standup",
+					DueDate:     dueDate,
+					OwnerID:     1,
+				}).Return(todo(database.TodoStatusOpen, ""), nil).Times(1)
+				repo.EXPECT().SetTodoTags(gomock.Any(), database.SetTodoTagsParams{TodoID: 1, OwnerID: 1}).Return(nil).Times(1)
+			},
+		},
+		// CompleteHandler
+		{
+			name:           "CompleteHandler Creates Next Occurrence",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=WEEKLY;BYDAY=MO,WE,FR", &seriesID),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=WEEKLY;BYDAY=MO,WE,FR"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusDone, "FREQ=WEEKLY;BYDAY=MO,WE,FR"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), database.CreateNextOccurrenceParams{
+					ID:         1,
+					DueDate:    time.Date(2024, 9, 6, 9, 0, 0, 0, time.UTC),
+					Recurrence: "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+				}).Return(database.CreateNextOccurrenceRow{ID: 2, SeriesID: sql.NullInt32{Int32: 1, Valid: true}}, nil).Times(1)
+				events := []database.InsertOutboxEventParams{
+					{Event: "todo.created", TodoID: 2},
+					{Event: "todo.completed", TodoID: 1},
+				}
+				for _, event := range events {
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, event.Event, arg.Event)
+						require.Equal(t, event.TodoID, arg.TodoID)
+						return nil
+					}).Times(1)
+				}
+			},
+		},
+		{
+			name:           "CompleteHandler Counts Down Occurrences",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=MONTHLY;COUNT=3", &seriesID),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=MONTHLY;COUNT=3"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusDone, "FREQ=MONTHLY;COUNT=3"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), database.CreateNextOccurrenceParams{
+					ID:         1,
+					DueDate:    time.Date(2024, 10, 5, 9, 0, 0, 0, time.UTC),
+					Recurrence: "FREQ=MONTHLY;COUNT=2",
+				}).Return(database.CreateNextOccurrenceRow{ID: 2, SeriesID: sql.NullInt32{Int32: 1, Valid: true}}, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Last Occurrence",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=DAILY;COUNT=1", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=DAILY;COUNT=1"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusDone, "FREQ=DAILY;COUNT=1"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().EndTodoRecurrence(gomock.Any(), int32(1)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Past Until",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=DAILY;UNTIL=20240905T235959Z", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=DAILY;UNTIL=20240905T235959Z"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusDone, "FREQ=DAILY;UNTIL=20240905T235959Z"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().EndTodoRecurrence(gomock.Any(), int32(1)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Next Occurrence Already Generated",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=DAILY", &seriesID),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				recurred := todo(database.TodoStatusOpen, "FREQ=DAILY")
+				recurred.Recurred = true
+				recurred.SeriesID = sql.NullInt32{Int32: 1, Valid: true}
+				completed := recurred
+				completed.Status = database.TodoStatusDone
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(recurred, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(completed, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+		{
+			name:           "CompleteHandler Next Occurrence Generated Concurrently",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusDone, "FREQ=DAILY", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=DAILY"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusDone, "FREQ=DAILY"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), gomock.Any()).Return(database.CreateNextOccurrenceRow{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "CancelHandler Doesn't Create Next Occurrence",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/cancel",
+			expectedStatus: http.StatusOK,
+			expectedBody:   todoDto(database.TodoStatusCancelled, "FREQ=DAILY", nil),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo(database.TodoStatusOpen, "FREQ=DAILY"), nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(todo(database.TodoStatusCancelled, "FREQ=DAILY"), nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTodoDefaults(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
 
 			r.ServeHTTP(rec, req)
 			res := rec.Result()
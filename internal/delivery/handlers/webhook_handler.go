@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// WebhookHandler manages webhooks of the authenticated user and the log of their deliveries.
+type WebhookHandler struct {
+	webhookService service.Webhooks
+	validator      *validator.Validate
+}
+
+func newWebhookHandler(webhookService service.Webhooks, validator *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validator:      validator,
+	}
+}
+
+func (h WebhookHandler) createWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	webhookInput := r.Context().Value(delivery.WebhookInputKey).(dto.WebhookInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), userID, webhookInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrCreatingWebhook)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.RespondWithJSON(w, http.StatusCreated, webhook)
+}
+
+func (h WebhookHandler) getWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	webhooks, err := h.webhookService.ListWebhooks(r.Context(), userID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingWebhooks)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, webhooks)
+}
+
+func (h WebhookHandler) getWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	webhookID := r.Context().Value(delivery.WebhookIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	webhook, err := h.webhookService.GetWebhook(r.Context(), userID, webhookID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingWebhook)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, webhook)
+}
+
+func (h WebhookHandler) updateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	webhookID := r.Context().Value(delivery.WebhookIDKey).(int)
+	webhookInput := r.Context().Value(delivery.WebhookInputKey).(dto.WebhookInputDto)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	webhook, err := h.webhookService.UpdateWebhook(r.Context(), userID, webhookID, webhookInput, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrUpdatingWebhook)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, webhook)
+}
+
+func (h WebhookHandler) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	webhookID := r.Context().Value(delivery.WebhookIDKey).(int)
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), userID, webhookID); err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrDeletingWebhook)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusNoContent, nil)
+}
+
+func (h WebhookHandler) getWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(delivery.UserIDKey).(int32)
+	webhookID := r.Context().Value(delivery.WebhookIDKey).(int)
+	loc := r.Context().Value(delivery.TimezoneKey).(*time.Location)
+
+	deliveries, err := h.webhookService.ListWebhookDeliveries(r.Context(), userID, webhookID, loc)
+	if err != nil {
+		delivery.RespondWithServiceError(w, r, err, delivery.ErrGettingWebhookDeliveries)
+		return
+	}
+
+	delivery.RespondWithJSON(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/validator"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	createdAt := time.Date(2024, 9, 5, 5, 24, 16, 0, time.UTC)
+	disabledAt := time.Date(2024, 9, 6, 8, 0, 0, 0, time.UTC)
+	webhook := database.Webhook{
+		ID:        3,
+		UserID:    1,
+		URL:       "https://chat.example.com/hooks/todos",
+		Secret:    "whsec_secret",
+		Events:    []string{"todo.completed", "todo.created"},
+		Active:    true,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	webhookDto := dto.WebhookResponseDto{
+		ID:        3,
+		URL:       "https://chat.example.com/hooks/todos",
+		Events:    []string{"todo.completed", "todo.created"},
+		Active:    true,
+		CreatedAt: "2024-09-05T05:24:16Z",
+		UpdatedAt: "2024-09-05T05:24:16Z",
+	}
+
+	tests := []struct {
+		name           string
+		input          io.Reader
+		reqMethod      string
+		reqTarget      string
+		expectedStatus int
+		expectedBody   interface{}
+		expectedSecret bool
+		mockBehavior   mockBehavior
+	}{
+		// CreateWebhookHandler
+		{
+			name:           "CreateWebhookHandler Success",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks/todos", "events": ["todo.created", "todo.completed", "todo.created"]}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusCreated,
+			expectedSecret: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.CreateWebhookParams) (database.Webhook, error) {
+					require.Equal(t, int32(1), arg.UserID)
+					require.Equal(t, []string{"todo.completed", "todo.created"}, arg.Events)
+					require.True(t, arg.Active)
+
+					created := webhook
+					created.Secret = arg.Secret
+					return created, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:           "CreateWebhookHandler Invalid URL",
+			input:          bytes.NewBufferString(`{"url": "ftp://chat.example.com/hooks", "events": ["todo.created"]}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/webhooks", delivery.ErrInvalidWebhookInput,
+				dto.FieldErrorDto{Field: "url", Rule: "http_url", Code: "invalid_format"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateWebhookHandler Private URL",
+			input:          bytes.NewBufferString(`{"url": "http://169.254.169.254/latest/meta-data", "events": ["todo.created"]}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/webhooks", service.ErrInvalidWebhookURL.Error()),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateWebhookHandler Invalid Event",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks", "events": ["todo.archived"]}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/webhooks", delivery.ErrInvalidWebhookInput,
+				dto.FieldErrorDto{Field: "events[0]", Rule: "oneof", Code: "not_allowed"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		{
+			name:           "CreateWebhookHandler Missing Events",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks"}`),
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: problem(http.StatusBadRequest, "/webhooks", delivery.ErrInvalidWebhookInput,
+				dto.FieldErrorDto{Field: "events", Rule: "required", Code: "required"},
+			),
+			mockBehavior: func(repo *mock_repo.MockRepository) {},
+		},
+		// GetWebhooksHandler
+		{
+			name:           "GetWebhooksHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks",
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.WebhooksDto{Items: []dto.WebhookResponseDto{webhookDto}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListWebhooks(gomock.Any(), int32(1)).Return([]database.Webhook{webhook}, nil).Times(1)
+			},
+		},
+		// GetWebhookHandler
+		{
+			name:           "GetWebhookHandler Disabled",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.WebhookResponseDto{
+				ID:                  3,
+				URL:                 "https://chat.example.com/hooks/todos",
+				Events:              []string{"todo.completed", "todo.created"},
+				ConsecutiveFailures: 20,
+				DisabledAt:          stringPtr("2024-09-06T08:00:00Z"),
+				CreatedAt:           "2024-09-05T05:24:16Z",
+				UpdatedAt:           "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				disabled := webhook
+				disabled.Active = false
+				disabled.ConsecutiveFailures = 20
+				disabled.DisabledAt = sql.NullTime{Time: disabledAt, Valid: true}
+				repo.EXPECT().GetWebhook(gomock.Any(), database.GetWebhookParams{ID: 3, UserID: 1}).Return(disabled, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetWebhookHandler Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/webhooks/3", service.ErrWebhookNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetWebhook(gomock.Any(), database.GetWebhookParams{ID: 3, UserID: 1}).Return(database.Webhook{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		{
+			name:           "GetWebhookHandler Invalid ID",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problem(http.StatusBadRequest, "/webhooks/abc", delivery.ErrInvalidWebhookID),
+			mockBehavior:   func(repo *mock_repo.MockRepository) {},
+		},
+		// UpdateWebhookHandler
+		{
+			name:           "UpdateWebhookHandler Re-enables",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks/todos", "events": ["todo.created", "todo.completed"]}`),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusOK,
+			expectedBody:   webhookDto,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UpdateWebhook(gomock.Any(), database.UpdateWebhookParams{
+					URL:    "https://chat.example.com/hooks/todos",
+					Events: []string{"todo.completed", "todo.created"},
+					Active: true,
+					ID:     3,
+					UserID: 1,
+				}).Return(webhook, nil).Times(1)
+			},
+		},
+		{
+			name:           "UpdateWebhookHandler Pauses",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks/todos", "events": ["todo.deleted"], "active": false}`),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.WebhookResponseDto{
+				ID:        3,
+				URL:       "https://chat.example.com/hooks/todos",
+				Events:    []string{"todo.deleted"},
+				CreatedAt: "2024-09-05T05:24:16Z",
+				UpdatedAt: "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				paused := webhook
+				paused.Events = []string{"todo.deleted"}
+				paused.Active = false
+				repo.EXPECT().UpdateWebhook(gomock.Any(), database.UpdateWebhookParams{
+					URL:    "https://chat.example.com/hooks/todos",
+					Events: []string{"todo.deleted"},
+					ID:     3,
+					UserID: 1,
+				}).Return(paused, nil).Times(1)
+			},
+		},
+		{
+			name:           "UpdateWebhookHandler Not Found",
+			input:          bytes.NewBufferString(`{"url": "https://chat.example.com/hooks/todos", "events": ["todo.deleted"]}`),
+			reqMethod:      http.MethodPut,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/webhooks/3", service.ErrWebhookNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().UpdateWebhook(gomock.Any(), gomock.Any()).Return(database.Webhook{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// DeleteWebhookHandler
+		{
+			name:           "DeleteWebhookHandler Success",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   nil,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteWebhook(gomock.Any(), database.DeleteWebhookParams{ID: 3, UserID: 1}).Return(webhook, nil).Times(1)
+			},
+		},
+		{
+			name:           "DeleteWebhookHandler Not Found",
+			reqMethod:      http.MethodDelete,
+			reqTarget:      "/webhooks/3",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/webhooks/3", service.ErrWebhookNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().DeleteWebhook(gomock.Any(), database.DeleteWebhookParams{ID: 3, UserID: 1}).Return(database.Webhook{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// GetWebhookDeliveriesHandler
+		{
+			name:           "GetWebhookDeliveriesHandler Success",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks/3/deliveries",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.WebhookDeliveriesDto{Items: []dto.WebhookDeliveryDto{
+				{
+					ID:            12,
+					Event:         "todo.created",
+					Status:        "pending",
+					Attempts:      2,
+					LastError:     stringPtr("webhook responded with status 502"),
+					NextAttemptAt: stringPtr("2024-09-05T05:25:16Z"),
+					CreatedAt:     "2024-09-05T05:24:16Z",
+				},
+				{
+					ID:          11,
+					Event:       "todo.completed",
+					Status:      "delivered",
+					Attempts:    1,
+					DeliveredAt: stringPtr("2024-09-05T05:24:16Z"),
+					CreatedAt:   "2024-09-05T05:24:16Z",
+				},
+				{
+					ID:        10,
+					Event:     "todo.completed",
+					Status:    "failed",
+					Attempts:  8,
+					LastError: stringPtr("connection refused"),
+					CreatedAt: "2024-09-05T05:24:16Z",
+				},
+			}},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetWebhook(gomock.Any(), database.GetWebhookParams{ID: 3, UserID: 1}).Return(webhook, nil).Times(1)
+				repo.EXPECT().ListWebhookDeliveries(gomock.Any(), database.ListWebhookDeliveriesParams{WebhookID: 3, UserID: 1, RowLimit: 100}).Return([]database.WebhookDelivery{
+					{
+						ID:            12,
+						WebhookID:     3,
+						Event:         "todo.created",
+						Attempts:      2,
+						NextAttemptAt: createdAt.Add(time.Minute),
+						LastError:     sql.NullString{String: "webhook responded with status 502", Valid: true},
+						CreatedAt:     createdAt,
+					},
+					{
+						ID:          11,
+						WebhookID:   3,
+						Event:       "todo.completed",
+						Attempts:    1,
+						DeliveredAt: sql.NullTime{Time: createdAt, Valid: true},
+						CreatedAt:   createdAt,
+					},
+					{
+						ID:        10,
+						WebhookID: 3,
+						Event:     "todo.completed",
+						Attempts:  8,
+						LastError: sql.NullString{String: "connection refused", Valid: true},
+						FailedAt:  sql.NullTime{Time: createdAt, Valid: true},
+						CreatedAt: createdAt,
+					},
+				}, nil).Times(1)
+			},
+		},
+		{
+			name:           "GetWebhookDeliveriesHandler Webhook Not Found",
+			reqMethod:      http.MethodGet,
+			reqTarget:      "/webhooks/3/deliveries",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problem(http.StatusNotFound, "/webhooks/3/deliveries", service.ErrWebhookNotFound.Error()),
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().GetWebhook(gomock.Any(), database.GetWebhookParams{ID: 3, UserID: 1}).Return(database.Webhook{}, sql.ErrNoRows).Times(1)
+			},
+		},
+		// Todo events
+		{
+			name:           "CompleteHandler Publishes Event",
+			reqMethod:      http.MethodPost,
+			reqTarget:      "/tasks/1/complete",
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.TodoResponseDto{
+				ID:          1,
+				Title:       "test",
+				Description: "test",
+				DueDate:     "2024-09-05T12:40:16Z",
+				Status:      "done",
+				Tags:        []string{},
+				BlockedBy:   []int32{},
+				CompletedAt: stringPtr("2024-09-05T05:24:16Z"),
+				CreatedAt:   "2024-09-05T05:24:16Z",
+				UpdatedAt:   "2024-09-05T05:24:16Z",
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				todo := database.Todo{
+					ID:          1,
+					Title:       "test",
+					Description: "test",
+					DueDate:     time.Date(2024, 9, 5, 12, 40, 16, 0, time.UTC),
+					Status:      database.TodoStatusOpen,
+					CreatedAt:   createdAt,
+					UpdatedAt:   createdAt,
+				}
+				completedTodo := todo
+				completedTodo.Status = database.TodoStatusDone
+				completedTodo.CompletedAt = sql.NullTime{Time: createdAt, Valid: true}
+				repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(todo, nil).Times(1)
+				repo.EXPECT().UpdateTodoStatus(gomock.Any(), gomock.Any()).Return(completedTodo, nil).Times(1)
+				repo.EXPECT().TouchBlockedTodos(gomock.Any(), database.TouchBlockedTodosParams{BlockerID: 1, OwnerID: 1}).Return(nil, nil).Times(1)
+				repo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.EnqueueWebhookDeliveriesParams) error {
+					require.Equal(t, "todo.completed", arg.Event)
+					require.Equal(t, int32(1), arg.UserID)
+
+					var event dto.WebhookEventDto
+					require.NoError(t, json.Unmarshal(arg.Payload, &event))
+					require.Equal(t, "todo.completed", event.Event)
+					require.Equal(t, int32(1), event.Data.ID)
+					require.Equal(t, "done", event.Data.Status)
+					return nil
+				}).Times(1)
+				expectTodoDefaults(repo)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			expectTransactions(repo)
+
+			s := service.NewService(repo, mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte(testSecret), AccessTokenTTL: time.Minute}, service.TodoConfig{})
+			v, _ := validator.InitValidator()
+			h := NewHandler(s, v)
+			r := chi.NewRouter()
+			h.RegisterRoutes(r)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.reqMethod, tt.reqTarget, tt.input)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+accessToken(t, testSecret, 1))
+
+			r.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+			data, _ := io.ReadAll(res.Body)
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedSecret {
+				var created dto.WebhookCreatedDto
+				require.NoError(t, json.Unmarshal(data, &created))
+				require.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+				require.Equal(t, webhookDto, created.WebhookResponseDto)
+				require.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			} else {
+				jsonExpected, _ := json.Marshal(tt.expectedBody)
+				require.Equal(t, jsonExpected, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/service"
+)
+
+const bearerPrefix = "Bearer "
+
+// CheckCredentials validates the request body against the CredentialsDto schema and adds it to the request context.
+func CheckCredentials(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			credentials := dto.CredentialsDto{}
+			if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidCredentialsInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidCredentialsInput, err)
+				return
+			}
+
+			if err := validate.Struct(&credentials); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidCredentialsInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidCredentialsInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.CredentialsKey, credentials)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CheckRefreshToken validates the request body against the RefreshTokenDto schema and adds the token to the request context.
+func CheckRefreshToken(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			refresh := dto.RefreshTokenDto{}
+			if err := json.NewDecoder(r.Body).Decode(&refresh); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidRefreshInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidRefreshInput, err)
+				return
+			}
+
+			if err := validate.Struct(&refresh); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidRefreshInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidRefreshInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.RefreshTokenKey, refresh.RefreshToken)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authenticate verifies the bearer access token or API key of the request and adds the id of its user and the granted scopes
+// to the request context, so the following handlers only see the todos of that user. Access tokens grant all scopes.
+// Requests without a valid token are rejected with 401.
+func Authenticate(auth service.Auth, apiKeys service.APIKeys) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+				logger.FromContext(r.Context()).Info(delivery.ErrMissingAccessToken)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				delivery.RespondWithError(w, r, http.StatusUnauthorized, delivery.ErrMissingAccessToken)
+				return
+			}
+
+			token := header[len(bearerPrefix):]
+			var userID int32
+			var err error
+			scopes := []string{service.ScopeRead, service.ScopeWrite, service.ScopeAdmin}
+			if service.IsAPIKey(token) {
+				userID, scopes, err = apiKeys.AuthenticateAPIKey(r.Context(), token)
+			} else {
+				userID, err = auth.ParseAccessToken(token)
+			}
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				delivery.RespondWithServiceError(w, r, err, delivery.ErrAuthenticating)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.UserIDKey, userID)
+			ctx = context.WithValue(ctx, delivery.ScopesKey, scopes)
+			ctx = logger.WithContext(ctx, logger.FromContext(ctx).With("user_id", userID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rejects requests whose credentials weren't granted scope with 403. The admin scope grants every scope.
+func RequireScope(scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes := r.Context().Value(delivery.ScopesKey).([]string)
+			if !slices.Contains(scopes, scope) && !slices.Contains(scopes, service.ScopeAdmin) {
+				logger.FromContext(r.Context()).Info(delivery.ErrInsufficientScope, "scope", scope, "scopes", scopes)
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				delivery.RespondWithError(w, r, http.StatusForbidden, delivery.ErrInsufficientScope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CheckAPIKeyInput validates the request body against the APIKeyInputDto schema and adds it to the request context.
+func CheckAPIKeyInput(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKeyInput := dto.APIKeyInputDto{}
+			if err := json.NewDecoder(r.Body).Decode(&apiKeyInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidAPIKeyInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidAPIKeyInput, err)
+				return
+			}
+
+			if err := validate.Struct(&apiKeyInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidAPIKeyInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidAPIKeyInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.APIKeyInputKey, apiKeyInput)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetAPIKeyID extracts the API key ID from the request URL and adds it to the request context.
+func GetAPIKeyID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKeyID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || apiKeyID <= 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidAPIKeyID, "api_key_id", chi.URLParam(r, "id"))
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidAPIKeyID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.APIKeyIDKey, apiKeyID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+// CheckTodoBlocker validates the request body against the TodoBlockerDto schema and adds it to the request context.
+func CheckTodoBlocker(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			todoBlocker := dto.TodoBlockerDto{}
+			if err := json.NewDecoder(r.Body).Decode(&todoBlocker); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoBlocker, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoBlocker, err)
+				return
+			}
+
+			if err := validate.Struct(&todoBlocker); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoBlocker, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoBlocker, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodoBlockerKey, todoBlocker)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetBlockerID extracts the blocker todo ID from the request URL and adds it to the request context.
+func GetBlockerID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blockerID, err := strconv.Atoi(chi.URLParam(r, "blockerID"))
+		if err != nil || blockerID <= 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidBlockerID, "blocker_id", chi.URLParam(r, "blockerID"))
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidBlockerID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.BlockerIDKey, blockerID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
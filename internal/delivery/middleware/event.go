@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/logger"
+)
+
+// GetLastEventID parses the Last-Event-ID header a client resumes an event stream with and adds it to the request context.
+// Nothing is added when the header is missing, so the stream starts with new events.
+func GetLastEventID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Last-Event-ID")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lastEventID, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || lastEventID < 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidLastEventID, "last_event_id", header)
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidLastEventID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.LastEventIDKey, lastEventID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
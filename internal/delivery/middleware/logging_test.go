@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"to-do-list-go/internal/logger"
+)
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestID         string
+		expectedRequestID string
+	}{
+		{
+			name:              "Request ID Accepted",
+			requestID:         "abc-123",
+			expectedRequestID: "abc-123",
+		},
+		{
+			name:      "Request ID Generated",
+			requestID: "",
+		},
+		{
+			name:      "Request ID Malformed",
+			requestID: "bad id",
+		},
+		{
+			name:      "Request ID Too Long",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			base := logger.New(&buf, slog.LevelInfo)
+
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					next.ServeHTTP(w, r.WithContext(logger.WithContext(r.Context(), base)))
+				})
+			})
+			r.Use(RequestID)
+			r.Use(AccessLog)
+			r.Get("/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+				logger.FromContext(r.Context()).Info("handler")
+				w.WriteHeader(http.StatusTeapot)
+				w.Write([]byte("body"))
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+			if tt.requestID != "" {
+				req.Header.Set(requestIDHeader, tt.requestID)
+			}
+			r.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(requestIDHeader)
+			if tt.expectedRequestID != "" {
+				require.Equal(t, tt.expectedRequestID, requestID)
+			} else {
+				require.Len(t, requestID, 32)
+			}
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			require.Len(t, lines, 2)
+
+			var handlerRecord, accessRecord map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &handlerRecord))
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &accessRecord))
+
+			require.Equal(t, requestID, handlerRecord["request_id"])
+			require.Equal(t, requestID, accessRecord["request_id"])
+			require.Equal(t, "GET", accessRecord["method"])
+			require.Equal(t, "/tasks/{id}", accessRecord["route"])
+			require.Equal(t, float64(http.StatusTeapot), accessRecord["status"])
+			require.Equal(t, float64(4), accessRecord["bytes"])
+			require.Contains(t, accessRecord, "latency_ms")
+		})
+	}
+}
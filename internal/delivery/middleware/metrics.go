@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi"
+	chimiddleware "github.com/go-chi/chi/middleware"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/metrics"
+)
+
+// unmatchedRoute labels requests that didn't match any route, so unknown paths don't grow the label set.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the status and latency of each request under its chi route pattern.
+func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			route := routePattern(r)
+			status := responseStatus(ww)
+
+			m.ObserveHTTPRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
+
+// routePattern returns the chi route pattern the request has been routed to.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+
+	return unmatchedRoute
+}
+
+// responseStatus returns the status written to ww, which is 200 when the handler wrote only a body or nothing.
+func responseStatus(ww chimiddleware.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+
+	return http.StatusOK
+}
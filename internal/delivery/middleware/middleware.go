@@ -3,13 +3,16 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
-	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"to-do-list-go/internal/delivery"
 	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
 )
 
 // CheckTodoInput validates the request body against the TodoInputDto schema and adds it to the request context.
@@ -18,14 +21,14 @@ func CheckTodoInput(validate *validator.Validate) func(next http.Handler) http.H
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			todoInput := dto.TodoInputDto{}
 			if err := json.NewDecoder(r.Body).Decode(&todoInput); err != nil {
-				log.Printf(delivery.ErrInvalidInput+": %s\n", err)
-				delivery.RespondWithError(w, http.StatusBadRequest, delivery.ErrInvalidInput)
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidInput, err)
 				return
 			}
 
 			if err := validate.Struct(&todoInput); err != nil {
-				log.Printf(delivery.ErrInvalidInput+": %s\n", err)
-				delivery.RespondWithError(w, http.StatusBadRequest, delivery.ErrInvalidInput)
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidInput, err)
 				return
 			}
 
@@ -42,12 +45,12 @@ func GetTodoID(next http.Handler) http.Handler {
 		todoID, err := strconv.Atoi(todoIDStr)
 		if err != nil || todoID <= 0 {
 			if err != nil {
-				log.Printf(delivery.ErrInvalidTodoID+": %s\n", err)
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoID, "error", err)
 			} else {
-				log.Printf(delivery.ErrInvalidTodoID+": %d\n", todoID)
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoID, "todo_id", todoID)
 			}
 
-			delivery.RespondWithError(w, http.StatusBadRequest, delivery.ErrInvalidTodoID)
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidTodoID)
 			return
 		}
 
@@ -55,3 +58,177 @@ func GetTodoID(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// GetTimezone reads the preferred timezone from the tz query parameter or the X-Timezone header and adds it to the request context.
+// The query parameter takes precedence. When neither is set, a nil location is stored.
+func GetTimezone(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tz := r.URL.Query().Get("tz")
+		if tz == "" {
+			tz = r.Header.Get("X-Timezone")
+		}
+
+		var loc *time.Location
+		if tz != "" {
+			var err error
+			loc, err = time.LoadLocation(tz)
+			if err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTZ, "error", err)
+				delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidTZ)
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.TimezoneKey, loc)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetTodosQuery parses and validates the pagination, filtering and sorting parameters of a todos list request
+// and adds them to the request context.
+func GetTodosQuery(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			todosQuery, err := parseTodosQuery(r)
+			if err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodosQuery, "error", err)
+				delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidTodosQuery)
+				return
+			}
+
+			if err := validate.Struct(&todosQuery); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodosQuery, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodosQuery, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodosQueryKey, todosQuery)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+const (
+	defaultTodosLimit   = 20
+	defaultTodosSort    = "created_at"
+	defaultTodosTagMode = "any"
+)
+
+func parseTodosQuery(r *http.Request) (dto.TodosQueryDto, error) {
+	query := r.URL.Query()
+	todosQuery := dto.TodosQueryDto{
+		Limit:    defaultTodosLimit,
+		Cursor:   query.Get("cursor"),
+		Sort:     splitQueryList(query["sort"]),
+		Statuses: splitQueryList(query["status"]),
+		Tags:     splitQueryList(query["tag"]),
+		TagMode:  query.Get("tag_mode"),
+	}
+
+	if todosQuery.TagMode == "" {
+		todosQuery.TagMode = defaultTodosTagMode
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if todosQuery.Limit, err = strconv.Atoi(limit); err != nil {
+			return dto.TodosQueryDto{}, err
+		}
+	}
+
+	if len(todosQuery.Sort) == 0 {
+		todosQuery.Sort = []string{defaultTodosSort}
+	}
+
+	seen := make(map[string]bool, len(todosQuery.Sort))
+	for _, key := range todosQuery.Sort {
+		field := strings.TrimPrefix(key, "-")
+		if seen[field] {
+			return dto.TodosQueryDto{}, fmt.Errorf("duplicate sort field %q", field)
+		}
+		seen[field] = true
+	}
+
+	timeParams := map[string]**time.Time{
+		"due_from":     &todosQuery.DueFrom,
+		"due_to":       &todosQuery.DueTo,
+		"created_from": &todosQuery.CreatedFrom,
+		"created_to":   &todosQuery.CreatedTo,
+		"updated_from": &todosQuery.UpdatedFrom,
+		"updated_to":   &todosQuery.UpdatedTo,
+	}
+	for name, dst := range timeParams {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return dto.TodosQueryDto{}, fmt.Errorf("%s: %w", name, err)
+		}
+		*dst = &t
+	}
+
+	return todosQuery, nil
+}
+
+// splitQueryList flattens repeated and comma-separated query parameter values, skipping empty items.
+func splitQueryList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+// GetTodoSearchQuery parses and validates the parameters of a todos search request and adds them to the request context.
+func GetTodoSearchQuery(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			searchQuery := dto.TodoSearchQueryDto{
+				Query: strings.TrimSpace(r.URL.Query().Get("q")),
+				Limit: defaultTodosLimit,
+			}
+
+			if limit := r.URL.Query().Get("limit"); limit != "" {
+				var err error
+				if searchQuery.Limit, err = strconv.Atoi(limit); err != nil {
+					logger.FromContext(r.Context()).Info(delivery.ErrInvalidSearchQuery, "error", err)
+					delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidSearchQuery)
+					return
+				}
+			}
+
+			if err := validate.Struct(&searchQuery); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidSearchQuery, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidSearchQuery, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodoSearchQueryKey, searchQuery)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetIfMatch parses the If-Match header into the list of acceptable todo versions and adds it to the request context.
+// A header that lists no usable strong entity tag can't match any todo and fails the precondition right away.
+func GetIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions, ok := delivery.ParseIfMatch(r.Header.Get("If-Match"))
+		if !ok {
+			logger.FromContext(r.Context()).Info(delivery.ErrIfMatchFailed, "if_match", r.Header.Get("If-Match"))
+			delivery.RespondWithError(w, r, http.StatusPreconditionFailed, delivery.ErrIfMatchFailed)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.IfMatchKey, versions)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
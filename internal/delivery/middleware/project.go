@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+const defaultProjectDeleteTodos = "move"
+
+// CheckProjectInput validates the request body against the ProjectInputDto schema and adds it to the request context.
+func CheckProjectInput(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			projectInput := dto.ProjectInputDto{}
+			if err := json.NewDecoder(r.Body).Decode(&projectInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidProjectInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidProjectInput, err)
+				return
+			}
+
+			if err := validate.Struct(&projectInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidProjectInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidProjectInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.ProjectInputKey, projectInput)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// CheckTodoMove validates the request body against the TodoMoveDto schema and adds it to the request context.
+func CheckTodoMove(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			todoMove := dto.TodoMoveDto{}
+			if err := json.NewDecoder(r.Body).Decode(&todoMove); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoMove, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoMove, err)
+				return
+			}
+
+			if err := validate.Struct(&todoMove); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoMove, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoMove, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodoMoveKey, todoMove)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetProjectID extracts the project ID from the request URL and adds it to the request context.
+func GetProjectID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || projectID <= 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidProjectID, "project_id", chi.URLParam(r, "id"))
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidProjectID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.ProjectIDKey, projectID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetIncludeArchived reads the archived query parameter, telling whether archived projects are listed, and adds it to the request context.
+func GetIncludeArchived(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		includeArchived := false
+		if archived := r.URL.Query().Get("archived"); archived != "" {
+			var err error
+			if includeArchived, err = strconv.ParseBool(archived); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidArchivedQuery, "error", err)
+				delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidArchivedQuery)
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.IncludeArchivedKey, includeArchived)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetProjectDeleteQuery parses and validates the parameters of a project delete request and adds them to the request context.
+// Todos of the project are moved out of any project unless asked otherwise.
+func GetProjectDeleteQuery(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deleteQuery, err := parseProjectDeleteQuery(r)
+			if err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidProjectDelete, "error", err)
+				delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidProjectDelete)
+				return
+			}
+
+			if err := validate.Struct(&deleteQuery); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidProjectDelete, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidProjectDelete, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.ProjectDeleteKey, deleteQuery)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func parseProjectDeleteQuery(r *http.Request) (dto.ProjectDeleteQueryDto, error) {
+	query := r.URL.Query()
+	deleteQuery := dto.ProjectDeleteQueryDto{
+		Todos: query.Get("todos"),
+	}
+	if deleteQuery.Todos == "" {
+		deleteQuery.Todos = defaultProjectDeleteTodos
+	}
+
+	if target := query.Get("target_project_id"); target != "" {
+		if deleteQuery.Todos != defaultProjectDeleteTodos {
+			return dto.ProjectDeleteQueryDto{}, errors.New("target_project_id is only allowed when todos are moved")
+		}
+
+		targetID, err := strconv.ParseInt(target, 10, 32)
+		if err != nil {
+			return dto.ProjectDeleteQueryDto{}, err
+		}
+		id := int32(targetID)
+		deleteQuery.TargetProjectID = &id
+	}
+
+	return deleteQuery, nil
+}
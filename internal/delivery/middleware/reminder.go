@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+// CheckReminderInput validates the request body against the ReminderInputDto schema and adds it to the request context.
+func CheckReminderInput(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reminderInput := dto.ReminderInputDto{}
+			if err := json.NewDecoder(r.Body).Decode(&reminderInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidReminderInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidReminderInput, err)
+				return
+			}
+
+			if err := validate.Struct(&reminderInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidReminderInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidReminderInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.ReminderInputKey, reminderInput)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetReminderID extracts the reminder ID from the request URL and adds it to the request context.
+func GetReminderID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reminderID, err := strconv.Atoi(chi.URLParam(r, "reminderID"))
+		if err != nil || reminderID <= 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidReminderID, "reminder_id", chi.URLParam(r, "reminderID"))
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidReminderID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.ReminderIDKey, reminderID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
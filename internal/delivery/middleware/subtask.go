@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+// CheckTodoParent validates the request body against the TodoParentDto schema and adds it to the request context.
+func CheckTodoParent(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			todoParent := dto.TodoParentDto{}
+			if err := json.NewDecoder(r.Body).Decode(&todoParent); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoParent, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoParent, err)
+				return
+			}
+
+			if err := validate.Struct(&todoParent); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidTodoParent, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidTodoParent, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodoParentKey, todoParent)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
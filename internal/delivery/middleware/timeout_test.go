@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name             string
+		target           string
+		accept           string
+		expectedDeadline bool
+	}{
+		{
+			name:             "Deadline Set",
+			target:           "/tasks",
+			expectedDeadline: true,
+		},
+		{
+			name:             "Deadline Set Despite Event Stream Accept",
+			target:           "/tasks",
+			accept:           "text/event-stream",
+			expectedDeadline: true,
+		},
+		{
+			name:   "Exempt Path",
+			target: "/tasks/events",
+		},
+		{
+			name:             "Exempt Path Prefix",
+			target:           "/tasks/events/1",
+			expectedDeadline: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			h := Timeout(time.Minute, "/tasks/events")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tt.expectedDeadline, hasDeadline)
+		})
+	}
+}
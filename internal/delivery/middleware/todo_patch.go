@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"mime"
+	"net/http"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+	jsonContentType       = "application/json"
+)
+
+// jsonPatchOperation is a single RFC 6902 operation.
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// CheckTodoPatch decodes the request body as an RFC 7396 merge patch (application/merge-patch+json or application/json)
+// or an RFC 6902 JSON patch (application/json-patch+json), validates the patched fields
+// and adds the resulting TodoPatchDto to the request context.
+func CheckTodoPatch(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil {
+				mediaType = ""
+			}
+
+			var todoPatch dto.TodoPatchDto
+			switch mediaType {
+			case mergePatchContentType, jsonContentType:
+				todoPatch, err = decodeMergePatch(r)
+			case jsonPatchContentType:
+				todoPatch, err = decodeJSONPatch(r)
+			default:
+				logger.FromContext(r.Context()).Info(delivery.ErrUnsupportedPatchType, "content_type", r.Header.Get("Content-Type"))
+				w.Header().Set("Accept-Patch", mergePatchContentType+", "+jsonPatchContentType)
+				delivery.RespondWithError(w, r, http.StatusUnsupportedMediaType, delivery.ErrUnsupportedPatchType)
+				return
+			}
+			if err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidPatch, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidPatch, err)
+				return
+			}
+
+			if err := validate.Struct(&todoPatch); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidPatch, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidPatch, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.TodoPatchKey, todoPatch)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// decodeMergePatch applies a merge patch document to an empty TodoPatchDto.
+// Members set to null remove tags and recurrence, required fields can't be removed.
+func decodeMergePatch(r *http.Request) (dto.TodoPatchDto, error) {
+	var document map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&document); err != nil {
+		return dto.TodoPatchDto{}, err
+	}
+	if document == nil {
+		return dto.TodoPatchDto{}, errors.New("merge patch must be a JSON object")
+	}
+
+	var todoPatch dto.TodoPatchDto
+	for member, value := range document {
+		if err := setPatchField(&todoPatch, member, value); err != nil {
+			return dto.TodoPatchDto{}, err
+		}
+	}
+
+	return todoPatch, nil
+}
+
+// decodeJSONPatch applies the add, replace and remove operations of a JSON patch document to an empty TodoPatchDto.
+// Fields are only set as a whole, so move, copy and test are not supported.
+func decodeJSONPatch(r *http.Request) (dto.TodoPatchDto, error) {
+	var operations []jsonPatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+		return dto.TodoPatchDto{}, err
+	}
+
+	var todoPatch dto.TodoPatchDto
+	for _, operation := range operations {
+		value := operation.Value
+		switch operation.Op {
+		case "add", "replace":
+			if value == nil {
+				return dto.TodoPatchDto{}, fmt.Errorf("operation %q requires a value", operation.Op)
+			}
+		case "remove":
+			value = nil
+		default:
+			return dto.TodoPatchDto{}, fmt.Errorf("unsupported operation %q", operation.Op)
+		}
+
+		if len(operation.Path) < 2 || operation.Path[0] != '/' {
+			return dto.TodoPatchDto{}, fmt.Errorf("invalid path %q", operation.Path)
+		}
+
+		if err := setPatchField(&todoPatch, operation.Path[1:], value); err != nil {
+			return dto.TodoPatchDto{}, err
+		}
+	}
+
+	return todoPatch, nil
+}
+
+// setPatchField sets field of todoPatch to value, a nil or null value removes the field.
+func setPatchField(todoPatch *dto.TodoPatchDto, field string, value json.RawMessage) error {
+	remove := value == nil || string(value) == "null"
+
+	var dst **string
+	switch field {
+	case "title":
+		dst = &todoPatch.Title
+	case "description":
+		dst = &todoPatch.Description
+	case "due_date":
+		dst = &todoPatch.DueDate
+	case "tags":
+		tags := []string{}
+		if !remove {
+			if err := json.Unmarshal(value, &tags); err != nil {
+				return fmt.Errorf("field %q: %w", field, err)
+			}
+		}
+		todoPatch.Tags = &tags
+
+		return nil
+	case "recurrence":
+		if remove {
+			todoPatch.Recurrence, todoPatch.RemoveRecurrence = nil, true
+			return nil
+		}
+		todoPatch.RemoveRecurrence = false
+		dst = &todoPatch.Recurrence
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+
+	if remove {
+		return fmt.Errorf("field %q can't be removed", field)
+	}
+
+	var str string
+	if err := json.Unmarshal(value, &str); err != nil {
+		return fmt.Errorf("field %q: %w", field, err)
+	}
+	*dst = &str
+
+	return nil
+}
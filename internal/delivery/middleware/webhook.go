@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"strconv"
+	"to-do-list-go/internal/delivery"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+// CheckWebhookInput validates the request body against the WebhookInputDto schema and adds it to the request context.
+func CheckWebhookInput(validate *validator.Validate) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			webhookInput := dto.WebhookInputDto{}
+			if err := json.NewDecoder(r.Body).Decode(&webhookInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidWebhookInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidWebhookInput, err)
+				return
+			}
+
+			if err := validate.Struct(&webhookInput); err != nil {
+				logger.FromContext(r.Context()).Info(delivery.ErrInvalidWebhookInput, "error", err)
+				delivery.RespondWithValidationError(w, r, delivery.ErrInvalidWebhookInput, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), delivery.WebhookInputKey, webhookInput)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetWebhookID extracts the webhook ID from the request URL and adds it to the request context.
+func GetWebhookID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || webhookID <= 0 {
+			logger.FromContext(r.Context()).Info(delivery.ErrInvalidWebhookID, "webhook_id", chi.URLParam(r, "id"))
+			delivery.RespondWithError(w, r, http.StatusBadRequest, delivery.ErrInvalidWebhookID)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), delivery.WebhookIDKey, webhookID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
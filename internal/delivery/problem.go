@@ -0,0 +1,113 @@
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+	"reflect"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+const problemContentType = "application/problem+json"
+
+// fieldErrorCodes maps validation rules to machine-readable codes of field errors.
+var fieldErrorCodes = map[string]string{
+	"required":         "required",
+	"required_without": "required",
+	"required_if":      "required",
+	"excluded_with":    "not_allowed",
+	"excluded_if":      "not_allowed",
+	"excluded_unless":  "not_allowed",
+	"rfc3339":          "invalid_datetime",
+	"oneof":            "not_allowed",
+	"base64rawurl":     "invalid_format",
+	"email":            "invalid_format",
+	"http_url":         "invalid_format",
+	"hexcolor":         "invalid_format",
+	"excludesall":      "invalid_format",
+	"rrule":            "invalid_format",
+	"maxbytes":         "too_long",
+}
+
+// RespondWithProblem sends an application/problem+json response describing the failed request to the client.
+func RespondWithProblem(w http.ResponseWriter, r *http.Request, code int, detail string, fieldErrors []dto.FieldErrorDto) {
+	problem := dto.ProblemDto{
+		Type:     "about:blank",
+		Title:    statusText(code),
+		Status:   code,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Errors:   fieldErrors,
+	}
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		logger.FromContext(r.Context()).Error(ErrMarshalingJSON, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// RespondWithValidationError sends a 400 problem response listing the fields err reports as invalid.
+func RespondWithValidationError(w http.ResponseWriter, r *http.Request, detail string, err error) {
+	RespondWithProblem(w, r, http.StatusBadRequest, detail, FieldErrors(err))
+}
+
+// FieldErrors lists the invalid fields reported by validator.ValidationErrors or a JSON type mismatch in err.
+func FieldErrors(err error) []dto.FieldErrorDto {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []dto.FieldErrorDto{{Field: typeErr.Field, Rule: "type", Code: "invalid_type"}}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fieldErrors := make([]dto.FieldErrorDto, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		fieldErrors[i] = dto.FieldErrorDto{
+			Field: fieldErr.Field(),
+			Rule:  fieldErr.Tag(),
+			Code:  fieldErrorCode(fieldErr),
+		}
+	}
+	return fieldErrors
+}
+
+func fieldErrorCode(fieldErr validator.FieldError) string {
+	if code, ok := fieldErrorCodes[fieldErr.Tag()]; ok {
+		return code
+	}
+
+	sized := fieldErr.Kind() == reflect.String || fieldErr.Kind() == reflect.Slice
+	switch fieldErr.Tag() {
+	case "min":
+		if sized {
+			return "too_short"
+		}
+		return "too_small"
+	case "max":
+		if sized {
+			return "too_long"
+		}
+		return "too_large"
+	default:
+		return "invalid"
+	}
+}
+
+func statusText(code int) string {
+	if code == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+
+	return http.StatusText(code)
+}
@@ -1,17 +1,34 @@
 package delivery
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
 )
 
+// StatusClientClosedRequest is the nginx status code for requests whose client disconnected before the response.
+const StatusClientClosedRequest = 499
+
+// domainErrorStatuses maps kinds of domain errors to the HTTP status codes they are reported with.
+var domainErrorStatuses = map[error]int{
+	domain.ErrNotFound:           http.StatusNotFound,
+	domain.ErrConflict:           http.StatusConflict,
+	domain.ErrValidation:         http.StatusBadRequest,
+	domain.ErrUnauthorized:       http.StatusUnauthorized,
+	domain.ErrForbidden:          http.StatusForbidden,
+	domain.ErrPreconditionFailed: http.StatusPreconditionFailed,
+}
+
 // RespondWithJSON sends a JSON response with the given HTTP status code and payload to the client.
 func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	data, err := json.Marshal(payload)
 
 	if err != nil {
-		log.Printf(ErrMarshalingJSON+": %v", payload)
+		slog.Error(ErrMarshalingJSON, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -21,13 +38,37 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(data)
 }
 
-// RespondWithError sends a JSON response with an error message and status code to the client.
-func RespondWithError(w http.ResponseWriter, code int, msg string) {
-	type errResponse struct {
-		Error string `json:"error"`
+// RespondWithError sends a problem response with an error message and status code to the client.
+func RespondWithError(w http.ResponseWriter, r *http.Request, code int, msg string) {
+	RespondWithProblem(w, r, code, msg, nil)
+}
+
+// RespondWithServiceError translates an error returned by a service into an error response.
+// Errors caused by the request deadline or the client going away are reported with 504 and 499.
+// Domain errors are reported with the status of their kind and their own message,
+// any other error is reported as an internal server error with fallbackMsg.
+func RespondWithServiceError(w http.ResponseWriter, r *http.Request, err error, fallbackMsg string) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+		logger.FromContext(r.Context()).Warn(ErrRequestTimeout, "error", err)
+		RespondWithError(w, r, http.StatusGatewayTimeout, ErrRequestTimeout)
+		return
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(r.Context().Err(), context.Canceled) {
+		logger.FromContext(r.Context()).Info(ErrClientClosedRequest, "error", err)
+		RespondWithError(w, r, StatusClientClosedRequest, ErrClientClosedRequest)
+		return
+	}
+
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		if code, ok := domainErrorStatuses[domainErr.Kind]; ok {
+			logger.FromContext(r.Context()).Info(domainErr.Message, "error", err)
+			RespondWithError(w, r, code, domainErr.Message)
+			return
+		}
 	}
 
-	RespondWithJSON(w, code, errResponse{
-		Error: msg,
-	})
+	logger.FromContext(r.Context()).Error(fallbackMsg, "error", err)
+	RespondWithError(w, r, http.StatusInternalServerError, fallbackMsg)
 }
@@ -0,0 +1,36 @@
+package domain
+
+import "errors"
+
+// Defines kinds of domain errors, delivery maps each kind to a response status.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrValidation         = errors.New("validation failed")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error is a domain error of a certain kind with a message that is safe to show to clients.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+// NewError creates a domain error of the given kind.
+func NewError(kind error, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the kind of the error, so errors.Is matches it.
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// BrokerConn is the part of a message broker client events are published through.
+// A NATS connection satisfies it as is, other brokers such as Kafka need a thin adapter.
+type BrokerConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// Broker publishes events as JSON messages to a message broker,
+// on subjects made of the configured prefix and the event type, e.g. "todos.todo.created".
+type Broker struct {
+	conn   BrokerConn
+	prefix string
+}
+
+// NewBroker creates a publisher sending events through conn to subjects starting with prefix.
+func NewBroker(conn BrokerConn, prefix string) *Broker {
+	return &Broker{
+		conn:   conn,
+		prefix: prefix,
+	}
+}
+
+// Publish sends event to the subject of its type.
+func (b *Broker) Publish(ctx context.Context, event Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	if err := b.conn.Publish(b.prefix+"."+event.Type, data); err != nil {
+		return fmt.Errorf("publish event %d: %w", event.ID, err)
+	}
+
+	return nil
+}
+
+// localHandler is a handler subscribed to a LocalBroker.
+type localHandler struct {
+	prefix string
+	handle func(subject string, data []byte)
+}
+
+// LocalBroker is an in-memory stand-in for a message broker, for development and tests.
+// Messages are handed synchronously to the handlers subscribed to their subjects and rejected when there are none.
+type LocalBroker struct {
+	mu       sync.RWMutex
+	handlers []localHandler
+}
+
+// NewLocalBroker creates a broker without subscribers.
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{}
+}
+
+// Subscribe calls handle for every message published to a subject starting with prefix.
+func (b *LocalBroker) Subscribe(prefix string, handle func(subject string, data []byte)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, localHandler{prefix: prefix, handle: handle})
+}
+
+// Publish hands data to the handlers subscribed to subject, it fails with ErrNoSubscribers when there are none.
+func (b *LocalBroker) Publish(subject string, data []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var handled bool
+	for _, h := range b.handlers {
+		if strings.HasPrefix(subject, h.prefix) {
+			h.handle(subject, data)
+			handled = true
+		}
+	}
+
+	if !handled {
+		return fmt.Errorf("%w: %s", ErrNoSubscribers, subject)
+	}
+
+	return nil
+}
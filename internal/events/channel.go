@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// subscription is a consumer of the events published through a Channel.
+// mu is held while an event is sent to it, so no event is sent once closed is set.
+type subscription struct {
+	events chan Event
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// Channel publishes events to consumers in the same process through Go channels.
+// Events reaching no subscriber are rejected with ErrNoSubscribers.
+type Channel struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+// NewChannel creates a publisher without subscribers.
+func NewChannel() *Channel {
+	return &Channel{
+		subs: make(map[*subscription]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the events published from now on, buffered to size,
+// and a function ending the subscription. The channel is never closed, but no event is sent to it
+// once the function has returned, so the consumer can handle the events left in the buffer afterwards.
+func (c *Channel) Subscribe(size int) (<-chan Event, func()) {
+	sub := &subscription{
+		events: make(chan Event, size),
+		done:   make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	c.subs[sub] = struct{}{}
+	c.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			c.mu.Lock()
+			delete(c.subs, sub)
+			c.mu.Unlock()
+			close(sub.done)
+
+			sub.mu.Lock()
+			sub.closed = true
+			sub.mu.Unlock()
+		})
+	}
+}
+
+// Publish sends event to every subscriber. It waits for subscribers whose buffers are full,
+// so a slow consumer holds the publisher back rather than losing events.
+func (c *Channel) Publish(ctx context.Context, event Event) error {
+	c.mu.Lock()
+	subs := make([]*subscription, 0, len(c.subs))
+	for sub := range c.subs {
+		subs = append(subs, sub)
+	}
+	c.mu.Unlock()
+
+	var sent int
+	for _, sub := range subs {
+		ok, err := sub.send(ctx, event)
+		if err != nil {
+			return err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	if sent == 0 {
+		return fmt.Errorf("%w: %d", ErrNoSubscribers, event.ID)
+	}
+
+	return nil
+}
+
+// send sends event to the subscriber and reports whether it did, it doesn't once the subscription has ended.
+func (s *subscription) send(ctx context.Context, event Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false, nil
+	}
+
+	select {
+	case s.events <- event:
+		return true, nil
+	case <-s.done:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
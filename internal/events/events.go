@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrNoSubscribers is returned by publishers that hand events to subscribers when an event has none,
+// so it is published again later rather than marked as sent.
+var ErrNoSubscribers = errors.New("event has no subscribers")
+
+// Event is a change of a todo saved to the outbox in the same transaction as the change itself.
+// ID grows with every saved event, so consumers can use it to skip events they have already seen.
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int32           `json:"user_id"`
+	TodoID     int32           `json:"todo_id"`
+	Payload    json.RawMessage `json:"payload"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// EventPublisher hands events over to their consumers once they have been committed.
+// Publish returns once the event has been accepted, or with an error when it hasn't,
+// so the caller can retry it later. Publishing is aborted once ctx is done.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
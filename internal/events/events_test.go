@@ -0,0 +1,107 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+var testEvent = Event{
+	ID:         42,
+	Type:       "todo.created",
+	UserID:     1,
+	TodoID:     7,
+	Payload:    json.RawMessage(`{"event":"todo.created","data":{"id":7}}`),
+	OccurredAt: time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC),
+}
+
+func TestChannel(t *testing.T) {
+	c := NewChannel()
+	require.ErrorIs(t, c.Publish(context.Background(), testEvent), ErrNoSubscribers)
+
+	first, cancelFirst := c.Subscribe(1)
+	second, cancelSecond := c.Subscribe(1)
+	defer cancelSecond()
+
+	require.NoError(t, c.Publish(context.Background(), testEvent))
+	require.Equal(t, testEvent, <-first)
+	require.Equal(t, testEvent, <-second)
+
+	cancelFirst()
+	cancelFirst()
+	require.NoError(t, c.Publish(context.Background(), testEvent))
+	require.Equal(t, testEvent, <-second)
+	require.Empty(t, first)
+
+	require.NoError(t, c.Publish(context.Background(), testEvent))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.Publish(ctx, testEvent), context.DeadlineExceeded)
+
+	// Ending a subscription releases a publisher waiting for it, and the event is left for the next attempt.
+	published := make(chan error, 1)
+	go func() {
+		published <- c.Publish(context.Background(), testEvent)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancelSecond()
+	require.ErrorIs(t, <-published, ErrNoSubscribers)
+	require.Len(t, second, 1)
+}
+
+// failingConn is a BrokerConn that is down.
+type failingConn struct{}
+
+func (failingConn) Publish(string, []byte) error {
+	return errors.New("connection closed")
+}
+
+func TestBroker(t *testing.T) {
+	local := NewLocalBroker()
+	var subjects []string
+	var received []Event
+	local.Subscribe("todos.", func(subject string, data []byte) {
+		var event Event
+		require.NoError(t, json.Unmarshal(data, &event))
+		subjects = append(subjects, subject)
+		received = append(received, event)
+	})
+	local.Subscribe("other.", func(string, []byte) {
+		t.Fatal("message delivered to a foreign subject")
+	})
+
+	require.NoError(t, NewBroker(local, "todos").Publish(context.Background(), testEvent))
+	require.Equal(t, []string{"todos.todo.created"}, subjects)
+	require.Equal(t, []Event{testEvent}, received)
+
+	require.Error(t, NewBroker(failingConn{}, "todos").Publish(context.Background(), testEvent))
+	require.ErrorIs(t, NewBroker(local, "unknown").Publish(context.Background(), testEvent), ErrNoSubscribers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, NewBroker(local, "todos").Publish(ctx, testEvent), context.Canceled)
+	require.Len(t, received, 1)
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	require.NoError(t, l.Publish(context.Background(), testEvent))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "todo event", record["msg"])
+	require.Equal(t, float64(42), record["event_id"])
+	require.Equal(t, "todo.created", record["type"])
+	require.Equal(t, string(testEvent.Payload), record["payload"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, l.Publish(ctx, testEvent), context.Canceled)
+}
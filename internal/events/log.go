@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log publishes events by writing them to the application log, for running without a message broker.
+// The log is the only record of the published events, so it has to be kept for as long as they are needed.
+type Log struct {
+	logger *slog.Logger
+}
+
+// NewLog creates a publisher writing events to logger.
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{
+		logger: logger,
+	}
+}
+
+// Publish writes event to the log, it only fails once ctx is done.
+func (l *Log) Publish(ctx context.Context, event Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.logger.InfoContext(ctx, "todo event",
+		"event_id", event.ID,
+		"type", event.Type,
+		"user_id", event.UserID,
+		"todo_id", event.TodoID,
+		"occurred_at", event.OccurredAt,
+		"payload", string(event.Payload),
+	)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"to-do-list-go/internal/domain"
+)
+
+const namespace = "todos"
+
+// Metrics holds the Prometheus collectors of the application and the registry they are exposed from.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	serviceCalls        *prometheus.CounterVec
+	serviceErrors       *prometheus.CounterVec
+	dbQueryDuration     *prometheus.HistogramVec
+	dbQueryErrors       *prometheus.CounterVec
+}
+
+// New creates Metrics with HTTP, service and database collectors registered alongside the Go runtime and process ones.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of handled HTTP requests by method, chi route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of handled HTTP requests by method and chi route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		serviceCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "service_calls_total",
+			Help:      "Number of calls of service methods.",
+		}, []string{"method"}),
+		serviceErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "service_errors_total",
+			Help:      "Number of errors returned by service methods by error kind.",
+		}, []string{"method", "kind"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Latency of database queries by repository method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"query"}),
+		dbQueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "Number of failed database queries by repository method.",
+		}, []string{"query"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.serviceCalls,
+		m.serviceErrors,
+		m.dbQueryDuration,
+		m.dbQueryErrors,
+	)
+
+	return m
+}
+
+// RegisterDBStats exposes the connection pool statistics of db, such as open and in-use connections and the wait count.
+func (m *Metrics) RegisterDBStats(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler returns the handler serving the collected metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a handled HTTP request.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) observeServiceCall(method string, err error) {
+	m.serviceCalls.WithLabelValues(method).Inc()
+	if err != nil {
+		m.serviceErrors.WithLabelValues(method, errorKind(err)).Inc()
+	}
+}
+
+func (m *Metrics) observeQuery(query string, start time.Time, err error) {
+	m.dbQueryDuration.WithLabelValues(query).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.dbQueryErrors.WithLabelValues(query).Inc()
+	}
+}
+
+// errorKind names the kind of a service error for the kind label.
+func errorKind(err error) string {
+	var domainErr *domain.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &domainErr):
+		return strings.ReplaceAll(domainErr.Kind.Error(), " ", "_")
+	default:
+		return "internal"
+	}
+}
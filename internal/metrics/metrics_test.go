@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/service"
+)
+
+func TestDecorators(t *testing.T) {
+	tests := []struct {
+		name               string
+		repoErr            error
+		expectedKind       string
+		expectedServiceErr float64
+		expectedQueryErr   float64
+	}{
+		{
+			name:               "Success",
+			repoErr:            nil,
+			expectedServiceErr: 0,
+			expectedQueryErr:   0,
+		},
+		{
+			name:               "Not Found",
+			repoErr:            sql.ErrNoRows,
+			expectedKind:       "not_found",
+			expectedServiceErr: 1,
+			expectedQueryErr:   0,
+		},
+		{
+			name:               "Timeout",
+			repoErr:            fmt.Errorf("query: %w", context.DeadlineExceeded),
+			expectedKind:       "timeout",
+			expectedServiceErr: 1,
+			expectedQueryErr:   1,
+		},
+		{
+			name:               "Internal",
+			repoErr:            errors.New("some db error"),
+			expectedKind:       "internal",
+			expectedServiceErr: 1,
+			expectedQueryErr:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{ID: 1}, tt.repoErr).Times(1)
+			queries := 1
+			if tt.repoErr == nil {
+				queries += 3
+				repo.EXPECT().ListTodoTags(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+				repo.EXPECT().ListSubtaskProgress(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+				repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+			}
+
+			m := New()
+			s := service.NewService(NewRepository(repo, m), mock_repo.NewMockPool(ctl), service.AuthConfig{}, service.TodoConfig{})
+			todos := NewTodos(s.Todos, m)
+
+			_, err := todos.GetTodo(context.Background(), 1, 1, nil)
+			require.Equal(t, tt.repoErr != nil, err != nil)
+
+			require.Equal(t, float64(1), testutil.ToFloat64(m.serviceCalls.WithLabelValues("GetTodo")))
+			if tt.expectedKind != "" {
+				require.Equal(t, tt.expectedServiceErr, testutil.ToFloat64(m.serviceErrors.WithLabelValues("GetTodo", tt.expectedKind)))
+			}
+			require.Equal(t, tt.expectedQueryErr, testutil.ToFloat64(m.dbQueryErrors.WithLabelValues("GetTodo")))
+			require.Equal(t, queries, testutil.CollectAndCount(m.dbQueryDuration))
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"to-do-list-go/internal/database"
+)
+
+// repository measures latency and failures of the queries of the wrapped database.Repository.
+type repository struct {
+	next    database.Repository
+	metrics *Metrics
+}
+
+// NewRepository wraps repo, so each of its queries is recorded in m.
+func NewRepository(repo database.Repository, m *Metrics) database.Repository {
+	return &repository{
+		next:    repo,
+		metrics: m,
+	}
+}
+
+func (r *repository) observe(query string, start time.Time, err *error) {
+	r.metrics.observeQuery(query, start, *err)
+}
+
+func (r *repository) CreateTodo(ctx context.Context, arg database.CreateTodoParams) (todo database.Todo, err error) {
+	defer r.observe("CreateTodo", time.Now(), &err)
+	return r.next.CreateTodo(ctx, arg)
+}
+
+func (r *repository) ListTodos(ctx context.Context, arg database.ListTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("ListTodos", time.Now(), &err)
+	return r.next.ListTodos(ctx, arg)
+}
+
+func (r *repository) GetTodo(ctx context.Context, arg database.GetTodoParams) (todo database.Todo, err error) {
+	defer r.observe("GetTodo", time.Now(), &err)
+	return r.next.GetTodo(ctx, arg)
+}
+
+func (r *repository) UpdateTodo(ctx context.Context, arg database.UpdateTodoParams) (todo database.Todo, err error) {
+	defer r.observe("UpdateTodo", time.Now(), &err)
+	return r.next.UpdateTodo(ctx, arg)
+}
+
+func (r *repository) PatchTodo(ctx context.Context, arg database.PatchTodoParams) (todo database.Todo, err error) {
+	defer r.observe("PatchTodo", time.Now(), &err)
+	return r.next.PatchTodo(ctx, arg)
+}
+
+func (r *repository) DeleteTodo(ctx context.Context, arg database.DeleteTodoParams) (todos []database.Todo, err error) {
+	defer r.observe("DeleteTodo", time.Now(), &err)
+	return r.next.DeleteTodo(ctx, arg)
+}
+
+func (r *repository) UpdateTodoStatus(ctx context.Context, arg database.UpdateTodoStatusParams) (todo database.Todo, err error) {
+	defer r.observe("UpdateTodoStatus", time.Now(), &err)
+	return r.next.UpdateTodoStatus(ctx, arg)
+}
+
+func (r *repository) TouchTodo(ctx context.Context, arg database.TouchTodoParams) (todo database.Todo, err error) {
+	defer r.observe("TouchTodo", time.Now(), &err)
+	return r.next.TouchTodo(ctx, arg)
+}
+
+func (r *repository) SearchTodos(ctx context.Context, arg database.SearchTodosParams) (rows []database.SearchTodosRow, err error) {
+	defer r.observe("SearchTodos", time.Now(), &err)
+	return r.next.SearchTodos(ctx, arg)
+}
+
+func (r *repository) MigrationVersion(ctx context.Context) (version int64, err error) {
+	defer r.observe("MigrationVersion", time.Now(), &err)
+	return r.next.MigrationVersion(ctx)
+}
+
+func (r *repository) CreateUser(ctx context.Context, arg database.CreateUserParams) (user database.User, err error) {
+	defer r.observe("CreateUser", time.Now(), &err)
+	return r.next.CreateUser(ctx, arg)
+}
+
+func (r *repository) GetUserByEmail(ctx context.Context, email string) (user database.User, err error) {
+	defer r.observe("GetUserByEmail", time.Now(), &err)
+	return r.next.GetUserByEmail(ctx, email)
+}
+
+func (r *repository) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (token database.RefreshToken, err error) {
+	defer r.observe("CreateRefreshToken", time.Now(), &err)
+	return r.next.CreateRefreshToken(ctx, arg)
+}
+
+func (r *repository) ConsumeRefreshToken(ctx context.Context, tokenHash string) (token database.RefreshToken, err error) {
+	defer r.observe("ConsumeRefreshToken", time.Now(), &err)
+	return r.next.ConsumeRefreshToken(ctx, tokenHash)
+}
+
+func (r *repository) CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (key database.APIKey, err error) {
+	defer r.observe("CreateAPIKey", time.Now(), &err)
+	return r.next.CreateAPIKey(ctx, arg)
+}
+
+func (r *repository) ListAPIKeys(ctx context.Context, userID int32) (keys []database.APIKey, err error) {
+	defer r.observe("ListAPIKeys", time.Now(), &err)
+	return r.next.ListAPIKeys(ctx, userID)
+}
+
+func (r *repository) RevokeAPIKey(ctx context.Context, arg database.RevokeAPIKeyParams) (key database.APIKey, err error) {
+	defer r.observe("RevokeAPIKey", time.Now(), &err)
+	return r.next.RevokeAPIKey(ctx, arg)
+}
+
+func (r *repository) UseAPIKey(ctx context.Context, keyHash string) (key database.APIKey, err error) {
+	defer r.observe("UseAPIKey", time.Now(), &err)
+	return r.next.UseAPIKey(ctx, keyHash)
+}
+
+func (r *repository) MoveTodo(ctx context.Context, arg database.MoveTodoParams) (todo database.Todo, err error) {
+	defer r.observe("MoveTodo", time.Now(), &err)
+	return r.next.MoveTodo(ctx, arg)
+}
+
+func (r *repository) CreateProject(ctx context.Context, arg database.CreateProjectParams) (project database.Project, err error) {
+	defer r.observe("CreateProject", time.Now(), &err)
+	return r.next.CreateProject(ctx, arg)
+}
+
+func (r *repository) ListProjects(ctx context.Context, arg database.ListProjectsParams) (projects []database.Project, err error) {
+	defer r.observe("ListProjects", time.Now(), &err)
+	return r.next.ListProjects(ctx, arg)
+}
+
+func (r *repository) GetProject(ctx context.Context, arg database.GetProjectParams) (project database.Project, err error) {
+	defer r.observe("GetProject", time.Now(), &err)
+	return r.next.GetProject(ctx, arg)
+}
+
+func (r *repository) UpdateProject(ctx context.Context, arg database.UpdateProjectParams) (project database.Project, err error) {
+	defer r.observe("UpdateProject", time.Now(), &err)
+	return r.next.UpdateProject(ctx, arg)
+}
+
+func (r *repository) DeleteProject(ctx context.Context, arg database.DeleteProjectParams) (project database.Project, err error) {
+	defer r.observe("DeleteProject", time.Now(), &err)
+	return r.next.DeleteProject(ctx, arg)
+}
+
+func (r *repository) DeleteProjectTodos(ctx context.Context, arg database.DeleteProjectTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("DeleteProjectTodos", time.Now(), &err)
+	return r.next.DeleteProjectTodos(ctx, arg)
+}
+
+func (r *repository) MoveProjectTodos(ctx context.Context, arg database.MoveProjectTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("MoveProjectTodos", time.Now(), &err)
+	return r.next.MoveProjectTodos(ctx, arg)
+}
+
+func (r *repository) SetTodoTags(ctx context.Context, arg database.SetTodoTagsParams) (err error) {
+	defer r.observe("SetTodoTags", time.Now(), &err)
+	return r.next.SetTodoTags(ctx, arg)
+}
+
+func (r *repository) ListTodoTags(ctx context.Context, todoIDs []int32) (rows []database.ListTodoTagsRow, err error) {
+	defer r.observe("ListTodoTags", time.Now(), &err)
+	return r.next.ListTodoTags(ctx, todoIDs)
+}
+
+func (r *repository) ListTags(ctx context.Context, ownerID int32) (tags []database.ListTagsRow, err error) {
+	defer r.observe("ListTags", time.Now(), &err)
+	return r.next.ListTags(ctx, ownerID)
+}
+
+func (r *repository) ListSubtaskTree(ctx context.Context, arg database.ListSubtaskTreeParams) (todos []database.Todo, err error) {
+	defer r.observe("ListSubtaskTree", time.Now(), &err)
+	return r.next.ListSubtaskTree(ctx, arg)
+}
+
+func (r *repository) ListTodoAncestors(ctx context.Context, arg database.ListTodoAncestorsParams) (ids []int32, err error) {
+	defer r.observe("ListTodoAncestors", time.Now(), &err)
+	return r.next.ListTodoAncestors(ctx, arg)
+}
+
+func (r *repository) SetTodoParent(ctx context.Context, arg database.SetTodoParentParams) (todo database.Todo, err error) {
+	defer r.observe("SetTodoParent", time.Now(), &err)
+	return r.next.SetTodoParent(ctx, arg)
+}
+
+func (r *repository) ListSubtaskProgress(ctx context.Context, todoIDs []int32) (rows []database.ListSubtaskProgressRow, err error) {
+	defer r.observe("ListSubtaskProgress", time.Now(), &err)
+	return r.next.ListSubtaskProgress(ctx, todoIDs)
+}
+
+func (r *repository) CompleteParentTodo(ctx context.Context, arg database.CompleteParentTodoParams) (todo database.Todo, err error) {
+	defer r.observe("CompleteParentTodo", time.Now(), &err)
+	return r.next.CompleteParentTodo(ctx, arg)
+}
+
+func (r *repository) LockTodoDependencies(ctx context.Context, ownerID int32) (err error) {
+	defer r.observe("LockTodoDependencies", time.Now(), &err)
+	return r.next.LockTodoDependencies(ctx, ownerID)
+}
+
+func (r *repository) AddTodoBlocker(ctx context.Context, arg database.AddTodoBlockerParams) (rows int64, err error) {
+	defer r.observe("AddTodoBlocker", time.Now(), &err)
+	return r.next.AddTodoBlocker(ctx, arg)
+}
+
+func (r *repository) RemoveTodoBlocker(ctx context.Context, arg database.RemoveTodoBlockerParams) (dependency database.TodoDependency, err error) {
+	defer r.observe("RemoveTodoBlocker", time.Now(), &err)
+	return r.next.RemoveTodoBlocker(ctx, arg)
+}
+
+func (r *repository) ListTodoBlockerChain(ctx context.Context, arg database.ListTodoBlockerChainParams) (ids []int32, err error) {
+	defer r.observe("ListTodoBlockerChain", time.Now(), &err)
+	return r.next.ListTodoBlockerChain(ctx, arg)
+}
+
+func (r *repository) ListTodoBlockers(ctx context.Context, todoIDs []int32) (rows []database.ListTodoBlockersRow, err error) {
+	defer r.observe("ListTodoBlockers", time.Now(), &err)
+	return r.next.ListTodoBlockers(ctx, todoIDs)
+}
+
+func (r *repository) TouchBlockedTodos(ctx context.Context, arg database.TouchBlockedTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("TouchBlockedTodos", time.Now(), &err)
+	return r.next.TouchBlockedTodos(ctx, arg)
+}
+
+func (r *repository) ListProjectTodos(ctx context.Context, arg database.ListProjectTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("ListProjectTodos", time.Now(), &err)
+	return r.next.ListProjectTodos(ctx, arg)
+}
+
+func (r *repository) CreateNextOccurrence(ctx context.Context, arg database.CreateNextOccurrenceParams) (todo database.CreateNextOccurrenceRow, err error) {
+	defer r.observe("CreateNextOccurrence", time.Now(), &err)
+	return r.next.CreateNextOccurrence(ctx, arg)
+}
+
+func (r *repository) EndTodoRecurrence(ctx context.Context, id int32) (err error) {
+	defer r.observe("EndTodoRecurrence", time.Now(), &err)
+	return r.next.EndTodoRecurrence(ctx, id)
+}
+
+func (r *repository) ListDueRecurringTodos(ctx context.Context, arg database.ListDueRecurringTodosParams) (todos []database.Todo, err error) {
+	defer r.observe("ListDueRecurringTodos", time.Now(), &err)
+	return r.next.ListDueRecurringTodos(ctx, arg)
+}
+
+func (r *repository) CreateReminder(ctx context.Context, arg database.CreateReminderParams) (reminder database.Reminder, err error) {
+	defer r.observe("CreateReminder", time.Now(), &err)
+	return r.next.CreateReminder(ctx, arg)
+}
+
+func (r *repository) ListReminders(ctx context.Context, arg database.ListRemindersParams) (reminders []database.Reminder, err error) {
+	defer r.observe("ListReminders", time.Now(), &err)
+	return r.next.ListReminders(ctx, arg)
+}
+
+func (r *repository) DeleteReminder(ctx context.Context, arg database.DeleteReminderParams) (reminder database.Reminder, err error) {
+	defer r.observe("DeleteReminder", time.Now(), &err)
+	return r.next.DeleteReminder(ctx, arg)
+}
+
+func (r *repository) ClaimDueReminders(ctx context.Context, arg database.ClaimDueRemindersParams) (reminders []database.ClaimDueRemindersRow, err error) {
+	defer r.observe("ClaimDueReminders", time.Now(), &err)
+	return r.next.ClaimDueReminders(ctx, arg)
+}
+
+func (r *repository) MarkReminderSent(ctx context.Context, id int32) (err error) {
+	defer r.observe("MarkReminderSent", time.Now(), &err)
+	return r.next.MarkReminderSent(ctx, id)
+}
+
+func (r *repository) RecordReminderFailure(ctx context.Context, arg database.RecordReminderFailureParams) (err error) {
+	defer r.observe("RecordReminderFailure", time.Now(), &err)
+	return r.next.RecordReminderFailure(ctx, arg)
+}
+
+func (r *repository) CreateWebhook(ctx context.Context, arg database.CreateWebhookParams) (rv database.Webhook, err error) {
+	defer r.observe("CreateWebhook", time.Now(), &err)
+	return r.next.CreateWebhook(ctx, arg)
+}
+
+func (r *repository) ListWebhooks(ctx context.Context, userID int32) (rv []database.Webhook, err error) {
+	defer r.observe("ListWebhooks", time.Now(), &err)
+	return r.next.ListWebhooks(ctx, userID)
+}
+
+func (r *repository) GetWebhook(ctx context.Context, arg database.GetWebhookParams) (rv database.Webhook, err error) {
+	defer r.observe("GetWebhook", time.Now(), &err)
+	return r.next.GetWebhook(ctx, arg)
+}
+
+func (r *repository) UpdateWebhook(ctx context.Context, arg database.UpdateWebhookParams) (rv database.Webhook, err error) {
+	defer r.observe("UpdateWebhook", time.Now(), &err)
+	return r.next.UpdateWebhook(ctx, arg)
+}
+
+func (r *repository) DeleteWebhook(ctx context.Context, arg database.DeleteWebhookParams) (rv database.Webhook, err error) {
+	defer r.observe("DeleteWebhook", time.Now(), &err)
+	return r.next.DeleteWebhook(ctx, arg)
+}
+
+func (r *repository) ListWebhookDeliveries(ctx context.Context, arg database.ListWebhookDeliveriesParams) (rv []database.WebhookDelivery, err error) {
+	defer r.observe("ListWebhookDeliveries", time.Now(), &err)
+	return r.next.ListWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) ClaimWebhookDeliveries(ctx context.Context, arg database.ClaimWebhookDeliveriesParams) (rv []database.ClaimWebhookDeliveriesRow, err error) {
+	defer r.observe("ClaimWebhookDeliveries", time.Now(), &err)
+	return r.next.ClaimWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) RecordWebhookDeliveryFailure(ctx context.Context, arg database.RecordWebhookDeliveryFailureParams) (rv database.Webhook, err error) {
+	defer r.observe("RecordWebhookDeliveryFailure", time.Now(), &err)
+	return r.next.RecordWebhookDeliveryFailure(ctx, arg)
+}
+
+func (r *repository) EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) (err error) {
+	defer r.observe("EnqueueWebhookDeliveries", time.Now(), &err)
+	return r.next.EnqueueWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) RecordWebhookDeliverySuccess(ctx context.Context, arg database.RecordWebhookDeliverySuccessParams) (err error) {
+	defer r.observe("RecordWebhookDeliverySuccess", time.Now(), &err)
+	return r.next.RecordWebhookDeliverySuccess(ctx, arg)
+}
+
+func (r *repository) ClaimOutboxEvents(ctx context.Context, rowLimit int32) (rv []database.Outbox, err error) {
+	defer r.observe("ClaimOutboxEvents", time.Now(), &err)
+	return r.next.ClaimOutboxEvents(ctx, rowLimit)
+}
+
+func (r *repository) InsertOutboxEvent(ctx context.Context, arg database.InsertOutboxEventParams) (err error) {
+	defer r.observe("InsertOutboxEvent", time.Now(), &err)
+	return r.next.InsertOutboxEvent(ctx, arg)
+}
+
+func (r *repository) MarkOutboxEventsSent(ctx context.Context, ids []int64) (err error) {
+	defer r.observe("MarkOutboxEventsSent", time.Now(), &err)
+	return r.next.MarkOutboxEventsSent(ctx, ids)
+}
+
+func (r *repository) DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) (err error) {
+	defer r.observe("DeleteSentOutboxEvents", time.Now(), &err)
+	return r.next.DeleteSentOutboxEvents(ctx, sentBefore)
+}
+
+// InTx runs fn in a transaction of the next repository, recording its queries like those made outside of it.
+func (r *repository) InTx(ctx context.Context, fn func(repo database.Repository) error) (err error) {
+	defer r.observe("InTx", time.Now(), &err)
+	return r.next.InTx(ctx, func(repo database.Repository) error {
+		return fn(&repository{next: repo, metrics: r.metrics})
+	})
+}
+
+func (r *repository) GetOutboxEvent(ctx context.Context, id int64) (rv database.Outbox, err error) {
+	defer r.observe("GetOutboxEvent", time.Now(), &err)
+	return r.next.GetOutboxEvent(ctx, id)
+}
+
+func (r *repository) ListTodoEvents(ctx context.Context, arg database.ListTodoEventsParams) (rv []database.Outbox, err error) {
+	defer r.observe("ListTodoEvents", time.Now(), &err)
+	return r.next.ListTodoEvents(ctx, arg)
+}
+
+func (r *repository) GetLastTodoEventPosition(ctx context.Context, userID int32) (position int64, err error) {
+	defer r.observe("GetLastTodoEventPosition", time.Now(), &err)
+	return r.next.GetLastTodoEventPosition(ctx, userID)
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/service"
+)
+
+// todos counts calls and errors of the wrapped service.Todos methods.
+type todos struct {
+	next    service.Todos
+	metrics *Metrics
+}
+
+// NewTodos wraps todoService, so each of its calls is recorded in m.
+func NewTodos(todoService service.Todos, m *Metrics) service.Todos {
+	return &todos{
+		next:    todoService,
+		metrics: m,
+	}
+}
+
+func (t *todos) observe(method string, err *error) {
+	t.metrics.observeServiceCall(method, *err)
+}
+
+func (t *todos) CreateTodo(ctx context.Context, userID int32, todoInput dto.TodoInputDto, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("CreateTodo", &err)
+	return t.next.CreateTodo(ctx, userID, todoInput, loc)
+}
+
+func (t *todos) GetTodos(ctx context.Context, userID int32, todosQuery dto.TodosQueryDto, loc *time.Location) (page dto.TodosPageDto, err error) {
+	defer t.observe("GetTodos", &err)
+	return t.next.GetTodos(ctx, userID, todosQuery, loc)
+}
+
+func (t *todos) SearchTodos(ctx context.Context, userID int32, searchQuery dto.TodoSearchQueryDto, loc *time.Location) (results dto.TodoSearchResultsDto, err error) {
+	defer t.observe("SearchTodos", &err)
+	return t.next.SearchTodos(ctx, userID, searchQuery, loc)
+}
+
+func (t *todos) GetTodo(ctx context.Context, userID int32, todoID int, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("GetTodo", &err)
+	return t.next.GetTodo(ctx, userID, todoID, loc)
+}
+
+func (t *todos) UpdateTodo(ctx context.Context, userID int32, todoID int, todoInput dto.TodoInputDto, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("UpdateTodo", &err)
+	return t.next.UpdateTodo(ctx, userID, todoID, todoInput, versions, loc)
+}
+
+func (t *todos) PatchTodo(ctx context.Context, userID int32, todoID int, todoPatch dto.TodoPatchDto, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("PatchTodo", &err)
+	return t.next.PatchTodo(ctx, userID, todoID, todoPatch, versions, loc)
+}
+
+func (t *todos) DeleteTodo(ctx context.Context, userID int32, todoID int, versions []int32) (err error) {
+	defer t.observe("DeleteTodo", &err)
+	return t.next.DeleteTodo(ctx, userID, todoID, versions)
+}
+
+func (t *todos) MoveTodo(ctx context.Context, userID int32, todoID int, todoMove dto.TodoMoveDto, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("MoveTodo", &err)
+	return t.next.MoveTodo(ctx, userID, todoID, todoMove, versions, loc)
+}
+
+func (t *todos) StartTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("StartTodo", &err)
+	return t.next.StartTodo(ctx, userID, todoID, versions, loc)
+}
+
+func (t *todos) CompleteTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("CompleteTodo", &err)
+	return t.next.CompleteTodo(ctx, userID, todoID, versions, loc)
+}
+
+func (t *todos) CancelTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("CancelTodo", &err)
+	return t.next.CancelTodo(ctx, userID, todoID, versions, loc)
+}
+
+func (t *todos) ReopenTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("ReopenTodo", &err)
+	return t.next.ReopenTodo(ctx, userID, todoID, versions, loc)
+}
+
+func (t *todos) GetSubtasks(ctx context.Context, userID int32, todoID int, loc *time.Location) (subtasks dto.SubtasksDto, err error) {
+	defer t.observe("GetSubtasks", &err)
+	return t.next.GetSubtasks(ctx, userID, todoID, loc)
+}
+
+func (t *todos) CreateSubtask(ctx context.Context, userID int32, parentID int, todoInput dto.TodoInputDto, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("CreateSubtask", &err)
+	return t.next.CreateSubtask(ctx, userID, parentID, todoInput, loc)
+}
+
+func (t *todos) SetTodoParent(ctx context.Context, userID int32, todoID int, todoParent dto.TodoParentDto, versions []int32, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("SetTodoParent", &err)
+	return t.next.SetTodoParent(ctx, userID, todoID, todoParent, versions, loc)
+}
+
+func (t *todos) AddTodoBlocker(ctx context.Context, userID int32, todoID int, todoBlocker dto.TodoBlockerDto, loc *time.Location) (todo dto.TodoResponseDto, err error) {
+	defer t.observe("AddTodoBlocker", &err)
+	return t.next.AddTodoBlocker(ctx, userID, todoID, todoBlocker, loc)
+}
+
+func (t *todos) RemoveTodoBlocker(ctx context.Context, userID int32, todoID int, blockerID int) (err error) {
+	defer t.observe("RemoveTodoBlocker", &err)
+	return t.next.RemoveTodoBlocker(ctx, userID, todoID, blockerID)
+}
+
+func (t *todos) GetTodosOrder(ctx context.Context, userID int32, projectID int, loc *time.Location) (order dto.TodosOrderDto, err error) {
+	defer t.observe("GetTodosOrder", &err)
+	return t.next.GetTodosOrder(ctx, userID, projectID, loc)
+}
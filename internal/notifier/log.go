@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log writes reminders to the application log, which is useful in development and as a fallback channel.
+type Log struct {
+	logger *slog.Logger
+}
+
+// NewLog creates a notifier writing reminders to logger.
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{
+		logger: logger,
+	}
+}
+
+// Notify writes msg to the log, it never fails.
+func (l *Log) Notify(ctx context.Context, msg Message) error {
+	l.logger.InfoContext(ctx, "todo reminder",
+		"reminder_id", msg.ReminderID,
+		"todo_id", msg.TodoID,
+		"title", msg.Title,
+		"due_date", msg.DueDate,
+	)
+
+	return nil
+}
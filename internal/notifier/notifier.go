@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a reminder about a todo that is due soon.
+// Recipient is where the reminder goes: an email address for SMTP or a URL for Webhook, Log ignores it.
+// Secret is the key Webhook signs the request with, requests are not signed without it.
+type Message struct {
+	ReminderID int32     `json:"reminder_id"`
+	TodoID     int32     `json:"todo_id"`
+	Title      string    `json:"title"`
+	DueDate    time.Time `json:"due_date"`
+	Recipient  string    `json:"-"`
+	Secret     string    `json:"-"`
+}
+
+// Notifier delivers reminders through a notification channel.
+// Notify returns once the message has been handed over to the channel, or with an error when it hasn't,
+// so the caller can retry it later. Delivery is aborted once ctx is done.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
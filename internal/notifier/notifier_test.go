@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"to-do-list-go/internal/outbound"
+)
+
+var testMessage = Message{
+	ReminderID: 3,
+	TodoID:     1,
+	Title:      "Pay invoice\r\nBcc: victim@example.com",
+	DueDate:    time.Date(2024, 9, 5, 9, 0, 0, 0, time.UTC),
+}
+
+// smtpMail is a message received by fakeSMTPServer.
+type smtpMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer serves a single SMTP session on a local port and sends the message received in it to the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan smtpMail) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	received := make(chan smtpMail, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		c := textproto.NewConn(conn)
+		var mail smtpMail
+		c.PrintfLine("220 localhost fake SMTP")
+		for {
+			line, err := c.ReadLine()
+			if err != nil {
+				return
+			}
+
+			cmd, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(cmd) {
+			case "EHLO", "HELO":
+				c.PrintfLine("250 localhost")
+			case "MAIL":
+				mail.from = arg
+				c.PrintfLine("250 OK")
+			case "RCPT":
+				mail.to = append(mail.to, arg)
+				c.PrintfLine("250 OK")
+			case "DATA":
+				c.PrintfLine("354 Go ahead")
+				data, err := c.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				mail.data = string(data)
+				c.PrintfLine("250 OK")
+			case "QUIT":
+				c.PrintfLine("221 Bye")
+				received <- mail
+				return
+			default:
+				c.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	return l.Addr().String(), received
+}
+
+func TestSMTP(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	n := NewSMTP(SMTPConfig{Addr: addr, From: "Todos <todos@example.com>"})
+
+	msg := testMessage
+	msg.Recipient = "user@example.com"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, n.Notify(ctx, msg))
+
+	mail := <-received
+	require.Equal(t, "FROM:<todos@example.com>", mail.from)
+	require.Equal(t, []string{"TO:<user@example.com>"}, mail.to)
+	require.Contains(t, mail.data, "To: <user@example.com>\n")
+	require.Contains(t, mail.data, "Subject: =?utf-8?q?Reminder:_Pay_invoice")
+	require.NotContains(t, mail.data, "\nBcc:")
+	require.Contains(t, mail.data, "is due at 2024-09-05T09:00:00Z.")
+}
+
+func TestSMTPInvalidRecipient(t *testing.T) {
+	n := NewSMTP(SMTPConfig{Addr: "127.0.0.1:1", From: "todos@example.com"})
+
+	msg := testMessage
+	msg.Recipient = "not an address"
+	require.Error(t, n.Notify(context.Background(), msg))
+}
+
+func TestWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		status      int
+		expectedErr bool
+	}{
+		{name: "Success", status: http.StatusNoContent},
+		{name: "Signed", secret: "whsec_secret", status: http.StatusNoContent},
+		{name: "Error Status", status: http.StatusInternalServerError, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				data, _ := io.ReadAll(r.Body)
+				require.NoError(t, json.Unmarshal(data, &body))
+				if tt.secret == "" {
+					require.Empty(t, r.Header.Get(outbound.SignatureHeader))
+				} else {
+					timestamp, err := strconv.ParseInt(r.Header.Get(outbound.TimestampHeader), 10, 64)
+					require.NoError(t, err)
+					require.Equal(t, outbound.Sign(tt.secret, timestamp, data), r.Header.Get(outbound.SignatureHeader))
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			msg := testMessage
+			msg.Recipient = srv.URL
+			msg.Secret = tt.secret
+			err := NewWebhook(srv.Client()).Notify(context.Background(), msg)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			require.Equal(t, map[string]interface{}{
+				"reminder_id": float64(3),
+				"todo_id":     float64(1),
+				"title":       testMessage.Title,
+				"due_date":    "2024-09-05T09:00:00Z",
+			}, body)
+		})
+	}
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	n := NewLog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	require.NoError(t, n.Notify(context.Background(), testMessage))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "todo reminder", record["msg"])
+	require.Equal(t, float64(1), record["todo_id"])
+	require.Equal(t, testMessage.Title, record["title"])
+}
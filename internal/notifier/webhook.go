@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"to-do-list-go/internal/outbound"
+)
+
+// Webhook posts reminders as JSON to URLs chosen by users.
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook creates a notifier posting reminders with client, see outbound.NewClient.
+func NewWebhook(client *http.Client) *Webhook {
+	return &Webhook{
+		client: client,
+	}
+}
+
+// Notify posts msg to the URL in msg.Recipient, signed with msg.Secret when it is set.
+// Any response status other than 2xx is reported as an error.
+func (h *Webhook) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.Recipient, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if msg.Secret != "" {
+		outbound.SetSignature(req, msg.Secret, time.Now().Unix(), body)
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
+)
+
+// Defines scopes of API keys. Access tokens of logged in users carry all scopes.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+const (
+	apiKeyPrefix       = "tdl_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+// Defines errors returned by APIKeyService.
+var (
+	ErrAPIKeyNotFound      = domain.NewError(domain.ErrNotFound, "api key with this id not found")
+	ErrInvalidAPIKey       = domain.NewError(domain.ErrUnauthorized, "invalid, expired or revoked api key")
+	ErrInvalidAPIKeyExpiry = domain.NewError(domain.ErrValidation, "api key expiration must be in the future")
+)
+
+// APIKeyService handles personal API keys users authenticate scripts with.
+type APIKeyService struct {
+	repo database.Repository
+}
+
+func newAPIKeyService(repo database.Repository) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+	}
+}
+
+// IsAPIKey reports whether a bearer token looks like an API key rather than an access token.
+func IsAPIKey(token string) bool {
+	return len(token) > apiKeyPrefixLength && token[:len(apiKeyPrefix)] == apiKeyPrefix
+}
+
+// CreateAPIKey generates a new API key of the user. Only its hash is stored, so the key is returned just this once.
+func (a APIKeyService) CreateAPIKey(ctx context.Context, userID int32, apiKeyInput dto.APIKeyInputDto, loc *time.Location) (dto.APIKeyCreatedDto, error) {
+	var expiresAt sql.NullTime
+	if apiKeyInput.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *apiKeyInput.ExpiresAt)
+		if err != nil {
+			return dto.APIKeyCreatedDto{}, err
+		}
+
+		if !t.After(time.Now()) {
+			return dto.APIKeyCreatedDto{}, fmt.Errorf("%w: %s", ErrInvalidAPIKeyExpiry, t)
+		}
+		expiresAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	secret, err := newRandomToken()
+	if err != nil {
+		return dto.APIKeyCreatedDto{}, err
+	}
+	key := apiKeyPrefix + secret
+
+	scopes := slices.Clone(apiKeyInput.Scopes)
+	slices.Sort(scopes)
+
+	apiKey, err := a.repo.CreateAPIKey(ctx, database.CreateAPIKeyParams{
+		UserID:    userID,
+		Name:      apiKeyInput.Name,
+		Prefix:    key[:apiKeyPrefixLength],
+		KeyHash:   hashToken(key),
+		Scopes:    slices.Compact(scopes),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return dto.APIKeyCreatedDto{}, err
+	}
+	logger.FromContext(ctx).Info("api key created", "api_key_id", apiKey.ID, "scopes", apiKey.Scopes)
+
+	return dto.APIKeyCreatedDto{
+		APIKeyResponseDto: makeAPIKeyResponseDto(apiKey, loc),
+		Key:               key,
+	}, nil
+}
+
+// ListAPIKeys returns the API keys of the user that haven't been revoked.
+func (a APIKeyService) ListAPIKeys(ctx context.Context, userID int32, loc *time.Location) (dto.APIKeysDto, error) {
+	apiKeys, err := a.repo.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return dto.APIKeysDto{}, err
+	}
+
+	items := make([]dto.APIKeyResponseDto, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		items[i] = makeAPIKeyResponseDto(apiKey, loc)
+	}
+
+	return dto.APIKeysDto{Items: items}, nil
+}
+
+// RevokeAPIKey revokes an API key of the user, so it can't authenticate requests anymore.
+func (a APIKeyService) RevokeAPIKey(ctx context.Context, userID int32, apiKeyID int) error {
+	_, err := a.repo.RevokeAPIKey(ctx, database.RevokeAPIKeyParams{
+		ID:     int32(apiKeyID),
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %w", ErrAPIKeyNotFound, err)
+		}
+
+		return err
+	}
+	logger.FromContext(ctx).Info("api key revoked", "api_key_id", apiKeyID)
+
+	return nil
+}
+
+// AuthenticateAPIKey checks that an API key is active, records its use and returns the id of its user and its scopes.
+// The use is recorded at most once a minute per key, so authenticated requests don't each write to the database.
+func (a APIKeyService) AuthenticateAPIKey(ctx context.Context, key string) (int32, []string, error) {
+	apiKey, err := a.repo.UseAPIKey(ctx, hashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil, fmt.Errorf("%w: %w", ErrInvalidAPIKey, err)
+		}
+
+		return 0, nil, err
+	}
+
+	return apiKey.UserID, apiKey.Scopes, nil
+}
+
+func makeAPIKeyResponseDto(apiKey database.APIKey, loc *time.Location) dto.APIKeyResponseDto {
+	return dto.APIKeyResponseDto{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  formatNullTime(apiKey.ExpiresAt, loc),
+		LastUsedAt: formatNullTime(apiKey.LastUsedAt, loc),
+		CreatedAt:  formatTime(apiKey.CreatedAt, loc),
+	}
+}
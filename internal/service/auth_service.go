@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
+)
+
+const (
+	tokenType          = "Bearer"
+	randomTokenBytes   = 32
+	uniqueViolationErr = "23505"
+)
+
+// Defines errors returned by AuthService.
+var (
+	ErrEmailTaken          = domain.NewError(domain.ErrConflict, "user with this email already exists")
+	ErrInvalidCredentials  = domain.NewError(domain.ErrUnauthorized, "invalid email or password")
+	ErrInvalidRefreshToken = domain.NewError(domain.ErrUnauthorized, "invalid or expired refresh token")
+	ErrInvalidAccessToken  = domain.NewError(domain.ErrUnauthorized, "invalid or expired access token")
+)
+
+// AuthConfig holds the secret access tokens are signed with and the lifetimes of issued tokens.
+type AuthConfig struct {
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// AuthService handles user registration and issues and verifies their tokens.
+type AuthService struct {
+	repo database.Repository
+	cfg  AuthConfig
+}
+
+func newAuthService(repo database.Repository, cfg AuthConfig) *AuthService {
+	return &AuthService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Register creates a new user with a bcrypt hash of the password.
+func (a AuthService) Register(ctx context.Context, credentials dto.CredentialsDto) (dto.UserResponseDto, error) {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(credentials.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return dto.UserResponseDto{}, err
+	}
+
+	user, err := a.repo.CreateUser(ctx, database.CreateUserParams{
+		Email:        normalizeEmail(credentials.Email),
+		PasswordHash: string(passwordHash),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolationErr {
+			return dto.UserResponseDto{}, fmt.Errorf("%w: %w", ErrEmailTaken, err)
+		}
+
+		return dto.UserResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("user registered", "user_id", user.ID)
+
+	return dto.UserResponseDto{
+		ID:        user.ID,
+		Email:     user.Email,
+		CreatedAt: formatTime(user.CreatedAt, nil),
+	}, nil
+}
+
+// Login checks the credentials of a user and issues a new pair of tokens.
+func (a AuthService) Login(ctx context.Context, credentials dto.CredentialsDto) (dto.TokensDto, error) {
+	user, err := a.repo.GetUserByEmail(ctx, normalizeEmail(credentials.Email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.TokensDto{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
+
+		return dto.TokensDto{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)); err != nil {
+		return dto.TokensDto{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	return a.issueTokens(ctx, user.ID)
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens. Each refresh token can be used only once.
+func (a AuthService) Refresh(ctx context.Context, refreshToken string) (dto.TokensDto, error) {
+	token, err := a.repo.ConsumeRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.TokensDto{}, fmt.Errorf("%w: %w", ErrInvalidRefreshToken, err)
+		}
+
+		return dto.TokensDto{}, err
+	}
+
+	return a.issueTokens(ctx, token.UserID)
+}
+
+// ParseAccessToken verifies the signature and expiration of an access token and returns the id of its user.
+func (a AuthService) ParseAccessToken(accessToken string) (int32, error) {
+	claims := jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(accessToken, &claims, func(*jwt.Token) (interface{}, error) {
+		return a.cfg.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidAccessToken, err)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 32)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("%w: invalid subject %q", ErrInvalidAccessToken, claims.Subject)
+	}
+
+	return int32(userID), nil
+}
+
+func (a AuthService) issueTokens(ctx context.Context, userID int32) (dto.TokensDto, error) {
+	now := time.Now()
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.Itoa(int(userID)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(a.cfg.AccessTokenTTL)),
+	}).SignedString(a.cfg.Secret)
+	if err != nil {
+		return dto.TokensDto{}, err
+	}
+
+	refreshToken, err := newRandomToken()
+	if err != nil {
+		return dto.TokensDto{}, err
+	}
+
+	_, err = a.repo.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(a.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return dto.TokensDto{}, err
+	}
+
+	return dto.TokensDto{
+		AccessToken:  accessToken,
+		TokenType:    tokenType,
+		ExpiresIn:    int64(a.cfg.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// newRandomToken generates an opaque random token.
+func newRandomToken() (string, error) {
+	b := make([]byte, randomTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hash a token is stored by, so a leaked database doesn't reveal usable tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(email)
+}
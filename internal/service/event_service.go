@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/logger"
+)
+
+const (
+	// eventsPageSize limits the number of events returned by a single ListEvents call.
+	eventsPageSize = 100
+	// eventsBufferSize is the number of events a subscriber may fall behind by before it is dropped.
+	eventsBufferSize = 64
+)
+
+// ErrEventsExpired is returned by ListEvents when the event to list the events after is no longer in the event log,
+// so some of the events after it may have been deleted as well.
+var ErrEventsExpired = errors.New("event is no longer in the event log")
+
+// EventService streams the events of todos to their users as soon as they are committed.
+// Events are saved to the outbox, which serves as the event log streams are resumed from,
+// and announced by the database on commit, so subscribers on every replica receive the events saved by any of them.
+// Event IDs are the outbox positions, which grow in the order the events of a user are committed,
+// so an event committed after a stream was resumed never has an ID below the events sent before it.
+// Subscribers falling behind are dropped, and so are all of them when announcements may have been lost;
+// they resume from the event log with the ID of the last event they received.
+type EventService struct {
+	repo database.Repository
+
+	mu     sync.Mutex
+	subs   map[int32]map[chan dto.TodoEventDto]struct{}
+	closed bool
+}
+
+func newEventService(repo database.Repository) *EventService {
+	return &EventService{
+		repo: repo,
+		subs: make(map[int32]map[chan dto.TodoEventDto]struct{}),
+	}
+}
+
+// ListEvents returns the first eventsPageSize events of the user committed after the event with afterID, oldest first,
+// or all of them when afterID is 0. Events are kept in the log for outboxRetention after they are published,
+// ErrEventsExpired is returned once the event with afterID is gone.
+func (s *EventService) ListEvents(ctx context.Context, userID int32, afterID int64) ([]dto.TodoEventDto, error) {
+	rows, err := s.repo.ListTodoEvents(ctx, database.ListTodoEventsParams{
+		UserID:       userID,
+		FromPosition: afterID,
+		RowLimit:     eventsPageSize + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if afterID > 0 {
+		if len(rows) == 0 || rows[0].Position.Int64 != afterID {
+			return nil, fmt.Errorf("%w: %d", ErrEventsExpired, afterID)
+		}
+		rows = rows[1:]
+	}
+	if len(rows) > eventsPageSize {
+		rows = rows[:eventsPageSize]
+	}
+
+	events := make([]dto.TodoEventDto, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, makeTodoEventDto(row))
+	}
+
+	return events, nil
+}
+
+// LastEventID returns the ID of the latest event of the user in the event log, or 0 when there are none.
+func (s *EventService) LastEventID(ctx context.Context, userID int32) (int64, error) {
+	return s.repo.GetLastTodoEventPosition(ctx, userID)
+}
+
+// SubscribeEvents returns a channel receiving the events of the user committed from now on and a function ending the subscription.
+// The channel is closed when the subscriber is dropped or the service is closed.
+func (s *EventService) SubscribeEvents(userID int32) (<-chan dto.TodoEventDto, func()) {
+	ch := make(chan dto.TodoEventDto, eventsBufferSize)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if s.subs[userID] == nil {
+		s.subs[userID] = make(map[chan dto.TodoEventDto]struct{})
+	}
+	s.subs[userID][ch] = struct{}{}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.drop(userID, ch)
+	}
+}
+
+// HandleNotification sends the event announced with payload, its ID, to the subscribers of its user.
+// An empty payload means announcements may have been lost, so all subscribers are dropped.
+func (s *EventService) HandleNotification(ctx context.Context, payload string) {
+	if payload == "" {
+		logger.FromContext(ctx).Warn("todo event announcements may have been lost, dropping subscribers")
+		s.dropAll()
+		return
+	}
+
+	id, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		logger.FromContext(ctx).Error("invalid todo event announcement", "payload", payload, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	idle := len(s.subs) == 0
+	s.mu.Unlock()
+	if idle {
+		return
+	}
+
+	row, err := s.repo.GetOutboxEvent(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to load todo event, dropping subscribers", "event_id", id, "error", err)
+		s.dropAll()
+		return
+	}
+	event := makeTodoEventDto(row)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subs[row.UserID] {
+		select {
+		case ch <- event:
+		default:
+			logger.FromContext(ctx).Info("todo event subscriber fell behind, dropping it", "user_id", row.UserID, "event_id", id)
+			s.drop(row.UserID, ch)
+		}
+	}
+}
+
+// Close drops all subscribers and closes the channels of new ones right away, so event streams end when the server shuts down.
+func (s *EventService) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	s.dropAll()
+}
+
+func (s *EventService) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, subs := range s.subs {
+		for ch := range subs {
+			s.drop(userID, ch)
+		}
+	}
+}
+
+// drop ends a subscription unless it has already ended, s.mu must be held.
+func (s *EventService) drop(userID int32, ch chan dto.TodoEventDto) {
+	if _, ok := s.subs[userID][ch]; !ok {
+		return
+	}
+
+	delete(s.subs[userID], ch)
+	if len(s.subs[userID]) == 0 {
+		delete(s.subs, userID)
+	}
+	close(ch)
+}
+
+func makeTodoEventDto(row database.Outbox) dto.TodoEventDto {
+	return dto.TodoEventDto{
+		ID:    row.Position.Int64,
+		Event: row.Event,
+		Data:  row.Payload,
+	}
+}
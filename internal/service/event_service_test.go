@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+// drain returns the events buffered in ch and whether it is still open.
+func drain(ch <-chan dto.TodoEventDto) ([]int64, bool) {
+	var ids []int64
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return ids, false
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids, true
+		}
+	}
+}
+
+func TestEventService(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	repo := mock_repo.NewMockRepository(ctl)
+	repo.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int64) (database.Outbox, error) {
+		if id < 0 {
+			return database.Outbox{}, errors.New("db error")
+		}
+		return database.Outbox{ID: id, Event: WebhookEventTodoUpdated, UserID: int32(id % 2), Payload: json.RawMessage(`{}`), Position: sql.NullInt64{Int64: id, Valid: true}}, nil
+	}).AnyTimes()
+	s := newEventService(repo)
+	ctx := context.Background()
+
+	// Events go to the subscribers of their user only, and subscribers falling behind are dropped.
+	odd, unsubscribeOdd := s.SubscribeEvents(1)
+	even, unsubscribeEven := s.SubscribeEvents(0)
+	defer unsubscribeEven()
+	s.HandleNotification(ctx, "1")
+	s.HandleNotification(ctx, "2")
+	s.HandleNotification(ctx, "invalid")
+	ids, open := drain(odd)
+	require.Equal(t, []int64{1}, ids)
+	require.True(t, open)
+	ids, open = drain(even)
+	require.Equal(t, []int64{2}, ids)
+	require.True(t, open)
+
+	for id := 1; id <= 2*(eventsBufferSize+1); id += 2 {
+		s.HandleNotification(ctx, strconv.Itoa(id))
+	}
+	ids, open = drain(odd)
+	require.Len(t, ids, eventsBufferSize)
+	require.False(t, open)
+	unsubscribeOdd()
+
+	// Lost announcements and failed loads drop every subscriber.
+	for _, payload := range []string{"", "-1"} {
+		ch, unsubscribe := s.SubscribeEvents(1)
+		s.HandleNotification(ctx, payload)
+		_, open = drain(ch)
+		require.False(t, open)
+		unsubscribe()
+	}
+	_, open = drain(even)
+	require.False(t, open)
+
+	// Closing ends current and future subscriptions.
+	ch, unsubscribe := s.SubscribeEvents(1)
+	defer unsubscribe()
+	s.Close()
+	_, open = drain(ch)
+	require.False(t, open)
+	ch, _ = s.SubscribeEvents(1)
+	_, open = drain(ch)
+	require.False(t, open)
+}
+
+func TestListEvents(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	outbox := func(positions ...int64) []database.Outbox {
+		rows := make([]database.Outbox, len(positions))
+		for i, position := range positions {
+			rows[i] = database.Outbox{ID: position + 100, Event: WebhookEventTodoUpdated, UserID: 1, Payload: json.RawMessage(`{}`), Position: sql.NullInt64{Int64: position, Valid: true}}
+		}
+		return rows
+	}
+	page := make([]int64, eventsPageSize+1)
+	for i := range page {
+		page[i] = int64(i + 1)
+	}
+
+	tests := []struct {
+		name         string
+		afterID      int64
+		expectedIDs  []int64
+		expectedErr  error
+		mockBehavior mockBehavior
+	}{
+		{
+			name:        "After Event",
+			afterID:     4,
+			expectedIDs: []int64{5, 7},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodoEvents(gomock.Any(), database.ListTodoEventsParams{UserID: 1, FromPosition: 4, RowLimit: eventsPageSize + 1}).
+					Return(outbox(4, 5, 7), nil).Times(1)
+			},
+		},
+		{
+			name:        "From Start",
+			expectedIDs: page[:eventsPageSize],
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodoEvents(gomock.Any(), database.ListTodoEventsParams{UserID: 1, RowLimit: eventsPageSize + 1}).
+					Return(outbox(page...), nil).Times(1)
+			},
+		},
+		{
+			name:        "Deleted Event",
+			afterID:     4,
+			expectedErr: ErrEventsExpired,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodoEvents(gomock.Any(), gomock.Any()).Return(outbox(5, 7), nil).Times(1)
+			},
+		},
+		{
+			name:        "Deleted Last Event",
+			afterID:     4,
+			expectedErr: ErrEventsExpired,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListTodoEvents(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			events, err := newEventService(repo).ListEvents(context.Background(), 1, tt.afterID)
+
+			require.ErrorIs(t, err, tt.expectedErr)
+			var ids []int64
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			require.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+const (
+	readinessReady        = "ready"
+	readinessNotReady     = "not_ready"
+	readinessShuttingDown = "shutting_down"
+
+	databaseUp   = "up"
+	databaseDown = "down"
+)
+
+var errShuttingDown = errors.New("application is shutting down")
+
+// HealthService checks whether the application and its database are able to serve requests.
+type HealthService struct {
+	repo         database.Repository
+	pool         database.Pool
+	shuttingDown atomic.Bool
+}
+
+func newHealthService(repo database.Repository, pool database.Pool) *HealthService {
+	return &HealthService{
+		repo: repo,
+		pool: pool,
+	}
+}
+
+// Readiness pings the database and reports the latest applied migration and the connection pool statistics.
+// It returns an error when the application should not receive traffic.
+func (h *HealthService) Readiness(ctx context.Context) (dto.ReadinessDto, error) {
+	stats := h.pool.Stats()
+	readiness := dto.ReadinessDto{
+		Status:   readinessReady,
+		Database: databaseUp,
+		Pool: dto.DBPoolStatsDto{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		},
+	}
+
+	if err := h.pool.PingContext(ctx); err != nil {
+		readiness.Status = readinessNotReady
+		readiness.Database = databaseDown
+		return readiness, err
+	}
+
+	version, err := h.repo.MigrationVersion(ctx)
+	if err != nil {
+		readiness.Status = readinessNotReady
+		return readiness, err
+	}
+	readiness.MigrationVersion = &version
+
+	if h.shuttingDown.Load() {
+		readiness.Status = readinessShuttingDown
+		return readiness, errShuttingDown
+	}
+
+	return readiness, nil
+}
+
+// SetShuttingDown makes the application report itself as not ready for the rest of its life.
+func (h *HealthService) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
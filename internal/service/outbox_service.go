@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/events"
+	"to-do-list-go/internal/logger"
+)
+
+const (
+	// outboxBatchSize limits the number of events claimed by a single RelayEvents call.
+	outboxBatchSize = 100
+	// outboxPublishTimeout limits the time the publisher may take to accept a batch of events.
+	outboxPublishTimeout = 10 * time.Second
+	// outboxRetention is how long sent events are kept in the outbox before RelayEvents deletes them.
+	outboxRetention = 24 * time.Hour
+)
+
+// publishTodoEvent saves event about a todo of the user to the outbox and queues its deliveries to the webhooks subscribed to it.
+// It is called in the transaction changing the todo, so the event is published exactly when the change is committed.
+func publishTodoEvent(ctx context.Context, repo database.Repository, userID int32, event string, todo dto.TodoResponseDto) error {
+	payload, err := json.Marshal(dto.WebhookEventDto{
+		Event:      event,
+		OccurredAt: formatTime(time.Now(), time.UTC),
+		Data:       todo,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", event, err)
+	}
+
+	if err := repo.InsertOutboxEvent(ctx, database.InsertOutboxEventParams{
+		Event:   event,
+		UserID:  userID,
+		TodoID:  int32(todo.ID),
+		Payload: payload,
+	}); err != nil {
+		return err
+	}
+
+	return repo.EnqueueWebhookDeliveries(ctx, database.EnqueueWebhookDeliveriesParams{
+		Event:   event,
+		Payload: payload,
+		UserID:  userID,
+	})
+}
+
+// OutboxRelay publishes the events saved to the outbox along with the changes of todos.
+// Events are claimed in a transaction marking them as sent once the publisher has accepted them,
+// so relays of several replicas can share the database and every event is marked as sent exactly once.
+// An event is published again only when that transaction fails to commit, so consumers should use event IDs to drop duplicates.
+type OutboxRelay struct {
+	repo      database.Repository
+	publisher events.EventPublisher
+}
+
+// NewOutboxRelay creates a relay publishing events through publisher.
+func NewOutboxRelay(repo database.Repository, publisher events.EventPublisher) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+// RelayEvents publishes the pending events in the order they were saved and returns the number of published ones.
+// Publishing stops at the first event the publisher rejects, which is retried by the next call along with the events after it.
+// Sent events older than outboxRetention are deleted.
+// The claimed events stay locked while they are published, at most outboxPublishTimeout: it is what keeps
+// other replicas from publishing them too and an event from being marked as sent before the publisher accepted it.
+func (r OutboxRelay) RelayEvents(ctx context.Context, now time.Time) (int, error) {
+	var sent int
+	err := r.repo.InTx(ctx, func(repo database.Repository) error {
+		rows, err := repo.ClaimOutboxEvents(ctx, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+
+		publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+		defer cancel()
+
+		ids := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			if err := r.publisher.Publish(publishCtx, makeEvent(row)); err != nil {
+				logger.FromContext(ctx).Error("failed to publish event", "event_id", row.ID, "event", row.Event, "error", err)
+				break
+			}
+			logger.FromContext(ctx).Debug("event published", "event_id", row.ID, "event", row.Event, "todo_id", row.TodoID)
+			ids = append(ids, row.ID)
+		}
+
+		if len(ids) > 0 {
+			if err := repo.MarkOutboxEventsSent(ctx, ids); err != nil {
+				return err
+			}
+		}
+		sent = len(ids)
+
+		return repo.DeleteSentOutboxEvents(ctx, now.Add(-outboxRetention))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return sent, nil
+}
+
+func makeEvent(row database.Outbox) events.Event {
+	return events.Event{
+		ID:         row.ID,
+		Type:       row.Event,
+		UserID:     row.UserID,
+		TodoID:     row.TodoID,
+		Payload:    row.Payload,
+		OccurredAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/events"
+)
+
+// fakePublisher records the events it accepts and rejects the event with ID rejectID.
+type fakePublisher struct {
+	published []events.Event
+	rejectID  int64
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event events.Event) error {
+	if event.ID == p.rejectID {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event)
+
+	return nil
+}
+
+func TestRelayEvents(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	now := time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC)
+	outbox := func(id int64) database.Outbox {
+		return database.Outbox{
+			ID:        id,
+			Event:     WebhookEventTodoCreated,
+			UserID:    1,
+			TodoID:    int32(id),
+			Payload:   json.RawMessage(`{"event":"todo.created"}`),
+			CreatedAt: now,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		rejectID          int64
+		expectedSent      int
+		expectedErr       bool
+		expectedPublished []int64
+		mockBehavior      mockBehavior
+	}{
+		{
+			name:              "Publishes Events In Order",
+			expectedSent:      2,
+			expectedPublished: []int64{4, 5},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimOutboxEvents(gomock.Any(), int32(outboxBatchSize)).Return([]database.Outbox{outbox(4), outbox(5)}, nil).Times(1)
+				repo.EXPECT().MarkOutboxEventsSent(gomock.Any(), []int64{4, 5}).Return(nil).Times(1)
+				repo.EXPECT().DeleteSentOutboxEvents(gomock.Any(), now.Add(-outboxRetention)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:              "Stops At Rejected Event",
+			rejectID:          5,
+			expectedSent:      1,
+			expectedPublished: []int64{4},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return([]database.Outbox{outbox(4), outbox(5), outbox(6)}, nil).Times(1)
+				repo.EXPECT().MarkOutboxEventsSent(gomock.Any(), []int64{4}).Return(nil).Times(1)
+				repo.EXPECT().DeleteSentOutboxEvents(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Empty Outbox",
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				repo.EXPECT().DeleteSentOutboxEvents(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:              "Mark Error",
+			expectedErr:       true,
+			expectedPublished: []int64{4},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return([]database.Outbox{outbox(4)}, nil).Times(1)
+				repo.EXPECT().MarkOutboxEventsSent(gomock.Any(), gomock.Any()).Return(errors.New("db error")).Times(1)
+			},
+		},
+		{
+			name:        "Repo Error",
+			expectedErr: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			repo.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(database.Repository) error) error {
+				return fn(repo)
+			}).Times(1)
+			tt.mockBehavior(repo)
+
+			publisher := &fakePublisher{rejectID: tt.rejectID}
+			sent, err := NewOutboxRelay(repo, publisher).RelayEvents(context.Background(), now)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			require.Equal(t, tt.expectedSent, sent)
+			var published []int64
+			for _, event := range publisher.published {
+				require.Equal(t, outbox(event.ID).Payload, event.Payload)
+				require.Equal(t, WebhookEventTodoCreated, event.Type)
+				published = append(published, event.ID)
+			}
+			require.Equal(t, tt.expectedPublished, published)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
+)
+
+const (
+	defaultProjectColor = "#808080"
+
+	deleteProjectTodos = "delete"
+	moveProjectTodos   = "move"
+)
+
+// Defines errors returned by ProjectService.
+var (
+	ErrProjectNotFound      = domain.NewError(domain.ErrNotFound, "project with this id not found")
+	ErrInvalidTargetProject = domain.NewError(domain.ErrValidation, "todos can't be moved to the project being deleted")
+)
+
+// ProjectService handles projects-related business logic.
+type ProjectService struct {
+	repo  database.Repository
+	todos *TodoService
+}
+
+func newProjectService(repo database.Repository, todos *TodoService) *ProjectService {
+	return &ProjectService{
+		repo:  repo,
+		todos: todos,
+	}
+}
+
+// CreateProject creates a new project of the user.
+func (p ProjectService) CreateProject(ctx context.Context, userID int32, projectInput dto.ProjectInputDto, loc *time.Location) (dto.ProjectResponseDto, error) {
+	project, err := p.repo.CreateProject(ctx, database.CreateProjectParams{
+		OwnerID:   userID,
+		Name:      projectInput.Name,
+		Color:     projectColor(projectInput.Color),
+		Archived:  projectInput.Archived,
+		SortOrder: projectInput.SortOrder,
+	})
+	if err != nil {
+		return dto.ProjectResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("project created", "project_id", project.ID)
+
+	return makeProjectResponseDto(project, loc), nil
+}
+
+// GetProjects returns the projects of the user, skipping archived ones unless includeArchived is set.
+func (p ProjectService) GetProjects(ctx context.Context, userID int32, includeArchived bool, loc *time.Location) (dto.ProjectsDto, error) {
+	projects, err := p.repo.ListProjects(ctx, database.ListProjectsParams{
+		OwnerID:         userID,
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		return dto.ProjectsDto{}, err
+	}
+
+	items := make([]dto.ProjectResponseDto, len(projects))
+	for i, project := range projects {
+		items[i] = makeProjectResponseDto(project, loc)
+	}
+
+	return dto.ProjectsDto{Items: items}, nil
+}
+
+// GetProject returns a single project of the user by ID.
+func (p ProjectService) GetProject(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.ProjectResponseDto, error) {
+	project, err := p.repo.GetProject(ctx, database.GetProjectParams{
+		ID:      int32(projectID),
+		OwnerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ProjectResponseDto{}, fmt.Errorf("%w: %w", ErrProjectNotFound, err)
+		}
+
+		return dto.ProjectResponseDto{}, err
+	}
+
+	return makeProjectResponseDto(project, loc), nil
+}
+
+// UpdateProject replaces the name, color, archived flag and sort order of an existing project.
+func (p ProjectService) UpdateProject(ctx context.Context, userID int32, projectID int, projectInput dto.ProjectInputDto, loc *time.Location) (dto.ProjectResponseDto, error) {
+	project, err := p.repo.UpdateProject(ctx, database.UpdateProjectParams{
+		ID:        int32(projectID),
+		OwnerID:   userID,
+		Name:      projectInput.Name,
+		Color:     projectColor(projectInput.Color),
+		Archived:  projectInput.Archived,
+		SortOrder: projectInput.SortOrder,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ProjectResponseDto{}, fmt.Errorf("%w: %w", ErrProjectNotFound, err)
+		}
+
+		return dto.ProjectResponseDto{}, err
+	}
+
+	return makeProjectResponseDto(project, loc), nil
+}
+
+// DeleteProject deletes a project of the user together with its todos, or moves them to the target project
+// or out of any project first, as the query asks. The todos, the project and their events change in a single transaction.
+func (p ProjectService) DeleteProject(ctx context.Context, userID int32, projectID int, deleteQuery dto.ProjectDeleteQueryDto) error {
+	switch deleteQuery.Todos {
+	case deleteProjectTodos:
+	case moveProjectTodos:
+		if deleteQuery.TargetProjectID != nil {
+			if int(*deleteQuery.TargetProjectID) == projectID {
+				return ErrInvalidTargetProject
+			}
+
+			if err := checkProject(ctx, p.repo, userID, *deleteQuery.TargetProjectID); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown todos mode %q", deleteQuery.Todos)
+	}
+
+	err := p.todos.inTx(ctx, func(tx TodoService) error {
+		var (
+			todos []database.Todo
+			err   error
+		)
+		if deleteQuery.Todos == deleteProjectTodos {
+			todos, err = tx.repo.DeleteProjectTodos(ctx, database.DeleteProjectTodosParams{
+				ProjectID: int32(projectID),
+				OwnerID:   userID,
+			})
+		} else {
+			todos, err = tx.repo.MoveProjectTodos(ctx, database.MoveProjectTodosParams{
+				ProjectID:       int32(projectID),
+				OwnerID:         userID,
+				TargetProjectID: toNullInt32(deleteQuery.TargetProjectID),
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.repo.DeleteProject(ctx, database.DeleteProjectParams{
+			ID:      int32(projectID),
+			OwnerID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %w", ErrProjectNotFound, err)
+			}
+
+			return err
+		}
+
+		if deleteQuery.Todos == deleteProjectTodos {
+			return tx.publishDeletedTodos(ctx, userID, todos)
+		}
+
+		for _, todo := range todos {
+			if _, err := tx.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, todo, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("project deleted", "project_id", projectID, "todos", deleteQuery.Todos)
+
+	return nil
+}
+
+// GetProjectTodos returns a page of todos of an existing project, see Todos.GetTodos.
+func (p ProjectService) GetProjectTodos(ctx context.Context, userID int32, projectID int, todosQuery dto.TodosQueryDto, loc *time.Location) (dto.TodosPageDto, error) {
+	if err := checkProject(ctx, p.repo, userID, int32(projectID)); err != nil {
+		return dto.TodosPageDto{}, err
+	}
+
+	id := int32(projectID)
+	todosQuery.ProjectID = &id
+
+	return p.todos.GetTodos(ctx, userID, todosQuery, loc)
+}
+
+// GetProjectTodosOrder returns all todos of an existing project in the order they can be done in, see Todos.GetTodosOrder.
+func (p ProjectService) GetProjectTodosOrder(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.TodosOrderDto, error) {
+	if err := checkProject(ctx, p.repo, userID, int32(projectID)); err != nil {
+		return dto.TodosOrderDto{}, err
+	}
+
+	return p.todos.GetTodosOrder(ctx, userID, projectID, loc)
+}
+
+// checkProject makes sure the project exists and belongs to the user before todos are put into it.
+func checkProject(ctx context.Context, repo database.Repository, userID, projectID int32) error {
+	_, err := repo.GetProject(ctx, database.GetProjectParams{
+		ID:      projectID,
+		OwnerID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %w", ErrProjectNotFound, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func projectColor(color string) string {
+	if color == "" {
+		return defaultProjectColor
+	}
+
+	return color
+}
+
+func makeProjectResponseDto(project database.Project, loc *time.Location) dto.ProjectResponseDto {
+	return dto.ProjectResponseDto{
+		ID:        project.ID,
+		Name:      project.Name,
+		Color:     project.Color,
+		Archived:  project.Archived,
+		SortOrder: project.SortOrder,
+		CreatedAt: formatTime(project.CreatedAt, loc),
+		UpdatedAt: formatTime(project.UpdatedAt, loc),
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/teambition/rrule-go"
+	"strings"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/logger"
+)
+
+// recurrenceBatchSize limits the number of recurring todos handled by a single GenerateOccurrences call.
+const recurrenceBatchSize = 100
+
+// RecurrenceService generates the next occurrences of recurring todos ahead of their due dates.
+type RecurrenceService struct {
+	repo  database.Repository
+	todos *TodoService
+}
+
+func newRecurrenceService(repo database.Repository, todos *TodoService) *RecurrenceService {
+	return &RecurrenceService{
+		repo:  repo,
+		todos: todos,
+	}
+}
+
+// GenerateOccurrences creates the next occurrence of the recurring todos due before dueBefore that don't have one yet
+// and returns the number of created occurrences. Cancelled todos end their series, so they are skipped until reopened.
+// At most recurrenceBatchSize todos are handled per call, the rest are left to the following calls.
+// Each occurrence is created in its own transaction, so a failed one is logged and skipped.
+func (r RecurrenceService) GenerateOccurrences(ctx context.Context, dueBefore time.Time) (int, error) {
+	todos, err := r.repo.ListDueRecurringTodos(ctx, database.ListDueRecurringTodosParams{
+		DueBefore: dueBefore,
+		RowLimit:  recurrenceBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var created int
+	for _, todo := range todos {
+		var ok bool
+		err := r.todos.inTx(ctx, func(tx TodoService) error {
+			var err error
+			_, ok, err = tx.createNextOccurrence(ctx, todo.OwnerID, todo)
+			return err
+		})
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to create next todo occurrence", "todo_id", todo.ID, "error", err)
+			continue
+		}
+
+		if ok {
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// recur creates the next occurrence of a todo that has just been completed when it repeats and hasn't been followed yet,
+// and returns the todo with the series the occurrence belongs to.
+func (t TodoService) recur(ctx context.Context, userID int32, todo database.Todo) (database.Todo, error) {
+	if !todo.Recurrence.Valid || todo.Recurred {
+		return todo, nil
+	}
+
+	next, ok, err := t.createNextOccurrence(ctx, userID, todo)
+	if err != nil {
+		return database.Todo{}, err
+	}
+
+	if ok {
+		todo.SeriesID = next.SeriesID
+	}
+
+	return todo, nil
+}
+
+// createNextOccurrence creates the occurrence following a recurring todo, copying its title, description, project, parent and tags,
+// or ends the series when the rule has no occurrences left.
+// The new occurrence is published as todo.created and its parent is touched, since the progress of the parent counts it.
+// Each occurrence is followed at most once, so it reports false when another call has already created the next one.
+func (t TodoService) createNextOccurrence(ctx context.Context, userID int32, todo database.Todo) (database.Todo, bool, error) {
+	dueDate, recurrence, ok, err := nextOccurrence(todo.Recurrence.String, todo.RecurrenceTimezone.String, todo.DueDate)
+	if err != nil {
+		logger.FromContext(ctx).Error("invalid todo recurrence", "todo_id", todo.ID, "recurrence", todo.Recurrence.String,
+			"timezone", todo.RecurrenceTimezone.String, "error", err)
+	}
+
+	if !ok {
+		if err := t.repo.EndTodoRecurrence(ctx, todo.ID); err != nil {
+			return database.Todo{}, false, err
+		}
+		logger.FromContext(ctx).Info("todo recurrence ended", "todo_id", todo.ID)
+
+		return database.Todo{}, false, nil
+	}
+
+	row, err := t.repo.CreateNextOccurrence(ctx, database.CreateNextOccurrenceParams{
+		ID:         todo.ID,
+		DueDate:    dueDate,
+		Recurrence: recurrence,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Todo{}, false, nil
+		}
+
+		return database.Todo{}, false, err
+	}
+	next := database.Todo(row)
+	logger.FromContext(ctx).Info("todo occurrence created", "todo_id", next.ID, "series_id", next.SeriesID.Int32, "due_date", next.DueDate)
+
+	if _, err := t.publishTodoChange(ctx, userID, WebhookEventTodoCreated, next, nil); err != nil {
+		return database.Todo{}, false, err
+	}
+
+	if next.ParentID.Valid {
+		if _, err := t.touchTodo(ctx, userID, next.ParentID.Int32, nil); err != nil {
+			return database.Todo{}, false, err
+		}
+	}
+
+	return next, true, nil
+}
+
+// nextOccurrence returns the due date of the occurrence following the one due at dueDate, along with the rule it repeats by,
+// or false when the rule has no occurrences left.
+// Rules are evaluated in the IANA time zone tz, or in UTC when it is empty, so a daily todo keeps its local time across DST changes.
+// COUNT is the number of occurrences left including the current one, so the next occurrence repeats by a rule with one less.
+func nextOccurrence(rule, tz string, dueDate time.Time) (time.Time, string, bool, error) {
+	option, err := parseRecurrence(rule)
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+
+	count := option.Count
+	if count == 1 {
+		return time.Time{}, "", false, nil
+	}
+
+	option.Count = 0
+	option.Dtstart = dueDate.In(loc)
+	r, err := rrule.NewRRule(*option)
+	if err != nil {
+		return time.Time{}, "", false, err
+	}
+
+	next := r.After(dueDate, false)
+	if next.IsZero() {
+		return time.Time{}, "", false, nil
+	}
+
+	if count > 1 {
+		option.Count = count - 1
+	}
+	option.Dtstart = time.Time{}
+
+	return next, option.RRuleString(), true, nil
+}
+
+// recurrenceTimezone returns the time zone a recurrence is evaluated in: the one requested when the rule was set, or none for UTC.
+func recurrenceTimezone(recurrence sql.NullString, loc *time.Location) sql.NullString {
+	if !recurrence.Valid || loc == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: loc.String(), Valid: true}
+}
+
+// normalizeRecurrence renders a recurrence rule the way it is stored, without the "RRULE:" prefix and with the parts in a fixed order.
+func normalizeRecurrence(rule *string) (sql.NullString, error) {
+	if rule == nil {
+		return sql.NullString{}, nil
+	}
+
+	option, err := parseRecurrence(*rule)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: option.RRuleString(), Valid: true}, nil
+}
+
+func parseRecurrence(rule string) (*rrule.ROption, error) {
+	return rrule.StrToROption(strings.ToUpper(rule))
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+)
+
+func TestGenerateOccurrences(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	now := time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC)
+	dueBefore := now.Add(24 * time.Hour)
+	recurring := func(id int32, dueDate time.Time, recurrence string) database.Todo {
+		return database.Todo{
+			ID:         id,
+			DueDate:    dueDate,
+			OwnerID:    7,
+			Recurrence: sql.NullString{String: recurrence, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		expectedCreated int
+		expectedErr     bool
+		mockBehavior    mockBehavior
+	}{
+		{
+			name:            "Creates Next Occurrences",
+			expectedCreated: 2,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListDueRecurringTodos(gomock.Any(), database.ListDueRecurringTodosParams{
+					DueBefore: dueBefore,
+					RowLimit:  recurrenceBatchSize,
+				}).Return([]database.Todo{
+					recurring(1, now, "FREQ=DAILY"),
+					recurring(2, now.Add(time.Hour), "FREQ=WEEKLY;INTERVAL=2;COUNT=5"),
+					recurring(3, now.Add(2*time.Hour), "FREQ=YEARLY;COUNT=1"),
+				}, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), database.CreateNextOccurrenceParams{
+					ID:         1,
+					DueDate:    now.AddDate(0, 0, 1),
+					Recurrence: "FREQ=DAILY",
+				}).Return(database.CreateNextOccurrenceRow{ID: 4, SeriesID: sql.NullInt32{Int32: 1, Valid: true}}, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), database.CreateNextOccurrenceParams{
+					ID:         2,
+					DueDate:    now.Add(time.Hour).AddDate(0, 0, 14),
+					Recurrence: "FREQ=WEEKLY;INTERVAL=2;COUNT=4",
+				}).Return(database.CreateNextOccurrenceRow{ID: 5, SeriesID: sql.NullInt32{Int32: 2, Valid: true}}, nil).Times(1)
+				repo.EXPECT().EndTodoRecurrence(gomock.Any(), int32(3)).Return(nil).Times(1)
+				for _, todoID := range []int32{4, 5} {
+					repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, arg database.InsertOutboxEventParams) error {
+						require.Equal(t, "todo.created", arg.Event)
+						require.Equal(t, int32(7), arg.UserID)
+						require.Equal(t, todoID, arg.TodoID)
+						return nil
+					}).Times(1)
+				}
+			},
+		},
+		{
+			name:            "Evaluates Rules In Series Timezone",
+			expectedCreated: 1,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				// 09:00 in Berlin is 07:00 UTC in summer time and 08:00 UTC after the clocks go back on October 27.
+				summer := recurring(1, time.Date(2024, 10, 26, 7, 0, 0, 0, time.UTC), "FREQ=DAILY;BYHOUR=9")
+				summer.RecurrenceTimezone = sql.NullString{String: "Europe/Berlin", Valid: true}
+				repo.EXPECT().ListDueRecurringTodos(gomock.Any(), gomock.Any()).Return([]database.Todo{summer}, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.CreateNextOccurrenceParams) (database.CreateNextOccurrenceRow, error) {
+					require.True(t, time.Date(2024, 10, 27, 8, 0, 0, 0, time.UTC).Equal(arg.DueDate))
+					require.Equal(t, "FREQ=DAILY;BYHOUR=9", arg.Recurrence)
+					return database.CreateNextOccurrenceRow{ID: 4, OwnerID: 7}, nil
+				}).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:            "Touches Parents Of Occurrences",
+			expectedCreated: 1,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				subtask := recurring(1, now, "FREQ=DAILY")
+				subtask.ParentID = sql.NullInt32{Int32: 9, Valid: true}
+				repo.EXPECT().ListDueRecurringTodos(gomock.Any(), gomock.Any()).Return([]database.Todo{subtask}, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), gomock.Any()).Return(database.CreateNextOccurrenceRow{ID: 4, OwnerID: 7, ParentID: subtask.ParentID}, nil).Times(1)
+				repo.EXPECT().TouchTodo(gomock.Any(), database.TouchTodoParams{ID: 9, OwnerID: 7}).Return(database.Todo{ID: 9, OwnerID: 7}, nil).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+		},
+		{
+			name:            "Skips Failed Occurrences",
+			expectedCreated: 0,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListDueRecurringTodos(gomock.Any(), gomock.Any()).Return([]database.Todo{
+					recurring(1, now, "FREQ=DAILY"),
+					recurring(2, now, "FREQ=DAILY"),
+				}, nil).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), gomock.Any()).Return(database.CreateNextOccurrenceRow{}, errors.New("db error")).Times(1)
+				repo.EXPECT().CreateNextOccurrence(gomock.Any(), gomock.Any()).Return(database.CreateNextOccurrenceRow{}, sql.ErrNoRows).Times(1)
+				repo.EXPECT().InsertOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:        "Repo Error",
+			expectedErr: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ListDueRecurringTodos(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+			repo.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(database.Repository) error) error {
+				return fn(repo)
+			}).AnyTimes()
+			// Occurrences have no tags, subtasks and blockers, and their events are queued for no webhooks.
+			repo.EXPECT().ListTodoTags(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			repo.EXPECT().ListSubtaskProgress(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			repo.EXPECT().ListTodoBlockers(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			repo.EXPECT().EnqueueWebhookDeliveries(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			created, err := newRecurrenceService(repo, newTodoService(repo, TodoConfig{})).GenerateOccurrences(context.Background(), dueBefore)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			require.Equal(t, tt.expectedCreated, created)
+		})
+	}
+}
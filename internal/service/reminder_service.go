@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/notifier"
+	"to-do-list-go/internal/outbound"
+)
+
+// Defines the channels reminders are sent through.
+const (
+	ReminderChannelEmail   = string(database.ReminderChannelEmail)
+	ReminderChannelWebhook = string(database.ReminderChannelWebhook)
+	ReminderChannelLog     = string(database.ReminderChannelLog)
+)
+
+const (
+	// reminderBatchSize limits the number of reminders claimed by a single SendDueReminders call.
+	reminderBatchSize = 20
+	// reminderNotifyTimeout limits the time a notifier may take to deliver a reminder.
+	reminderNotifyTimeout = 10 * time.Second
+	// reminderLease is how long claimed reminders stay hidden from other schedulers,
+	// it must be longer than the time needed to deliver a whole batch.
+	reminderLease = 5 * time.Minute
+	// reminderMaxAttempts is the number of delivery attempts after which a reminder is marked as failed.
+	reminderMaxAttempts = 5
+	// reminderRetryDelay is the delay before the second delivery attempt, it doubles with every further attempt.
+	reminderRetryDelay = time.Minute
+)
+
+// Defines errors returned by ReminderService.
+var (
+	ErrReminderNotFound      = domain.NewError(domain.ErrNotFound, "reminder with this id not found")
+	ErrInvalidReminderTime   = domain.NewError(domain.ErrValidation, "reminder time must be in the future")
+	ErrInvalidReminderTarget = domain.NewError(domain.ErrValidation, "reminder target must be an http or https URL of a public host")
+
+	errNotifierNotConfigured = errors.New("reminder channel is not configured")
+)
+
+// ReminderService handles reminders about todos of a user.
+type ReminderService struct {
+	repo database.Repository
+}
+
+func newReminderService(repo database.Repository) *ReminderService {
+	return &ReminderService{
+		repo: repo,
+	}
+}
+
+// CreateReminder adds a reminder to an existingTodo.
+// The secret webhook reminders are signed with is generated here and returned just this once.
+func (s ReminderService) CreateReminder(ctx context.Context, userID int32, todoID int, reminderInput dto.ReminderInputDto, loc *time.Location) (dto.ReminderCreatedDto, error) {
+	var remindAt sql.NullTime
+	if reminderInput.RemindAt != nil {
+		t, err := time.Parse(time.RFC3339, *reminderInput.RemindAt)
+		if err != nil {
+			return dto.ReminderCreatedDto{}, err
+		}
+
+		if !t.After(time.Now()) {
+			return dto.ReminderCreatedDto{}, fmt.Errorf("%w: %s", ErrInvalidReminderTime, t)
+		}
+		remindAt = sql.NullTime{Time: t, Valid: true}
+	}
+
+	var secret *string
+	if reminderInput.Channel == ReminderChannelWebhook && reminderInput.Target != nil {
+		if err := outbound.CheckURL(*reminderInput.Target); err != nil {
+			return dto.ReminderCreatedDto{}, fmt.Errorf("%w: %w", ErrInvalidReminderTarget, err)
+		}
+
+		token, err := newRandomToken()
+		if err != nil {
+			return dto.ReminderCreatedDto{}, err
+		}
+		webhookSecret := webhookSecretPrefix + token
+		secret = &webhookSecret
+	}
+
+	reminder, err := s.repo.CreateReminder(ctx, database.CreateReminderParams{
+		RemindAt:      remindAt,
+		MinutesBefore: toNullInt32(reminderInput.MinutesBefore),
+		Channel:       database.ReminderChannel(reminderInput.Channel),
+		Target:        toNullString(reminderInput.Target),
+		Secret:        toNullString(secret),
+		TodoID:        int32(todoID),
+		OwnerID:       userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.ReminderCreatedDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+		}
+
+		return dto.ReminderCreatedDto{}, err
+	}
+	logger.FromContext(ctx).Info("reminder created", "reminder_id", reminder.ID, "todo_id", reminder.TodoID, "channel", reminder.Channel)
+
+	return dto.ReminderCreatedDto{
+		ReminderResponseDto: makeReminderResponseDto(reminder, loc),
+		Secret:              secret,
+	}, nil
+}
+
+// ListReminders returns the reminders of an existingTodo, including the sent and failed ones.
+func (s ReminderService) ListReminders(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.RemindersDto, error) {
+	if _, err := s.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.RemindersDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+		}
+
+		return dto.RemindersDto{}, err
+	}
+
+	reminders, err := s.repo.ListReminders(ctx, database.ListRemindersParams{TodoID: int32(todoID), OwnerID: userID})
+	if err != nil {
+		return dto.RemindersDto{}, err
+	}
+
+	items := make([]dto.ReminderResponseDto, len(reminders))
+	for i, reminder := range reminders {
+		items[i] = makeReminderResponseDto(reminder, loc)
+	}
+
+	return dto.RemindersDto{Items: items}, nil
+}
+
+// DeleteReminder removes a reminder from an existingTodo.
+func (s ReminderService) DeleteReminder(ctx context.Context, userID int32, todoID int, reminderID int) error {
+	if _, err := s.repo.DeleteReminder(ctx, database.DeleteReminderParams{
+		ID:      int32(reminderID),
+		TodoID:  int32(todoID),
+		OwnerID: userID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %w", ErrReminderNotFound, err)
+		}
+
+		return err
+	}
+	logger.FromContext(ctx).Info("reminder deleted", "reminder_id", reminderID, "todo_id", todoID)
+
+	return nil
+}
+
+func makeReminderResponseDto(reminder database.Reminder, loc *time.Location) dto.ReminderResponseDto {
+	status := "pending"
+	if reminder.SentAt.Valid {
+		status = "sent"
+	} else if reminder.FailedAt.Valid {
+		status = "failed"
+	}
+
+	return dto.ReminderResponseDto{
+		ID:            reminder.ID,
+		TodoID:        reminder.TodoID,
+		RemindAt:      formatNullTime(reminder.RemindAt, loc),
+		MinutesBefore: fromNullInt32(reminder.MinutesBefore),
+		Channel:       string(reminder.Channel),
+		Target:        fromNullString(reminder.Target),
+		Status:        status,
+		Attempts:      reminder.Attempts,
+		LastError:     fromNullString(reminder.LastError),
+		SentAt:        formatNullTime(reminder.SentAt, loc),
+		CreatedAt:     formatTime(reminder.CreatedAt, loc),
+	}
+}
+
+// ReminderScheduler sends the reminders that are due through the notifiers of their channels.
+// Reminders are claimed before they are sent, so schedulers of several replicas can share the database without sending a reminder twice,
+// unless a replica stops after sending a reminder and before recording it.
+type ReminderScheduler struct {
+	repo      database.Repository
+	notifiers map[string]notifier.Notifier
+}
+
+// NewReminderScheduler creates a scheduler sending reminders through notifiers keyed by channel.
+// Reminders of channels without a notifier are marked as failed.
+func NewReminderScheduler(repo database.Repository, notifiers map[string]notifier.Notifier) *ReminderScheduler {
+	return &ReminderScheduler{
+		repo:      repo,
+		notifiers: notifiers,
+	}
+}
+
+// SendDueReminders sends the reminders of open and in progress todos that are due at now and returns the number of sent reminders.
+// Failed deliveries are retried with an exponential backoff until reminderMaxAttempts is reached.
+func (s ReminderScheduler) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	reminders, err := s.repo.ClaimDueReminders(ctx, database.ClaimDueRemindersParams{
+		Now:         now,
+		LockedUntil: now.Add(reminderLease),
+		RowLimit:    reminderBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var sent int
+	for _, reminder := range reminders {
+		if err := s.notify(ctx, reminder); err != nil {
+			s.recordFailure(ctx, reminder, now, err)
+			continue
+		}
+
+		if err := s.repo.MarkReminderSent(ctx, reminder.ID); err != nil {
+			logger.FromContext(ctx).Error("failed to mark reminder as sent", "reminder_id", reminder.ID, "error", err)
+		}
+		logger.FromContext(ctx).Info("reminder sent", "reminder_id", reminder.ID, "todo_id", reminder.TodoID, "channel", reminder.Channel)
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (s ReminderScheduler) notify(ctx context.Context, reminder database.ClaimDueRemindersRow) error {
+	n, ok := s.notifiers[string(reminder.Channel)]
+	if !ok {
+		return fmt.Errorf("%w: %s", errNotifierNotConfigured, reminder.Channel)
+	}
+
+	recipient := reminder.Target.String
+	if reminder.Channel == database.ReminderChannelEmail {
+		recipient = reminder.Email
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reminderNotifyTimeout)
+	defer cancel()
+
+	return n.Notify(ctx, notifier.Message{
+		ReminderID: reminder.ID,
+		TodoID:     reminder.TodoID,
+		Title:      reminder.Title,
+		DueDate:    reminder.DueDate,
+		Recipient:  recipient,
+		Secret:     reminder.Secret.String,
+	})
+}
+
+// recordFailure schedules the next delivery attempt of a reminder, or marks it as failed when no attempts are left.
+func (s ReminderScheduler) recordFailure(ctx context.Context, reminder database.ClaimDueRemindersRow, now time.Time, err error) {
+	var retryAt sql.NullTime
+	if reminder.Attempts < reminderMaxAttempts && !errors.Is(err, errNotifierNotConfigured) {
+		retryAt = sql.NullTime{Time: now.Add(reminderRetryDelay << (reminder.Attempts - 1)), Valid: true}
+	}
+	logger.FromContext(ctx).Warn("failed to send reminder", "reminder_id", reminder.ID, "attempts", reminder.Attempts, "retry", retryAt.Valid, "error", err)
+
+	if err := s.repo.RecordReminderFailure(ctx, database.RecordReminderFailureParams{
+		ID:        reminder.ID,
+		LastError: err.Error(),
+		RetryAt:   retryAt,
+	}); err != nil {
+		logger.FromContext(ctx).Error("failed to record reminder failure", "reminder_id", reminder.ID, "error", err)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/notifier"
+)
+
+// fakeNotifier records the messages it is asked to deliver and fails with err.
+type fakeNotifier struct {
+	messages []notifier.Message
+	err      error
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, msg notifier.Message) error {
+	n.messages = append(n.messages, msg)
+	return n.err
+}
+
+func TestSendDueReminders(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository)
+
+	now := time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC)
+	dueDate := now.Add(30 * time.Minute)
+	reminder := func(id int32, channel database.ReminderChannel, target string, attempts int32) database.ClaimDueRemindersRow {
+		return database.ClaimDueRemindersRow{
+			ID:       id,
+			TodoID:   1,
+			Channel:  channel,
+			Target:   sql.NullString{String: target, Valid: target != ""},
+			Attempts: attempts,
+			Title:    "Pay invoice",
+			DueDate:  dueDate,
+			Email:    "user@example.com",
+		}
+	}
+
+	tests := []struct {
+		name             string
+		notifierErr      error
+		withoutEmail     bool
+		expectedSent     int
+		expectedErr      bool
+		expectedMessages []notifier.Message
+		mockBehavior     mockBehavior
+	}{
+		{
+			name:         "Sends Reminders",
+			expectedSent: 2,
+			expectedMessages: []notifier.Message{
+				{ReminderID: 1, TodoID: 1, Title: "Pay invoice", DueDate: dueDate, Recipient: "user@example.com"},
+				{ReminderID: 2, TodoID: 1, Title: "Pay invoice", DueDate: dueDate, Recipient: "user@example.com"},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimDueReminders(gomock.Any(), database.ClaimDueRemindersParams{
+					Now:         now,
+					LockedUntil: now.Add(reminderLease),
+					RowLimit:    reminderBatchSize,
+				}).Return([]database.ClaimDueRemindersRow{
+					reminder(1, database.ReminderChannelEmail, "", 1),
+					reminder(2, database.ReminderChannelEmail, "boss@example.com", 1),
+				}, nil).Times(1)
+				repo.EXPECT().MarkReminderSent(gomock.Any(), int32(1)).Return(nil).Times(1)
+				repo.EXPECT().MarkReminderSent(gomock.Any(), int32(2)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:        "Retries Failed Reminders",
+			notifierErr: errors.New("connection refused"),
+			expectedMessages: []notifier.Message{
+				{ReminderID: 1, TodoID: 1, Title: "Pay invoice", DueDate: dueDate, Recipient: "user@example.com"},
+				{ReminderID: 2, TodoID: 1, Title: "Pay invoice", DueDate: dueDate, Recipient: "user@example.com"},
+			},
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimDueReminders(gomock.Any(), gomock.Any()).Return([]database.ClaimDueRemindersRow{
+					reminder(1, database.ReminderChannelEmail, "", 3),
+					reminder(2, database.ReminderChannelEmail, "", reminderMaxAttempts),
+				}, nil).Times(1)
+				repo.EXPECT().RecordReminderFailure(gomock.Any(), database.RecordReminderFailureParams{
+					ID:        1,
+					LastError: "connection refused",
+					RetryAt:   sql.NullTime{Time: now.Add(4 * reminderRetryDelay), Valid: true},
+				}).Return(nil).Times(1)
+				repo.EXPECT().RecordReminderFailure(gomock.Any(), database.RecordReminderFailureParams{
+					ID:        2,
+					LastError: "connection refused",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:         "Fails Reminders Of Unconfigured Channels",
+			withoutEmail: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimDueReminders(gomock.Any(), gomock.Any()).Return([]database.ClaimDueRemindersRow{
+					reminder(1, database.ReminderChannelEmail, "", 1),
+				}, nil).Times(1)
+				repo.EXPECT().RecordReminderFailure(gomock.Any(), database.RecordReminderFailureParams{
+					ID:        1,
+					LastError: "reminder channel is not configured: email",
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:        "Repo Error",
+			expectedErr: true,
+			mockBehavior: func(repo *mock_repo.MockRepository) {
+				repo.EXPECT().ClaimDueReminders(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo)
+
+			email := &fakeNotifier{err: tt.notifierErr}
+			notifiers := map[string]notifier.Notifier{ReminderChannelEmail: email}
+			if tt.withoutEmail {
+				notifiers = map[string]notifier.Notifier{}
+			}
+
+			sent, err := NewReminderScheduler(repo, notifiers).SendDueReminders(context.Background(), now)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			require.Equal(t, tt.expectedSent, sent)
+			require.Equal(t, tt.expectedMessages, email.messages)
+		})
+	}
+}
@@ -1,29 +1,147 @@
 package service
 
 import (
+	"context"
+	"time"
 	"to-do-list-go/internal/database"
 	"to-do-list-go/internal/delivery/dto"
 )
 
 // Todos defines methods for managing todos operations.
+// Todos are scoped to the user with userID, todos of other users are reported as not found.
+// Timestamps of returned todos are rendered in the given location, or as stored when it is nil.
+// Writes taking versions only apply when the todo version is one of them; an empty list matches any version.
+// Database queries are aborted once ctx is done.
 type Todos interface {
-	CreateTodo(todoInput dto.TodoInputDto) (dto.TodoResponseDto, error)
-	GetTodos() ([]dto.TodoResponseDto, error)
-	GetTodo(todoID int) (dto.TodoResponseDto, error)
-	UpdateTodo(todoID int, todoInput dto.TodoInputDto) (dto.TodoResponseDto, error)
-	DeleteTodo(todoID int) error
+	CreateTodo(ctx context.Context, userID int32, todoInput dto.TodoInputDto, loc *time.Location) (dto.TodoResponseDto, error)
+	GetTodos(ctx context.Context, userID int32, todosQuery dto.TodosQueryDto, loc *time.Location) (dto.TodosPageDto, error)
+	SearchTodos(ctx context.Context, userID int32, searchQuery dto.TodoSearchQueryDto, loc *time.Location) (dto.TodoSearchResultsDto, error)
+	GetTodo(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.TodoResponseDto, error)
+	UpdateTodo(ctx context.Context, userID int32, todoID int, todoInput dto.TodoInputDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	PatchTodo(ctx context.Context, userID int32, todoID int, todoPatch dto.TodoPatchDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	DeleteTodo(ctx context.Context, userID int32, todoID int, versions []int32) error
+	MoveTodo(ctx context.Context, userID int32, todoID int, todoMove dto.TodoMoveDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	GetSubtasks(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.SubtasksDto, error)
+	CreateSubtask(ctx context.Context, userID int32, parentID int, todoInput dto.TodoInputDto, loc *time.Location) (dto.TodoResponseDto, error)
+	SetTodoParent(ctx context.Context, userID int32, todoID int, todoParent dto.TodoParentDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	AddTodoBlocker(ctx context.Context, userID int32, todoID int, todoBlocker dto.TodoBlockerDto, loc *time.Location) (dto.TodoResponseDto, error)
+	RemoveTodoBlocker(ctx context.Context, userID int32, todoID int, blockerID int) error
+	GetTodosOrder(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.TodosOrderDto, error)
+	StartTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	CompleteTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	CancelTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
+	ReopenTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error)
 }
 
-// Service manages todos-related operations through the Todos interface.
+// Projects defines methods for managing projects that group todos of a user.
+type Projects interface {
+	CreateProject(ctx context.Context, userID int32, projectInput dto.ProjectInputDto, loc *time.Location) (dto.ProjectResponseDto, error)
+	GetProjects(ctx context.Context, userID int32, includeArchived bool, loc *time.Location) (dto.ProjectsDto, error)
+	GetProject(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.ProjectResponseDto, error)
+	UpdateProject(ctx context.Context, userID int32, projectID int, projectInput dto.ProjectInputDto, loc *time.Location) (dto.ProjectResponseDto, error)
+	DeleteProject(ctx context.Context, userID int32, projectID int, deleteQuery dto.ProjectDeleteQueryDto) error
+	GetProjectTodos(ctx context.Context, userID int32, projectID int, todosQuery dto.TodosQueryDto, loc *time.Location) (dto.TodosPageDto, error)
+	GetProjectTodosOrder(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.TodosOrderDto, error)
+}
+
+// Tags defines methods for reading the tags todos of a user are labelled with.
+type Tags interface {
+	GetTags(ctx context.Context, userID int32) (dto.TagsDto, error)
+}
+
+// Reminders defines methods for managing reminders about todos of a user.
+// Reminders of todos of other users are reported as not found.
+type Reminders interface {
+	CreateReminder(ctx context.Context, userID int32, todoID int, reminderInput dto.ReminderInputDto, loc *time.Location) (dto.ReminderCreatedDto, error)
+	ListReminders(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.RemindersDto, error)
+	DeleteReminder(ctx context.Context, userID int32, todoID int, reminderID int) error
+}
+
+// Webhooks defines methods for managing webhooks that receive todo events of a user, and for reading their deliveries.
+// Webhooks of other users are reported as not found.
+type Webhooks interface {
+	CreateWebhook(ctx context.Context, userID int32, webhookInput dto.WebhookInputDto, loc *time.Location) (dto.WebhookCreatedDto, error)
+	ListWebhooks(ctx context.Context, userID int32, loc *time.Location) (dto.WebhooksDto, error)
+	GetWebhook(ctx context.Context, userID int32, webhookID int, loc *time.Location) (dto.WebhookResponseDto, error)
+	UpdateWebhook(ctx context.Context, userID int32, webhookID int, webhookInput dto.WebhookInputDto, loc *time.Location) (dto.WebhookResponseDto, error)
+	DeleteWebhook(ctx context.Context, userID int32, webhookID int) error
+	ListWebhookDeliveries(ctx context.Context, userID int32, webhookID int, loc *time.Location) (dto.WebhookDeliveriesDto, error)
+}
+
+// Events defines methods for streaming the events of todos of a user as they are committed on any replica.
+// HandleNotification receives the announcements of committed events and Close ends all streams.
+type Events interface {
+	ListEvents(ctx context.Context, userID int32, afterID int64) ([]dto.TodoEventDto, error)
+	LastEventID(ctx context.Context, userID int32) (int64, error)
+	SubscribeEvents(userID int32) (<-chan dto.TodoEventDto, func())
+	HandleNotification(ctx context.Context, payload string)
+	Close()
+}
+
+// Recurrences defines methods for generating the occurrences of recurring todos of all users.
+type Recurrences interface {
+	GenerateOccurrences(ctx context.Context, dueBefore time.Time) (int, error)
+}
+
+// Health defines methods for reporting whether the application can serve requests.
+type Health interface {
+	Readiness(ctx context.Context) (dto.ReadinessDto, error)
+	SetShuttingDown()
+}
+
+// Auth defines methods for registering users and issuing and verifying their tokens.
+type Auth interface {
+	Register(ctx context.Context, credentials dto.CredentialsDto) (dto.UserResponseDto, error)
+	Login(ctx context.Context, credentials dto.CredentialsDto) (dto.TokensDto, error)
+	Refresh(ctx context.Context, refreshToken string) (dto.TokensDto, error)
+	ParseAccessToken(accessToken string) (int32, error)
+}
+
+// APIKeys defines methods for managing personal API keys and authenticating requests with them.
+type APIKeys interface {
+	CreateAPIKey(ctx context.Context, userID int32, apiKeyInput dto.APIKeyInputDto, loc *time.Location) (dto.APIKeyCreatedDto, error)
+	ListAPIKeys(ctx context.Context, userID int32, loc *time.Location) (dto.APIKeysDto, error)
+	RevokeAPIKey(ctx context.Context, userID int32, apiKeyID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (int32, []string, error)
+}
+
+// Service manages todos, projects, tags, recurrences, reminders, webhooks, event streams, users, API keys and health checks through their interfaces.
 type Service struct {
-	Todos Todos
+	Todos       Todos
+	Projects    Projects
+	Tags        Tags
+	Recurrences Recurrences
+	Reminders   Reminders
+	Webhooks    Webhooks
+	Events      Events
+	Auth        Auth
+	APIKeys     APIKeys
+	Health      Health
 }
 
 // NewService creates a new Service instance.
-func NewService(repo database.Repository) *Service {
-	todoService := newTodoService(repo)
+func NewService(repo database.Repository, pool database.Pool, authCfg AuthConfig, todoCfg TodoConfig) *Service {
+	todoService := newTodoService(repo, todoCfg)
+	projectService := newProjectService(repo, todoService)
+	tagService := newTagService(repo)
+	recurrenceService := newRecurrenceService(repo, todoService)
+	reminderService := newReminderService(repo)
+	webhookService := newWebhookService(repo)
+	eventService := newEventService(repo)
+	authService := newAuthService(repo, authCfg)
+	apiKeyService := newAPIKeyService(repo)
+	healthService := newHealthService(repo, pool)
 
 	return &Service{
-		Todos: todoService,
+		Todos:       todoService,
+		Projects:    projectService,
+		Tags:        tagService,
+		Recurrences: recurrenceService,
+		Reminders:   reminderService,
+		Webhooks:    webhookService,
+		Events:      eventService,
+		Auth:        authService,
+		APIKeys:     apiKeyService,
+		Health:      healthService,
 	}
 }
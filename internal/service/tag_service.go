@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+)
+
+// allTags is the tag mode of todos queries matching todos that have all of the requested tags.
+const allTags = "all"
+
+// TagService handles tags-related business logic.
+type TagService struct {
+	repo database.Repository
+}
+
+func newTagService(repo database.Repository) *TagService {
+	return &TagService{
+		repo: repo,
+	}
+}
+
+// GetTags returns the tags of the user with the number of todos labelled with each of them.
+func (t TagService) GetTags(ctx context.Context, userID int32) (dto.TagsDto, error) {
+	tags, err := t.repo.ListTags(ctx, userID)
+	if err != nil {
+		return dto.TagsDto{}, err
+	}
+
+	items := make([]dto.TagResponseDto, len(tags))
+	for i, tag := range tags {
+		items[i] = dto.TagResponseDto{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			TodoCount: tag.TodoCount,
+		}
+	}
+
+	return dto.TagsDto{Items: items}, nil
+}
+
+// normalizeTags trims and lowercases tag names, so "Bug" and " bug" are the same tag,
+// and returns them sorted without empty names and duplicates.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
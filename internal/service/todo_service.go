@@ -5,129 +5,1078 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"html"
+	"slices"
+	"strings"
 	"time"
 	"to-do-list-go/internal/database"
 	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
 )
 
-const (
-	errTodoNotFound = "todo with this id not found"
+// Defines errors returned by TodoService.
+var (
+	ErrTodoNotFound            = domain.NewError(domain.ErrNotFound, "todo with this id not found")
+	ErrInvalidStatusTransition = domain.NewError(domain.ErrConflict, "todo status transition is not allowed")
+	ErrInvalidCursor           = domain.NewError(domain.ErrValidation, "invalid cursor")
+	ErrTodoModified            = domain.NewError(domain.ErrPreconditionFailed, "todo has been modified")
+	ErrParentTodoNotFound      = domain.NewError(domain.ErrNotFound, "parent todo with this id not found")
+	ErrTodoCycle               = domain.NewError(domain.ErrConflict, "todo can't become a subtask of itself or of its subtasks")
+	ErrBlockerTodoNotFound     = domain.NewError(domain.ErrNotFound, "blocker todo with this id not found")
+	ErrTodoBlockerNotFound     = domain.NewError(domain.ErrNotFound, "todo isn't blocked by this todo")
+	ErrDependencyCycle         = domain.NewError(domain.ErrConflict, "todo can't be blocked by itself or by todos it blocks")
+	ErrTodoBlocked             = domain.NewError(domain.ErrConflict, "todo is blocked by unfinished todos")
 )
 
+// todoStatusTransitions lists the statuses a todo may move to from each status.
+var todoStatusTransitions = map[database.TodoStatus][]database.TodoStatus{
+	database.TodoStatusOpen:       {database.TodoStatusInProgress, database.TodoStatusDone, database.TodoStatusCancelled},
+	database.TodoStatusInProgress: {database.TodoStatusOpen, database.TodoStatusDone, database.TodoStatusCancelled},
+	database.TodoStatusDone:       {database.TodoStatusOpen},
+	database.TodoStatusCancelled:  {database.TodoStatusOpen},
+}
+
+// TodoConfig holds the rules applied to todos with subtasks.
+type TodoConfig struct {
+	// AutoCompleteParents marks a todo as done once none of its subtasks is open or in progress and at least one is done.
+	AutoCompleteParents bool
+}
+
 // TodoService handles todos-related business logic.
 type TodoService struct {
 	repo database.Repository
+	cfg  TodoConfig
 }
 
-func newTodoService(repo database.Repository) *TodoService {
+func newTodoService(repo database.Repository, cfg TodoConfig) *TodoService {
 	return &TodoService{
 		repo: repo,
+		cfg:  cfg,
 	}
 }
 
 // CreateTodo creates a newTodo.
-func (t TodoService) CreateTodo(todoInput dto.TodoInputDto) (dto.TodoResponseDto, error) {
-	newTodo, err := t.repo.CreateTodo(context.Background(), database.CreateTodoParams{
+func (t TodoService) CreateTodo(ctx context.Context, userID int32, todoInput dto.TodoInputDto, loc *time.Location) (dto.TodoResponseDto, error) {
+	return t.createTodo(ctx, userID, todoInput, nil, loc)
+}
+
+// CreateSubtask creates a newTodo as a subtask of an existingTodo.
+// Unless todoInput sets a project, the subtask is created in the project of its parent.
+func (t TodoService) CreateSubtask(ctx context.Context, userID int32, parentID int, todoInput dto.TodoInputDto, loc *time.Location) (dto.TodoResponseDto, error) {
+	parent, err := t.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(parentID), OwnerID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.TodoResponseDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+		}
+
+		return dto.TodoResponseDto{}, err
+	}
+
+	return t.createTodo(ctx, userID, todoInput, &parent, loc)
+}
+
+func (t TodoService) createTodo(ctx context.Context, userID int32, todoInput dto.TodoInputDto, parent *database.Todo, loc *time.Location) (dto.TodoResponseDto, error) {
+	dueDate, err := time.Parse(time.RFC3339, todoInput.DueDate)
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	params := database.CreateTodoParams{
 		Title:       todoInput.Title,
 		Description: todoInput.Description,
-		DueDate:     todoInput.DueDate,
+		DueDate:     dueDate,
+		OwnerID:     userID,
+		ProjectID:   toNullInt32(todoInput.ProjectID),
+	}
+
+	if todoInput.ProjectID != nil {
+		if err := checkProject(ctx, t.repo, userID, *todoInput.ProjectID); err != nil {
+			return dto.TodoResponseDto{}, err
+		}
+	} else if parent != nil {
+		params.ProjectID = parent.ProjectID
+	}
+
+	if parent != nil {
+		params.ParentID = sql.NullInt32{Int32: parent.ID, Valid: true}
+	}
+
+	params.Recurrence, err = normalizeRecurrence(todoInput.Recurrence)
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+	params.RecurrenceTimezone = recurrenceTimezone(params.Recurrence, loc)
+
+	tags := normalizeTags(todoInput.Tags)
+
+	var newTodo database.Todo
+	err = t.inTx(ctx, func(tx TodoService) error {
+		newTodo, err = tx.repo.CreateTodo(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if len(tags) > 0 {
+			if err := tx.setTodoTags(ctx, userID, newTodo.ID, tags); err != nil {
+				return err
+			}
+		}
+
+		if parent != nil {
+			if _, err := tx.touchTodo(ctx, userID, parent.ID, nil); err != nil {
+				return err
+			}
+		}
+
+		return publishTodoEvent(ctx, tx.repo, userID, WebhookEventTodoCreated, tx.makeTodoResponseDto(newTodo, todoDetails{tags: tags}, nil))
 	})
 	if err != nil {
 		return dto.TodoResponseDto{}, err
 	}
+	logger.FromContext(ctx).Info("todo created", "todo_id", newTodo.ID, "parent_id", fromNullInt32(newTodo.ParentID))
 
-	return dto.TodoResponseDto{
-		ID:          newTodo.ID,
-		Title:       newTodo.Title,
-		Description: newTodo.Description,
-		DueDate:     newTodo.DueDate,
-		CreatedAt:   newTodo.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   newTodo.UpdatedAt.Format(time.RFC3339),
+	return t.makeTodoResponseDto(newTodo, todoDetails{tags: tags}, loc), nil
+}
+
+// GetTodos returns a page of todos matching the query filters, ordered by the query sort keys.
+func (t TodoService) GetTodos(ctx context.Context, userID int32, todosQuery dto.TodosQueryDto, loc *time.Location) (dto.TodosPageDto, error) {
+	params := database.ListTodosParams{
+		Statuses:    todosQuery.Statuses,
+		DueFrom:     toNullTime(todosQuery.DueFrom),
+		DueTo:       toNullTime(todosQuery.DueTo),
+		CreatedFrom: toNullTime(todosQuery.CreatedFrom),
+		CreatedTo:   toNullTime(todosQuery.CreatedTo),
+		UpdatedFrom: toNullTime(todosQuery.UpdatedFrom),
+		UpdatedTo:   toNullTime(todosQuery.UpdatedTo),
+		RowLimit:    int32(todosQuery.Limit + 1),
+		OwnerID:     userID,
+		ProjectID:   toNullInt32(todosQuery.ProjectID),
+		Tags:        normalizeTags(todosQuery.Tags),
+		AllTags:     todosQuery.TagMode == allTags,
+	}
+
+	params.SortKey1, params.SortKey1Desc = parseSortKey(todosQuery.Sort[0])
+	if len(todosQuery.Sort) > 1 {
+		params.SortKey2, params.SortKey2Desc = parseSortKey(todosQuery.Sort[1])
+	}
+
+	sort := strings.Join(todosQuery.Sort, ",")
+	if todosQuery.Cursor != "" {
+		cursor, err := decodeTodosCursor(todosQuery.Cursor)
+		if err != nil {
+			return dto.TodosPageDto{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+
+		if cursor.Sort != sort {
+			return dto.TodosPageDto{}, fmt.Errorf("%w: cursor sort %q does not match %q", ErrInvalidCursor, cursor.Sort, sort)
+		}
+
+		params.CursorID = sql.NullInt32{Int32: cursor.ID, Valid: true}
+		params.CursorKey1 = sql.NullTime{Time: cursor.Key1, Valid: true}
+		params.CursorKey2 = toNullTime(cursor.Key2)
+	}
+
+	todos, err := t.repo.ListTodos(ctx, params)
+	if err != nil {
+		return dto.TodosPageDto{}, err
+	}
+
+	var nextCursor *string
+	if len(todos) > todosQuery.Limit {
+		todos = todos[:todosQuery.Limit]
+		last := todos[len(todos)-1]
+
+		cursor := todosCursor{
+			Sort: sort,
+			Key1: sortKeyValue(last, params.SortKey1),
+			ID:   last.ID,
+		}
+		if params.SortKey2 != "" {
+			key2 := sortKeyValue(last, params.SortKey2)
+			cursor.Key2 = &key2
+		}
+
+		encoded, err := cursor.encode()
+		if err != nil {
+			return dto.TodosPageDto{}, err
+		}
+		nextCursor = &encoded
+	}
+
+	items, err := t.makeTodosResponseDto(ctx, todos, loc)
+	if err != nil {
+		return dto.TodosPageDto{}, err
+	}
+
+	return dto.TodosPageDto{
+		Items:      items,
+		NextCursor: nextCursor,
 	}, nil
 }
 
-// GetTodos returns all todos.
-func (t TodoService) GetTodos() ([]dto.TodoResponseDto, error) {
-	todos, err := t.repo.GetTodos(context.Background())
+// SearchTodos returns todos matching a web-search style query, ordered by relevance.
+func (t TodoService) SearchTodos(ctx context.Context, userID int32, searchQuery dto.TodoSearchQueryDto, loc *time.Location) (dto.TodoSearchResultsDto, error) {
+	rows, err := t.repo.SearchTodos(ctx, database.SearchTodosParams{
+		Query:    searchQuery.Query,
+		RowLimit: int32(searchQuery.Limit),
+		OwnerID:  userID,
+	})
+	if err != nil {
+		return dto.TodoSearchResultsDto{}, err
+	}
+
+	todos := make([]database.Todo, len(rows))
+	for i, row := range rows {
+		todos[i] = row.Todo
+	}
+
+	details, err := t.loadTodosDetails(ctx, todos)
 	if err != nil {
-		return nil, err
+		return dto.TodoSearchResultsDto{}, err
 	}
 
-	return t.makeTodosResponseDto(todos), nil
+	results := make([]dto.TodoSearchResultDto, len(rows))
+	for i, row := range rows {
+		results[i] = dto.TodoSearchResultDto{
+			Todo: t.makeTodoResponseDto(row.Todo, details[row.Todo.ID], loc),
+			Rank: row.Rank,
+			Highlights: dto.TodoHighlightsDto{
+				Title:       escapeHighlight(row.TitleHighlight),
+				Description: escapeHighlight(row.DescriptionHighlight),
+			},
+		}
+	}
+
+	return dto.TodoSearchResultsDto{Items: results}, nil
 }
 
 // GetTodo returns a singleTodo by ID.
-func (t TodoService) GetTodo(todoID int) (dto.TodoResponseDto, error) {
-	todo, err := t.repo.GetTodo(context.Background(), int32(todoID))
+func (t TodoService) GetTodo(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.TodoResponseDto, error) {
+	todo, err := t.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return dto.TodoResponseDto{}, fmt.Errorf(errTodoNotFound+": %s\n", err)
+			return dto.TodoResponseDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
 		}
 
 		return dto.TodoResponseDto{}, err
 	}
 
-	return dto.TodoResponseDto{
-		ID:          todo.ID,
-		Title:       todo.Title,
-		Description: todo.Description,
-		DueDate:     todo.DueDate,
-		CreatedAt:   todo.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   todo.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return t.loadTodoResponseDto(ctx, todo, loc)
 }
 
 // UpdateTodo updates an existingTodo by ID.
-func (t TodoService) UpdateTodo(todoID int, todoInput dto.TodoInputDto) (dto.TodoResponseDto, error) {
-	updatedTodo, err := t.repo.UpdateTodo(context.Background(), database.UpdateTodoParams{
-		ID:          int32(todoID),
-		Title:       todoInput.Title,
-		Description: todoInput.Description,
-		DueDate:     todoInput.DueDate,
+func (t TodoService) UpdateTodo(ctx context.Context, userID int32, todoID int, todoInput dto.TodoInputDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	dueDate, err := time.Parse(time.RFC3339, todoInput.DueDate)
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	recurrence, err := normalizeRecurrence(todoInput.Recurrence)
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	tags := normalizeTags(todoInput.Tags)
+
+	var res dto.TodoResponseDto
+	err = t.inTx(ctx, func(tx TodoService) error {
+		updatedTodo, err := tx.repo.UpdateTodo(ctx, database.UpdateTodoParams{
+			ID:                 int32(todoID),
+			Title:              todoInput.Title,
+			Description:        todoInput.Description,
+			DueDate:            dueDate,
+			Recurrence:         recurrence,
+			RecurrenceTimezone: recurrenceTimezone(recurrence, loc),
+			Versions:           versions,
+			OwnerID:            userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return tx.missingTodoError(ctx, userID, int32(todoID), versions, err)
+			}
+
+			return err
+		}
+
+		if err := tx.setTodoTags(ctx, userID, updatedTodo.ID, tags); err != nil {
+			return err
+		}
+
+		res, err = tx.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, updatedTodo, loc)
+		return err
+	})
+
+	return res, err
+}
+
+// PatchTodo changes only the fields of an existingTodo that are set in todoPatch.
+// Patched tags replace all tags of the todo, and a removed recurrence stops the todo from repeating.
+func (t TodoService) PatchTodo(ctx context.Context, userID int32, todoID int, todoPatch dto.TodoPatchDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	params := database.PatchTodoParams{
+		ID:               int32(todoID),
+		Title:            toNullString(todoPatch.Title),
+		Description:      toNullString(todoPatch.Description),
+		RemoveRecurrence: todoPatch.RemoveRecurrence,
+		Versions:         versions,
+		OwnerID:          userID,
+	}
+
+	if todoPatch.DueDate != nil {
+		dueDate, err := time.Parse(time.RFC3339, *todoPatch.DueDate)
+		if err != nil {
+			return dto.TodoResponseDto{}, err
+		}
+		params.DueDate = sql.NullTime{Time: dueDate, Valid: true}
+	}
+
+	recurrence, err := normalizeRecurrence(todoPatch.Recurrence)
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+	params.Recurrence = recurrence
+	params.RecurrenceTimezone = recurrenceTimezone(recurrence, loc)
+
+	var res dto.TodoResponseDto
+	err = t.inTx(ctx, func(tx TodoService) error {
+		patchedTodo, err := tx.repo.PatchTodo(ctx, params)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return tx.missingTodoError(ctx, userID, int32(todoID), versions, err)
+			}
+
+			return err
+		}
+
+		if todoPatch.Tags != nil {
+			if err := tx.setTodoTags(ctx, userID, patchedTodo.ID, normalizeTags(*todoPatch.Tags)); err != nil {
+				return err
+			}
+		}
+
+		res, err = tx.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, patchedTodo, loc)
+		return err
+	})
+
+	return res, err
+}
+
+// DeleteTodo deletes a existingTodo by ID together with its subtasks.
+func (t TodoService) DeleteTodo(ctx context.Context, userID int32, todoID int, versions []int32) error {
+	err := t.inTx(ctx, func(tx TodoService) error {
+		deletedTodos, err := tx.repo.DeleteTodo(ctx, database.DeleteTodoParams{
+			ID:       int32(todoID),
+			Versions: versions,
+			OwnerID:  userID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(deletedTodos) == 0 {
+			return tx.missingTodoError(ctx, userID, int32(todoID), versions, sql.ErrNoRows)
+		}
+
+		for _, deletedTodo := range deletedTodos {
+			if deletedTodo.ID == int32(todoID) && deletedTodo.ParentID.Valid {
+				if _, err := tx.touchTodo(ctx, userID, deletedTodo.ParentID.Int32, nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.publishDeletedTodos(ctx, userID, deletedTodos)
 	})
 	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("todo deleted", "todo_id", todoID)
+
+	return nil
+}
+
+// MoveTodo moves an existingTodo to another project of the user, or out of any project.
+func (t TodoService) MoveTodo(ctx context.Context, userID int32, todoID int, todoMove dto.TodoMoveDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	if todoMove.ProjectID != nil {
+		if err := checkProject(ctx, t.repo, userID, *todoMove.ProjectID); err != nil {
+			return dto.TodoResponseDto{}, err
+		}
+	}
+
+	var res dto.TodoResponseDto
+	err := t.inTx(ctx, func(tx TodoService) error {
+		movedTodo, err := tx.repo.MoveTodo(ctx, database.MoveTodoParams{
+			ID:        int32(todoID),
+			ProjectID: toNullInt32(todoMove.ProjectID),
+			Versions:  versions,
+			OwnerID:   userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return tx.missingTodoError(ctx, userID, int32(todoID), versions, err)
+			}
+
+			return err
+		}
+
+		res, err = tx.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, movedTodo, loc)
+		return err
+	})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("todo moved", "todo_id", todoID, "project_id", todoMove.ProjectID)
+
+	return res, nil
+}
+
+// GetSubtasks returns the tree of subtasks of an existingTodo, with all levels of nesting.
+func (t TodoService) GetSubtasks(ctx context.Context, userID int32, todoID int, loc *time.Location) (dto.SubtasksDto, error) {
+	if _, err := t.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID}); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return dto.TodoResponseDto{}, fmt.Errorf(errTodoNotFound+": %s\n", err)
+			return dto.SubtasksDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+		}
+
+		return dto.SubtasksDto{}, err
+	}
+
+	subtasks, err := t.repo.ListSubtaskTree(ctx, database.ListSubtaskTreeParams{ID: int32(todoID), OwnerID: userID})
+	if err != nil {
+		return dto.SubtasksDto{}, err
+	}
+
+	details, err := t.loadTodosDetails(ctx, subtasks)
+	if err != nil {
+		return dto.SubtasksDto{}, err
+	}
+
+	children := make(map[int32][]database.Todo, len(subtasks))
+	for _, subtask := range subtasks {
+		children[subtask.ParentID.Int32] = append(children[subtask.ParentID.Int32], subtask)
+	}
+
+	var makeNodes func(parentID int32) []dto.TodoNodeDto
+	makeNodes = func(parentID int32) []dto.TodoNodeDto {
+		nodes := make([]dto.TodoNodeDto, len(children[parentID]))
+		for i, subtask := range children[parentID] {
+			nodes[i] = dto.TodoNodeDto{
+				TodoResponseDto: t.makeTodoResponseDto(subtask, details[subtask.ID], loc),
+				Subtasks:        makeNodes(subtask.ID),
+			}
+		}
+		return nodes
+	}
+
+	return dto.SubtasksDto{Items: makeNodes(int32(todoID))}, nil
+}
+
+// SetTodoParent makes an existingTodo a subtask of another todo of the user, or a top-level todo.
+// A todo can't become a subtask of itself or of any of its subtasks.
+func (t TodoService) SetTodoParent(ctx context.Context, userID int32, todoID int, todoParent dto.TodoParentDto, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	var res dto.TodoResponseDto
+	err := t.inTx(ctx, func(tx TodoService) error {
+		// Parents are changed under the lock blockers are added with, one at a time per user,
+		// so that two requests making todos subtasks of each other can't both pass the cycle check.
+		if err := tx.repo.LockTodoDependencies(ctx, userID); err != nil {
+			return err
+		}
+
+		if todoParent.ParentID != nil {
+			ancestors, err := tx.repo.ListTodoAncestors(ctx, database.ListTodoAncestorsParams{ID: *todoParent.ParentID, OwnerID: userID})
+			if err != nil {
+				return err
+			}
+
+			if len(ancestors) == 0 {
+				return fmt.Errorf("%w: %d", ErrParentTodoNotFound, *todoParent.ParentID)
+			}
+
+			if slices.Contains(ancestors, int32(todoID)) {
+				return fmt.Errorf("%w: %d is %d or one of its subtasks", ErrTodoCycle, *todoParent.ParentID, todoID)
+			}
+		}
+
+		todo, err := tx.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+			}
+
+			return err
+		}
+
+		updatedTodo, err := tx.repo.SetTodoParent(ctx, database.SetTodoParentParams{
+			ID:       int32(todoID),
+			ParentID: toNullInt32(todoParent.ParentID),
+			Versions: versions,
+			OwnerID:  userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return tx.missingTodoError(ctx, userID, int32(todoID), versions, err)
+			}
+
+			return err
+		}
+
+		// Both the old and the new parent have their subtasks progress changed.
+		for _, parentID := range []sql.NullInt32{todo.ParentID, updatedTodo.ParentID} {
+			if parentID.Valid && todo.ParentID != updatedTodo.ParentID {
+				if _, err := tx.touchTodo(ctx, userID, parentID.Int32, nil); err != nil {
+					return err
+				}
+			}
 		}
 
+		res, err = tx.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, updatedTodo, loc)
+		return err
+	})
+	if err != nil {
 		return dto.TodoResponseDto{}, err
 	}
+	logger.FromContext(ctx).Info("todo parent changed", "todo_id", todoID, "parent_id", todoParent.ParentID)
 
-	return dto.TodoResponseDto{
-		ID:          updatedTodo.ID,
-		Title:       updatedTodo.Title,
-		Description: updatedTodo.Description,
-		DueDate:     updatedTodo.DueDate,
-		CreatedAt:   updatedTodo.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   updatedTodo.UpdatedAt.Format(time.RFC3339),
-	}, nil
+	return res, nil
 }
 
-// DeleteTodo deletes a existingTodo by ID.
-func (t TodoService) DeleteTodo(todoID int) error {
-	_, err := t.repo.DeleteTodo(context.Background(), int32(todoID))
+// AddTodoBlocker makes an existingTodo blocked by another todo of the user, so it can't be completed before the blocker is finished.
+// A todo can't be blocked by itself or by any todo it blocks directly or through other todos.
+func (t TodoService) AddTodoBlocker(ctx context.Context, userID int32, todoID int, todoBlocker dto.TodoBlockerDto, loc *time.Location) (dto.TodoResponseDto, error) {
+	var res dto.TodoResponseDto
+	err := t.inTx(ctx, func(tx TodoService) error {
+		// The blockers of a user are added one at a time, so that two requests blocking todos by each other
+		// can't both pass the cycle check.
+		if err := tx.repo.LockTodoDependencies(ctx, userID); err != nil {
+			return err
+		}
+
+		todo, err := tx.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+			}
+
+			return err
+		}
+
+		chain, err := tx.repo.ListTodoBlockerChain(ctx, database.ListTodoBlockerChainParams{ID: todoBlocker.BlockerID, OwnerID: userID})
+		if err != nil {
+			return err
+		}
+
+		if len(chain) == 0 {
+			return fmt.Errorf("%w: %d", ErrBlockerTodoNotFound, todoBlocker.BlockerID)
+		}
+
+		if slices.Contains(chain, todo.ID) {
+			return fmt.Errorf("%w: %d is %d or is blocked by it", ErrDependencyCycle, todoBlocker.BlockerID, todo.ID)
+		}
+
+		added, err := tx.repo.AddTodoBlocker(ctx, database.AddTodoBlockerParams{TodoID: todo.ID, BlockerID: todoBlocker.BlockerID})
+		if err != nil {
+			return err
+		}
+
+		if added == 0 {
+			res, err = tx.loadTodoResponseDto(ctx, todo, loc)
+			return err
+		}
+
+		res, err = tx.touchTodo(ctx, userID, todo.ID, loc)
+		return err
+	})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("todo blocker added", "todo_id", todoID, "blocker_id", todoBlocker.BlockerID)
+
+	return res, nil
+}
+
+// RemoveTodoBlocker makes an existingTodo no longer blocked by the todo with blockerID.
+func (t TodoService) RemoveTodoBlocker(ctx context.Context, userID int32, todoID int, blockerID int) error {
+	err := t.inTx(ctx, func(tx TodoService) error {
+		_, err := tx.repo.RemoveTodoBlocker(ctx, database.RemoveTodoBlockerParams{
+			TodoID:    int32(todoID),
+			BlockerID: int32(blockerID),
+			OwnerID:   userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %d isn't blocked by %d", ErrTodoBlockerNotFound, todoID, blockerID)
+			}
+
+			return err
+		}
+
+		_, err = tx.touchTodo(ctx, userID, int32(todoID), nil)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).Info("todo blocker removed", "todo_id", todoID, "blocker_id", blockerID)
+
+	return nil
+}
+
+// GetTodosOrder returns all todos of a project so that each todo comes after the todos of the project blocking it.
+// Todos that don't depend on each other stay ordered by due date.
+func (t TodoService) GetTodosOrder(ctx context.Context, userID int32, projectID int, loc *time.Location) (dto.TodosOrderDto, error) {
+	todos, err := t.repo.ListProjectTodos(ctx, database.ListProjectTodosParams{ProjectID: int32(projectID), OwnerID: userID})
+	if err != nil {
+		return dto.TodosOrderDto{}, err
+	}
+
+	details, err := t.loadTodosDetails(ctx, todos)
+	if err != nil {
+		return dto.TodosOrderDto{}, err
+	}
+
+	ordered, err := orderByDependencies(todos, details)
+	if err != nil {
+		return dto.TodosOrderDto{}, fmt.Errorf("%w: todos of project %d block each other", err, projectID)
+	}
+
+	items := make([]dto.TodoResponseDto, len(ordered))
+	for i, todo := range ordered {
+		items[i] = t.makeTodoResponseDto(todo, details[todo.ID], loc)
+	}
+
+	return dto.TodosOrderDto{Items: items}, nil
+}
+
+// orderByDependencies sorts todos topologically, so that each todo comes after the todos blocking it.
+// Todos that don't depend on each other keep their order in todos, blockers missing from todos are ignored.
+func orderByDependencies(todos []database.Todo, details map[int32]todoDetails) ([]database.Todo, error) {
+	indexes := make(map[int32]int, len(todos))
+	for i, todo := range todos {
+		indexes[todo.ID] = i
+	}
+
+	blocks := make([][]int, len(todos))
+	blockerCounts := make([]int, len(todos))
+	for i, todo := range todos {
+		for _, blockerID := range details[todo.ID].blockedBy {
+			if j, ok := indexes[blockerID]; ok {
+				blocks[j] = append(blocks[j], i)
+				blockerCounts[i]++
+			}
+		}
+	}
+
+	var ready []int
+	for i, count := range blockerCounts {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	ordered := make([]database.Todo, 0, len(todos))
+	for len(ready) > 0 {
+		next := slices.Min(ready)
+		ready = slices.DeleteFunc(ready, func(i int) bool { return i == next })
+		ordered = append(ordered, todos[next])
+
+		for _, i := range blocks[next] {
+			blockerCounts[i]--
+			if blockerCounts[i] == 0 {
+				ready = append(ready, i)
+			}
+		}
+	}
+
+	if len(ordered) < len(todos) {
+		return nil, ErrDependencyCycle
+	}
+
+	return ordered, nil
+}
+
+// StartTodo moves an existingTodo to the in_progress status.
+func (t TodoService) StartTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	return t.changeTodoStatus(ctx, userID, todoID, database.TodoStatusInProgress, versions, loc)
+}
+
+// CompleteTodo marks an existingTodo as done and records the completion time.
+// A todo can't be completed while any of its blockers is open or in progress.
+// Completing a recurring todo creates its next occurrence, unless it has already been created.
+func (t TodoService) CompleteTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	return t.changeTodoStatus(ctx, userID, todoID, database.TodoStatusDone, versions, loc)
+}
+
+// CancelTodo marks an existingTodo as cancelled.
+func (t TodoService) CancelTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	return t.changeTodoStatus(ctx, userID, todoID, database.TodoStatusCancelled, versions, loc)
+}
+
+// ReopenTodo moves a done or cancelledTodo back to the open status.
+func (t TodoService) ReopenTodo(ctx context.Context, userID int32, todoID int, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	return t.changeTodoStatus(ctx, userID, todoID, database.TodoStatusOpen, versions, loc)
+}
+
+func (t TodoService) changeTodoStatus(ctx context.Context, userID int32, todoID int, status database.TodoStatus, versions []int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	todo, err := t.repo.GetTodo(ctx, database.GetTodoParams{ID: int32(todoID), OwnerID: userID})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf(errTodoNotFound+": %s\n", err)
+			return dto.TodoResponseDto{}, fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+		}
+
+		return dto.TodoResponseDto{}, err
+	}
+
+	if len(versions) > 0 && !slices.Contains(versions, todo.Version) {
+		return dto.TodoResponseDto{}, fmt.Errorf("%w: version %d is not one of %v", ErrTodoModified, todo.Version, versions)
+	}
+
+	if !slices.Contains(todoStatusTransitions[todo.Status], status) {
+		return dto.TodoResponseDto{}, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, todo.Status, status)
+	}
+
+	if status == database.TodoStatusDone {
+		if err := t.checkBlockers(ctx, todo.ID); err != nil {
+			return dto.TodoResponseDto{}, err
+		}
+	}
+
+	event := WebhookEventTodoUpdated
+	if status == database.TodoStatusDone {
+		event = WebhookEventTodoCompleted
+	}
+
+	var res dto.TodoResponseDto
+	err = t.inTx(ctx, func(tx TodoService) error {
+		updatedTodo, err := tx.repo.UpdateTodoStatus(ctx, database.UpdateTodoStatusParams{
+			ID:            todo.ID,
+			Status:        status,
+			CurrentStatus: todo.Status,
+			Versions:      versions,
+			OwnerID:       userID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				if len(versions) > 0 {
+					return tx.missingTodoError(ctx, userID, todo.ID, versions, err)
+				}
+
+				return fmt.Errorf("%w: status of todo %d changed concurrently", ErrInvalidStatusTransition, todo.ID)
+			}
+
+			return err
 		}
 
+		if isUnfinished(todo.Status) != isUnfinished(status) {
+			if err := tx.touchRelatedTodos(ctx, userID, updatedTodo); err != nil {
+				return err
+			}
+		}
+
+		if tx.cfg.AutoCompleteParents && (status == database.TodoStatusDone || status == database.TodoStatusCancelled) {
+			if err := tx.completeParents(ctx, userID, updatedTodo.ParentID); err != nil {
+				return err
+			}
+		}
+
+		if status == database.TodoStatusDone {
+			if updatedTodo, err = tx.recur(ctx, userID, updatedTodo); err != nil {
+				return err
+			}
+		}
+
+		res, err = tx.publishTodoChange(ctx, userID, event, updatedTodo, loc)
 		return err
+	})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("todo status changed", "todo_id", todo.ID, "from", todo.Status, "to", status)
+
+	return res, nil
+}
+
+// completeParents marks the parent of a finished subtask as done when it has no unfinished subtasks left,
+// going up the tree while parents get completed, and publishes todo.completed about each of them.
+// A completed parent that repeats gets its next occurrence, the same way as a todo completed directly.
+func (t TodoService) completeParents(ctx context.Context, userID int32, parentID sql.NullInt32) error {
+	for parentID.Valid {
+		parent, err := t.repo.CompleteParentTodo(ctx, database.CompleteParentTodoParams{ID: parentID.Int32, OwnerID: userID})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+
+			return err
+		}
+		logger.FromContext(ctx).Info("parent todo completed", "todo_id", parent.ID)
+
+		if parent, err = t.recur(ctx, userID, parent); err != nil {
+			return err
+		}
+
+		if _, err := t.publishTodoChange(ctx, userID, WebhookEventTodoCompleted, parent, nil); err != nil {
+			return err
+		}
+
+		if err := t.touchRelatedTodos(ctx, userID, parent); err != nil {
+			return err
+		}
+
+		parentID = parent.ParentID
 	}
 
 	return nil
 }
 
-func (t TodoService) makeTodosResponseDto(todos []database.Todo) []dto.TodoResponseDto {
-	todosResponseDto := make([]dto.TodoResponseDto, len(todos))
+// checkBlockers makes sure none of the todos blocking a todo is still open or in progress.
+func (t TodoService) checkBlockers(ctx context.Context, todoID int32) error {
+	blockers, err := t.repo.ListTodoBlockers(ctx, []int32{todoID})
+	if err != nil {
+		return err
+	}
+
+	var unfinished []int32
+	for _, blocker := range blockers {
+		if isUnfinished(blocker.BlockerStatus) {
+			unfinished = append(unfinished, blocker.BlockerID)
+		}
+	}
+
+	if len(unfinished) > 0 {
+		return fmt.Errorf("%w: %v", ErrTodoBlocked, unfinished)
+	}
+
+	return nil
+}
+
+// isUnfinished tells whether a todo with status still blocks the todos depending on it.
+func isUnfinished(status database.TodoStatus) bool {
+	return status == database.TodoStatusOpen || status == database.TodoStatusInProgress
+}
+
+// missingTodoError tells a missing todo apart from one whose version doesn't satisfy the precondition,
+// after a conditional write has affected no rows.
+func (t TodoService) missingTodoError(ctx context.Context, userID, todoID int32, versions []int32, err error) error {
+	if len(versions) > 0 {
+		todo, getErr := t.repo.GetTodo(ctx, database.GetTodoParams{ID: todoID, OwnerID: userID})
+		if getErr == nil {
+			return fmt.Errorf("%w: version %d is not one of %v", ErrTodoModified, todo.Version, versions)
+		}
+
+		if !errors.Is(getErr, sql.ErrNoRows) {
+			return getErr
+		}
+	}
+
+	return fmt.Errorf("%w: %w", ErrTodoNotFound, err)
+}
+
+// setTodoTags replaces the tags of a todo with tags, creating the ones the user doesn't have yet.
+func (t TodoService) setTodoTags(ctx context.Context, userID, todoID int32, tags []string) error {
+	return t.repo.SetTodoTags(ctx, database.SetTodoTagsParams{
+		TodoID:  todoID,
+		OwnerID: userID,
+		Names:   tags,
+	})
+}
+
+// todoDetails holds the data of a todo response that is loaded apart from the todo itself.
+type todoDetails struct {
+	tags      []string
+	progress  *int32
+	blocked   bool
+	blockedBy []int32
+}
+
+// loadTodosDetails returns the tags, the subtasks progress and the blockers of each of todos by todo ID, with a query for each.
+func (t TodoService) loadTodosDetails(ctx context.Context, todos []database.Todo) (map[int32]todoDetails, error) {
+	if len(todos) == 0 {
+		return nil, nil
+	}
+
+	todoIDs := make([]int32, len(todos))
 	for i, todo := range todos {
-		todosResponseDto[i] = dto.TodoResponseDto{
-			ID:          todo.ID,
-			Title:       todo.Title,
-			Description: todo.Description,
-			DueDate:     todo.DueDate,
-			CreatedAt:   todo.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   todo.UpdatedAt.Format(time.RFC3339),
+		todoIDs[i] = todo.ID
+	}
+
+	tagRows, err := t.repo.ListTodoTags(ctx, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	progressRows, err := t.repo.ListSubtaskProgress(ctx, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	blockerRows, err := t.repo.ListTodoBlockers(ctx, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[int32]todoDetails, len(todos))
+	for _, row := range tagRows {
+		entry := details[row.TodoID]
+		entry.tags = append(entry.tags, row.Name)
+		details[row.TodoID] = entry
+	}
+
+	for _, row := range progressRows {
+		if row.Total == 0 {
+			continue
+		}
+
+		entry := details[row.TodoID]
+		progress := row.Done * 100 / row.Total
+		entry.progress = &progress
+		details[row.TodoID] = entry
+	}
+
+	for _, row := range blockerRows {
+		entry := details[row.TodoID]
+		entry.blockedBy = append(entry.blockedBy, row.BlockerID)
+		entry.blocked = entry.blocked || isUnfinished(row.BlockerStatus)
+		details[row.TodoID] = entry
+	}
+
+	return details, nil
+}
+
+func (t TodoService) loadTodoResponseDto(ctx context.Context, todo database.Todo, loc *time.Location) (dto.TodoResponseDto, error) {
+	details, err := t.loadTodosDetails(ctx, []database.Todo{todo})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	return t.makeTodoResponseDto(todo, details[todo.ID], loc), nil
+}
+
+// publishTodoChange publishes event about a changed todo and returns the todo rendered in loc.
+// Events carry timestamps as stored, whatever location the response is rendered in.
+func (t TodoService) publishTodoChange(ctx context.Context, userID int32, event string, todo database.Todo, loc *time.Location) (dto.TodoResponseDto, error) {
+	details, err := t.loadTodosDetails(ctx, []database.Todo{todo})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	if err := publishTodoEvent(ctx, t.repo, userID, event, t.makeTodoResponseDto(todo, details[todo.ID], nil)); err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	return t.makeTodoResponseDto(todo, details[todo.ID], loc), nil
+}
+
+// touchTodo bumps the version of a todo whose response has changed with other todos, like its blockers
+// or the progress of its subtasks, publishes todo.updated about it and returns it rendered in loc.
+func (t TodoService) touchTodo(ctx context.Context, userID, todoID int32, loc *time.Location) (dto.TodoResponseDto, error) {
+	todo, err := t.repo.TouchTodo(ctx, database.TouchTodoParams{ID: todoID, OwnerID: userID})
+	if err != nil {
+		return dto.TodoResponseDto{}, err
+	}
+
+	return t.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, todo, loc)
+}
+
+// touchRelatedTodos touches the parent of a todo that got finished or reopened and the todos it blocks,
+// since the progress of the parent and the blocked flag of the blocked todos depend on it.
+func (t TodoService) touchRelatedTodos(ctx context.Context, userID int32, todo database.Todo) error {
+	if todo.ParentID.Valid {
+		if _, err := t.touchTodo(ctx, userID, todo.ParentID.Int32, nil); err != nil {
+			return err
+		}
+	}
+
+	blockedTodos, err := t.repo.TouchBlockedTodos(ctx, database.TouchBlockedTodosParams{BlockerID: todo.ID, OwnerID: userID})
+	if err != nil {
+		return err
+	}
+
+	for _, blockedTodo := range blockedTodos {
+		if _, err := t.publishTodoChange(ctx, userID, WebhookEventTodoUpdated, blockedTodo, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishDeletedTodos publishes todo.deleted about each of the deleted todos, including the subtasks
+// and project todos removed along with them.
+func (t TodoService) publishDeletedTodos(ctx context.Context, userID int32, todos []database.Todo) error {
+	for _, todo := range todos {
+		if err := publishTodoEvent(ctx, t.repo, userID, WebhookEventTodoDeleted, t.makeTodoResponseDto(todo, todoDetails{}, nil)); err != nil {
+			return err
 		}
 	}
-	return todosResponseDto
+
+	return nil
+}
+
+// inTx runs fn with a copy of the service whose queries are made in a single transaction,
+// so a change of a todo is saved together with its events or not at all.
+func (t TodoService) inTx(ctx context.Context, fn func(tx TodoService) error) error {
+	return t.repo.InTx(ctx, func(repo database.Repository) error {
+		tx := t
+		tx.repo = repo
+		return fn(tx)
+	})
+}
+
+func (t TodoService) makeTodosResponseDto(ctx context.Context, todos []database.Todo, loc *time.Location) ([]dto.TodoResponseDto, error) {
+	details, err := t.loadTodosDetails(ctx, todos)
+	if err != nil {
+		return nil, err
+	}
+
+	todosResponseDto := make([]dto.TodoResponseDto, len(todos))
+	for i, todo := range todos {
+		todosResponseDto[i] = t.makeTodoResponseDto(todo, details[todo.ID], loc)
+	}
+	return todosResponseDto, nil
+}
+
+func (t TodoService) makeTodoResponseDto(todo database.Todo, details todoDetails, loc *time.Location) dto.TodoResponseDto {
+	tags := details.tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	blockedBy := details.blockedBy
+	if blockedBy == nil {
+		blockedBy = []int32{}
+	}
+
+	return dto.TodoResponseDto{
+		ID:          todo.ID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		DueDate:     formatTime(todo.DueDate, loc),
+		Status:      string(todo.Status),
+		ProjectID:   fromNullInt32(todo.ProjectID),
+		ParentID:    fromNullInt32(todo.ParentID),
+		Tags:        tags,
+		Progress:    details.progress,
+		Blocked:     details.blocked,
+		BlockedBy:   blockedBy,
+		Recurrence:  fromNullString(todo.Recurrence),
+		SeriesID:    fromNullInt32(todo.SeriesID),
+		CompletedAt: formatNullTime(todo.CompletedAt, loc),
+		CreatedAt:   formatTime(todo.CreatedAt, loc),
+		UpdatedAt:   formatTime(todo.UpdatedAt, loc),
+		Version:     todo.Version,
+	}
+}
+
+// The SearchTodos query delimits matches with the STX and ETX control characters and removes them from the todo text,
+// so the delimiters can't be confused with anything the user wrote.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// highlightMarker turns the match delimiters of a ts_headline snippet into <mark> tags.
+var highlightMarker = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// escapeHighlight makes a ts_headline snippet safe to embed into HTML, keeping only the <mark> tags around matches as markup.
+func escapeHighlight(snippet string) string {
+	return highlightMarker.Replace(html.EscapeString(snippet))
 }
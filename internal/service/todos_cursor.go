@@ -0,0 +1,60 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+	"to-do-list-go/internal/database"
+)
+
+// todosCursor points at the last todo of a page in the keyset ordering described by Sort.
+type todosCursor struct {
+	Sort string     `json:"s"`
+	Key1 time.Time  `json:"k1"`
+	Key2 *time.Time `json:"k2,omitempty"`
+	ID   int32      `json:"id"`
+}
+
+func (c todosCursor) encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeTodosCursor(encoded string) (todosCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return todosCursor{}, err
+	}
+
+	var cursor todosCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return todosCursor{}, err
+	}
+
+	return cursor, nil
+}
+
+// parseSortKey splits a sort key like "-due_date" into the field name and the descending flag.
+func parseSortKey(key string) (string, bool) {
+	if field, found := strings.CutPrefix(key, "-"); found {
+		return field, true
+	}
+
+	return key, false
+}
+
+func sortKeyValue(todo database.Todo, field string) time.Time {
+	switch field {
+	case "due_date":
+		return todo.DueDate
+	case "updated_at":
+		return todo.UpdatedAt
+	default:
+		return todo.CreatedAt
+	}
+}
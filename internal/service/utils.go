@@ -0,0 +1,65 @@
+package service
+
+import (
+	"database/sql"
+	"time"
+)
+
+// formatTime renders t in RFC3339, converting it to loc first when loc is set.
+func formatTime(t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// formatNullTime renders a nullable time like formatTime, returning nil when it is not set.
+func formatNullTime(t sql.NullTime, loc *time.Location) *string {
+	if !t.Valid {
+		return nil
+	}
+
+	formatted := formatTime(t.Time, loc)
+	return &formatted
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func fromNullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+
+	return &s.String
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func toNullInt32(n *int32) sql.NullInt32 {
+	if n == nil {
+		return sql.NullInt32{}
+	}
+
+	return sql.NullInt32{Int32: *n, Valid: true}
+}
+
+func fromNullInt32(n sql.NullInt32) *int32 {
+	if !n.Valid {
+		return nil
+	}
+
+	return &n.Int32
+}
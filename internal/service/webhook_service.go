@@ -0,0 +1,351 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+	"to-do-list-go/internal/database"
+	"to-do-list-go/internal/delivery/dto"
+	"to-do-list-go/internal/domain"
+	"to-do-list-go/internal/logger"
+	"to-do-list-go/internal/outbound"
+)
+
+// Defines the todo events webhooks can subscribe to.
+const (
+	WebhookEventTodoCreated   = "todo.created"
+	WebhookEventTodoUpdated   = "todo.updated"
+	WebhookEventTodoCompleted = "todo.completed"
+	WebhookEventTodoDeleted   = "todo.deleted"
+)
+
+// Defines the headers of webhook requests besides the signature ones, see outbound.SetSignature.
+const (
+	WebhookEventHeader    = "X-Webhook-Event"
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+)
+
+const (
+	webhookSecretPrefix = "whsec_"
+	// webhookDeliveriesLimit is the number of latest deliveries returned by ListWebhookDeliveries.
+	webhookDeliveriesLimit = 100
+	// webhookBatchSize limits the number of deliveries claimed by a single DeliverWebhooks call.
+	webhookBatchSize = 20
+	// webhookTimeout limits the time a receiver may take to respond to a delivery.
+	webhookTimeout = 10 * time.Second
+	// webhookLease is how long claimed deliveries stay hidden from other dispatchers,
+	// it must be longer than the time needed to post a whole batch.
+	webhookLease = 5 * time.Minute
+	// webhookMaxAttempts is the number of attempts after which a delivery is marked as failed.
+	webhookMaxAttempts = 8
+	// webhookRetryDelay is the delay before the second attempt of a delivery, it doubles with every further attempt.
+	webhookRetryDelay = 30 * time.Second
+	// webhookMaxFailures is the number of failed attempts in a row after which a webhook is disabled.
+	webhookMaxFailures = 20
+)
+
+// Defines errors returned by WebhookService.
+var (
+	ErrWebhookNotFound   = domain.NewError(domain.ErrNotFound, "webhook with this id not found")
+	ErrInvalidWebhookURL = domain.NewError(domain.ErrValidation, "webhook url must be an http or https URL of a public host")
+)
+
+// WebhookService handles webhooks users subscribe to todo events with, and the log of their deliveries.
+type WebhookService struct {
+	repo database.Repository
+}
+
+func newWebhookService(repo database.Repository) *WebhookService {
+	return &WebhookService{
+		repo: repo,
+	}
+}
+
+// CreateWebhook subscribes a URL to todo events of the user.
+// The secret payloads are signed with is generated here and returned just this once.
+func (s WebhookService) CreateWebhook(ctx context.Context, userID int32, webhookInput dto.WebhookInputDto, loc *time.Location) (dto.WebhookCreatedDto, error) {
+	if err := outbound.CheckURL(webhookInput.URL); err != nil {
+		return dto.WebhookCreatedDto{}, fmt.Errorf("%w: %w", ErrInvalidWebhookURL, err)
+	}
+
+	token, err := newRandomToken()
+	if err != nil {
+		return dto.WebhookCreatedDto{}, err
+	}
+	secret := webhookSecretPrefix + token
+
+	webhook, err := s.repo.CreateWebhook(ctx, database.CreateWebhookParams{
+		UserID: userID,
+		URL:    webhookInput.URL,
+		Secret: secret,
+		Events: normalizeWebhookEvents(webhookInput.Events),
+		Active: webhookInput.Active == nil || *webhookInput.Active,
+	})
+	if err != nil {
+		return dto.WebhookCreatedDto{}, err
+	}
+	logger.FromContext(ctx).Info("webhook created", "webhook_id", webhook.ID, "events", webhook.Events)
+
+	return dto.WebhookCreatedDto{
+		WebhookResponseDto: makeWebhookResponseDto(webhook, loc),
+		Secret:             secret,
+	}, nil
+}
+
+// ListWebhooks returns the webhooks of the user, including the disabled ones.
+func (s WebhookService) ListWebhooks(ctx context.Context, userID int32, loc *time.Location) (dto.WebhooksDto, error) {
+	webhooks, err := s.repo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return dto.WebhooksDto{}, err
+	}
+
+	items := make([]dto.WebhookResponseDto, len(webhooks))
+	for i, webhook := range webhooks {
+		items[i] = makeWebhookResponseDto(webhook, loc)
+	}
+
+	return dto.WebhooksDto{Items: items}, nil
+}
+
+// GetWebhook returns a webhook of the user by ID.
+func (s WebhookService) GetWebhook(ctx context.Context, userID int32, webhookID int, loc *time.Location) (dto.WebhookResponseDto, error) {
+	webhook, err := s.repo.GetWebhook(ctx, database.GetWebhookParams{ID: int32(webhookID), UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.WebhookResponseDto{}, fmt.Errorf("%w: %w", ErrWebhookNotFound, err)
+		}
+
+		return dto.WebhookResponseDto{}, err
+	}
+
+	return makeWebhookResponseDto(webhook, loc), nil
+}
+
+// UpdateWebhook replaces the URL, events and state of a webhook of the user.
+// Activating a webhook clears its failures, so a webhook disabled after repeated failures is re-enabled this way.
+func (s WebhookService) UpdateWebhook(ctx context.Context, userID int32, webhookID int, webhookInput dto.WebhookInputDto, loc *time.Location) (dto.WebhookResponseDto, error) {
+	if err := outbound.CheckURL(webhookInput.URL); err != nil {
+		return dto.WebhookResponseDto{}, fmt.Errorf("%w: %w", ErrInvalidWebhookURL, err)
+	}
+
+	webhook, err := s.repo.UpdateWebhook(ctx, database.UpdateWebhookParams{
+		URL:    webhookInput.URL,
+		Events: normalizeWebhookEvents(webhookInput.Events),
+		Active: webhookInput.Active == nil || *webhookInput.Active,
+		ID:     int32(webhookID),
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.WebhookResponseDto{}, fmt.Errorf("%w: %w", ErrWebhookNotFound, err)
+		}
+
+		return dto.WebhookResponseDto{}, err
+	}
+	logger.FromContext(ctx).Info("webhook updated", "webhook_id", webhook.ID, "events", webhook.Events, "active", webhook.Active)
+
+	return makeWebhookResponseDto(webhook, loc), nil
+}
+
+// DeleteWebhook deletes a webhook of the user together with its deliveries.
+func (s WebhookService) DeleteWebhook(ctx context.Context, userID int32, webhookID int) error {
+	if _, err := s.repo.DeleteWebhook(ctx, database.DeleteWebhookParams{ID: int32(webhookID), UserID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: %w", ErrWebhookNotFound, err)
+		}
+
+		return err
+	}
+	logger.FromContext(ctx).Info("webhook deleted", "webhook_id", webhookID)
+
+	return nil
+}
+
+// ListWebhookDeliveries returns the latest webhookDeliveriesLimit deliveries of a webhook of the user, newest first.
+func (s WebhookService) ListWebhookDeliveries(ctx context.Context, userID int32, webhookID int, loc *time.Location) (dto.WebhookDeliveriesDto, error) {
+	if _, err := s.repo.GetWebhook(ctx, database.GetWebhookParams{ID: int32(webhookID), UserID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.WebhookDeliveriesDto{}, fmt.Errorf("%w: %w", ErrWebhookNotFound, err)
+		}
+
+		return dto.WebhookDeliveriesDto{}, err
+	}
+
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, database.ListWebhookDeliveriesParams{
+		WebhookID: int32(webhookID),
+		UserID:    userID,
+		RowLimit:  webhookDeliveriesLimit,
+	})
+	if err != nil {
+		return dto.WebhookDeliveriesDto{}, err
+	}
+
+	items := make([]dto.WebhookDeliveryDto, len(deliveries))
+	for i, delivery := range deliveries {
+		items[i] = makeWebhookDeliveryDto(delivery, loc)
+	}
+
+	return dto.WebhookDeliveriesDto{Items: items}, nil
+}
+
+// normalizeWebhookEvents sorts events and drops duplicates, so equal subscriptions are stored the same way.
+func normalizeWebhookEvents(events []string) []string {
+	events = slices.Clone(events)
+	slices.Sort(events)
+
+	return slices.Compact(events)
+}
+
+func makeWebhookResponseDto(webhook database.Webhook, loc *time.Location) dto.WebhookResponseDto {
+	return dto.WebhookResponseDto{
+		ID:                  webhook.ID,
+		URL:                 webhook.URL,
+		Events:              webhook.Events,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          formatNullTime(webhook.DisabledAt, loc),
+		CreatedAt:           formatTime(webhook.CreatedAt, loc),
+		UpdatedAt:           formatTime(webhook.UpdatedAt, loc),
+	}
+}
+
+func makeWebhookDeliveryDto(delivery database.WebhookDelivery, loc *time.Location) dto.WebhookDeliveryDto {
+	status := "pending"
+	var nextAttemptAt *string
+	switch {
+	case delivery.DeliveredAt.Valid:
+		status = "delivered"
+	case delivery.FailedAt.Valid:
+		status = "failed"
+	default:
+		formatted := formatTime(delivery.NextAttemptAt, loc)
+		nextAttemptAt = &formatted
+	}
+
+	return dto.WebhookDeliveryDto{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		Status:         status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: fromNullInt32(delivery.ResponseStatus),
+		LastError:      fromNullString(delivery.LastError),
+		NextAttemptAt:  nextAttemptAt,
+		DeliveredAt:    formatNullTime(delivery.DeliveredAt, loc),
+		CreatedAt:      formatTime(delivery.CreatedAt, loc),
+	}
+}
+
+// WebhookDispatcher posts the pending webhook deliveries to their receivers.
+// Deliveries are claimed before they are posted, so dispatchers of several replicas can share the database without posting a delivery twice,
+// unless a replica stops after posting a delivery and before recording it. Receivers should use the delivery header to drop duplicates.
+type WebhookDispatcher struct {
+	repo   database.Repository
+	client *http.Client
+}
+
+// NewWebhookDispatcher creates a dispatcher posting deliveries with client.
+// Webhook URLs are chosen by users, so client should be an outbound.NewClient that can't reach the internal network.
+func NewWebhookDispatcher(repo database.Repository, client *http.Client) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: client,
+	}
+}
+
+// DeliverWebhooks posts the deliveries of active webhooks that are due at now and returns the number of delivered ones.
+// Failed deliveries are retried with an exponential backoff until webhookMaxAttempts is reached,
+// and webhooks failing webhookMaxFailures attempts in a row are disabled.
+func (d WebhookDispatcher) DeliverWebhooks(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, database.ClaimWebhookDeliveriesParams{
+		Now:         now,
+		LockedUntil: now.Add(webhookLease),
+		RowLimit:    webhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var delivered int
+	for _, delivery := range deliveries {
+		status, err := d.post(ctx, delivery, now)
+		if err != nil {
+			d.recordFailure(ctx, delivery, now, status, err)
+			continue
+		}
+
+		if err := d.repo.RecordWebhookDeliverySuccess(ctx, database.RecordWebhookDeliverySuccessParams{
+			ResponseStatus: int32(status),
+			ID:             delivery.ID,
+		}); err != nil {
+			logger.FromContext(ctx).Error("failed to record webhook delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		logger.FromContext(ctx).Info("webhook delivered", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "event", delivery.Event)
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// post sends a delivery to its webhook and returns the response status, which is 0 when no response was received.
+// Any response status other than 2xx is reported as an error.
+func (d WebhookDispatcher) post(ctx context.Context, delivery database.ClaimWebhookDeliveriesRow, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(int(delivery.ID)))
+	outbound.SetSignature(req, delivery.Secret, now.Unix(), delivery.Payload)
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
+
+// recordFailure schedules the next attempt of a delivery, or marks it as failed when no attempts are left.
+func (d WebhookDispatcher) recordFailure(ctx context.Context, delivery database.ClaimWebhookDeliveriesRow, now time.Time, status int, err error) {
+	var retryAt sql.NullTime
+	if delivery.Attempts < webhookMaxAttempts {
+		retryAt = sql.NullTime{Time: now.Add(webhookRetryDelay << (delivery.Attempts - 1)), Valid: true}
+	}
+	logger.FromContext(ctx).Warn("failed to deliver webhook", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "attempts", delivery.Attempts, "retry", retryAt.Valid, "error", err)
+
+	webhook, err := d.repo.RecordWebhookDeliveryFailure(ctx, database.RecordWebhookDeliveryFailureParams{
+		MaxFailures:    webhookMaxFailures,
+		ResponseStatus: sql.NullInt32{Int32: int32(status), Valid: status != 0},
+		LastError:      err.Error(),
+		RetryAt:        retryAt,
+		ID:             delivery.ID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.FromContext(ctx).Error("failed to record webhook delivery failure", "delivery_id", delivery.ID, "error", err)
+		}
+
+		return
+	}
+
+	if !webhook.Active && webhook.ConsecutiveFailures == webhookMaxFailures {
+		logger.FromContext(ctx).Warn("webhook disabled after repeated failures", "webhook_id", webhook.ID, "failures", webhook.ConsecutiveFailures)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/outbound"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	type mockBehavior func(repo *mock_repo.MockRepository, url string)
+
+	now := time.Date(2024, 9, 5, 12, 0, 0, 0, time.UTC)
+	payload := json.RawMessage(`{"event":"todo.completed","occurred_at":"2024-09-05T11:59:58Z","data":{"id":1}}`)
+	delivery := func(id int32, url string, attempts int32) database.ClaimWebhookDeliveriesRow {
+		return database.ClaimWebhookDeliveriesRow{
+			ID:        id,
+			WebhookID: 3,
+			Event:     WebhookEventTodoCompleted,
+			Payload:   payload,
+			Attempts:  attempts,
+			URL:       url,
+			Secret:    "whsec_secret",
+		}
+	}
+
+	tests := []struct {
+		name              string
+		receiverStatus    int
+		closedReceiver    bool
+		expectedDelivered int
+		expectedErr       bool
+		expectedRequests  int
+		mockBehavior      mockBehavior
+	}{
+		{
+			name:              "Delivers Signed Events",
+			receiverStatus:    http.StatusNoContent,
+			expectedDelivered: 1,
+			expectedRequests:  1,
+			mockBehavior: func(repo *mock_repo.MockRepository, url string) {
+				repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), database.ClaimWebhookDeliveriesParams{
+					Now:         now,
+					LockedUntil: now.Add(webhookLease),
+					RowLimit:    webhookBatchSize,
+				}).Return([]database.ClaimWebhookDeliveriesRow{delivery(7, url, 1)}, nil).Times(1)
+				repo.EXPECT().RecordWebhookDeliverySuccess(gomock.Any(), database.RecordWebhookDeliverySuccessParams{
+					ResponseStatus: http.StatusNoContent,
+					ID:             7,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:             "Retries Failed Deliveries",
+			receiverStatus:   http.StatusBadGateway,
+			expectedRequests: 2,
+			mockBehavior: func(repo *mock_repo.MockRepository, url string) {
+				repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return([]database.ClaimWebhookDeliveriesRow{
+					delivery(7, url, 3),
+					delivery(8, url, webhookMaxAttempts),
+				}, nil).Times(1)
+				repo.EXPECT().RecordWebhookDeliveryFailure(gomock.Any(), database.RecordWebhookDeliveryFailureParams{
+					MaxFailures:    webhookMaxFailures,
+					ResponseStatus: sql.NullInt32{Int32: http.StatusBadGateway, Valid: true},
+					LastError:      "webhook responded with status 502",
+					RetryAt:        sql.NullTime{Time: now.Add(4 * webhookRetryDelay), Valid: true},
+					ID:             7,
+				}).Return(database.Webhook{ID: 3, Active: true, ConsecutiveFailures: 1}, nil).Times(1)
+				repo.EXPECT().RecordWebhookDeliveryFailure(gomock.Any(), database.RecordWebhookDeliveryFailureParams{
+					MaxFailures:    webhookMaxFailures,
+					ResponseStatus: sql.NullInt32{Int32: http.StatusBadGateway, Valid: true},
+					LastError:      "webhook responded with status 502",
+					ID:             8,
+				}).Return(database.Webhook{ID: 3, ConsecutiveFailures: webhookMaxFailures}, nil).Times(1)
+			},
+		},
+		{
+			name:           "Records Unreachable Receivers",
+			closedReceiver: true,
+			mockBehavior: func(repo *mock_repo.MockRepository, url string) {
+				repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return([]database.ClaimWebhookDeliveriesRow{delivery(7, url, 1)}, nil).Times(1)
+				repo.EXPECT().RecordWebhookDeliveryFailure(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg database.RecordWebhookDeliveryFailureParams) (database.Webhook, error) {
+					require.Equal(t, int32(7), arg.ID)
+					require.False(t, arg.ResponseStatus.Valid)
+					require.NotEmpty(t, arg.LastError)
+					require.Equal(t, sql.NullTime{Time: now.Add(webhookRetryDelay), Valid: true}, arg.RetryAt)
+					return database.Webhook{ID: 3, Active: true, ConsecutiveFailures: 1}, nil
+				}).Times(1)
+			},
+		},
+		{
+			name:        "Repo Error",
+			expectedErr: true,
+			mockBehavior: func(repo *mock_repo.MockRepository, url string) {
+				repo.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			var requests int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				timestamp, err := strconv.ParseInt(r.Header.Get(outbound.TimestampHeader), 10, 64)
+				require.NoError(t, err)
+				require.Equal(t, now.Unix(), timestamp)
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.Equal(t, WebhookEventTodoCompleted, r.Header.Get(WebhookEventHeader))
+				require.NotEmpty(t, r.Header.Get(WebhookDeliveryHeader))
+				require.Equal(t, outbound.Sign("whsec_secret", timestamp, body), r.Header.Get(outbound.SignatureHeader))
+				require.JSONEq(t, string(payload), string(body))
+				w.WriteHeader(tt.receiverStatus)
+			}))
+			defer srv.Close()
+			if tt.closedReceiver {
+				srv.Close()
+			}
+
+			repo := mock_repo.NewMockRepository(ctl)
+			tt.mockBehavior(repo, srv.URL)
+
+			delivered, err := NewWebhookDispatcher(repo, srv.Client()).DeliverWebhooks(context.Background(), now)
+
+			require.Equal(t, tt.expectedErr, err != nil)
+			require.Equal(t, tt.expectedDelivered, delivered)
+			require.Equal(t, tt.expectedRequests, requests)
+		})
+	}
+}
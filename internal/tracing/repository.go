@@ -0,0 +1,448 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+	"to-do-list-go/internal/database"
+)
+
+// repository starts a span for each query of the wrapped database.Repository.
+type repository struct {
+	next database.Repository
+}
+
+// NewRepository wraps repo, so each of its queries is traced.
+func NewRepository(repo database.Repository) database.Repository {
+	return &repository{
+		next: repo,
+	}
+}
+
+func (r *repository) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "Repository."+query,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", query),
+		),
+	)
+}
+
+func (r *repository) end(span trace.Span, err *error) {
+	End(span, *err, func(err error) bool { return errors.Is(err, sql.ErrNoRows) })
+}
+
+func (r *repository) CreateTodo(ctx context.Context, arg database.CreateTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "CreateTodo")
+	defer r.end(span, &err)
+	return r.next.CreateTodo(ctx, arg)
+}
+
+func (r *repository) ListTodos(ctx context.Context, arg database.ListTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "ListTodos")
+	defer r.end(span, &err)
+	return r.next.ListTodos(ctx, arg)
+}
+
+func (r *repository) GetTodo(ctx context.Context, arg database.GetTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "GetTodo")
+	defer r.end(span, &err)
+	return r.next.GetTodo(ctx, arg)
+}
+
+func (r *repository) UpdateTodo(ctx context.Context, arg database.UpdateTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "UpdateTodo")
+	defer r.end(span, &err)
+	return r.next.UpdateTodo(ctx, arg)
+}
+
+func (r *repository) PatchTodo(ctx context.Context, arg database.PatchTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "PatchTodo")
+	defer r.end(span, &err)
+	return r.next.PatchTodo(ctx, arg)
+}
+
+func (r *repository) DeleteTodo(ctx context.Context, arg database.DeleteTodoParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "DeleteTodo")
+	defer r.end(span, &err)
+	return r.next.DeleteTodo(ctx, arg)
+}
+
+func (r *repository) UpdateTodoStatus(ctx context.Context, arg database.UpdateTodoStatusParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "UpdateTodoStatus")
+	defer r.end(span, &err)
+	return r.next.UpdateTodoStatus(ctx, arg)
+}
+
+func (r *repository) TouchTodo(ctx context.Context, arg database.TouchTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "TouchTodo")
+	defer r.end(span, &err)
+	return r.next.TouchTodo(ctx, arg)
+}
+
+func (r *repository) SearchTodos(ctx context.Context, arg database.SearchTodosParams) (rows []database.SearchTodosRow, err error) {
+	ctx, span := r.start(ctx, "SearchTodos")
+	defer r.end(span, &err)
+	return r.next.SearchTodos(ctx, arg)
+}
+
+func (r *repository) MigrationVersion(ctx context.Context) (version int64, err error) {
+	ctx, span := r.start(ctx, "MigrationVersion")
+	defer r.end(span, &err)
+	return r.next.MigrationVersion(ctx)
+}
+
+func (r *repository) CreateUser(ctx context.Context, arg database.CreateUserParams) (user database.User, err error) {
+	ctx, span := r.start(ctx, "CreateUser")
+	defer r.end(span, &err)
+	return r.next.CreateUser(ctx, arg)
+}
+
+func (r *repository) GetUserByEmail(ctx context.Context, email string) (user database.User, err error) {
+	ctx, span := r.start(ctx, "GetUserByEmail")
+	defer r.end(span, &err)
+	return r.next.GetUserByEmail(ctx, email)
+}
+
+func (r *repository) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (token database.RefreshToken, err error) {
+	ctx, span := r.start(ctx, "CreateRefreshToken")
+	defer r.end(span, &err)
+	return r.next.CreateRefreshToken(ctx, arg)
+}
+
+func (r *repository) ConsumeRefreshToken(ctx context.Context, tokenHash string) (token database.RefreshToken, err error) {
+	ctx, span := r.start(ctx, "ConsumeRefreshToken")
+	defer r.end(span, &err)
+	return r.next.ConsumeRefreshToken(ctx, tokenHash)
+}
+
+func (r *repository) CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (key database.APIKey, err error) {
+	ctx, span := r.start(ctx, "CreateAPIKey")
+	defer r.end(span, &err)
+	return r.next.CreateAPIKey(ctx, arg)
+}
+
+func (r *repository) ListAPIKeys(ctx context.Context, userID int32) (keys []database.APIKey, err error) {
+	ctx, span := r.start(ctx, "ListAPIKeys")
+	defer r.end(span, &err)
+	return r.next.ListAPIKeys(ctx, userID)
+}
+
+func (r *repository) RevokeAPIKey(ctx context.Context, arg database.RevokeAPIKeyParams) (key database.APIKey, err error) {
+	ctx, span := r.start(ctx, "RevokeAPIKey")
+	defer r.end(span, &err)
+	return r.next.RevokeAPIKey(ctx, arg)
+}
+
+func (r *repository) UseAPIKey(ctx context.Context, keyHash string) (key database.APIKey, err error) {
+	ctx, span := r.start(ctx, "UseAPIKey")
+	defer r.end(span, &err)
+	return r.next.UseAPIKey(ctx, keyHash)
+}
+
+func (r *repository) MoveTodo(ctx context.Context, arg database.MoveTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "MoveTodo")
+	defer r.end(span, &err)
+	return r.next.MoveTodo(ctx, arg)
+}
+
+func (r *repository) CreateProject(ctx context.Context, arg database.CreateProjectParams) (project database.Project, err error) {
+	ctx, span := r.start(ctx, "CreateProject")
+	defer r.end(span, &err)
+	return r.next.CreateProject(ctx, arg)
+}
+
+func (r *repository) ListProjects(ctx context.Context, arg database.ListProjectsParams) (projects []database.Project, err error) {
+	ctx, span := r.start(ctx, "ListProjects")
+	defer r.end(span, &err)
+	return r.next.ListProjects(ctx, arg)
+}
+
+func (r *repository) GetProject(ctx context.Context, arg database.GetProjectParams) (project database.Project, err error) {
+	ctx, span := r.start(ctx, "GetProject")
+	defer r.end(span, &err)
+	return r.next.GetProject(ctx, arg)
+}
+
+func (r *repository) UpdateProject(ctx context.Context, arg database.UpdateProjectParams) (project database.Project, err error) {
+	ctx, span := r.start(ctx, "UpdateProject")
+	defer r.end(span, &err)
+	return r.next.UpdateProject(ctx, arg)
+}
+
+func (r *repository) DeleteProject(ctx context.Context, arg database.DeleteProjectParams) (project database.Project, err error) {
+	ctx, span := r.start(ctx, "DeleteProject")
+	defer r.end(span, &err)
+	return r.next.DeleteProject(ctx, arg)
+}
+
+func (r *repository) DeleteProjectTodos(ctx context.Context, arg database.DeleteProjectTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "DeleteProjectTodos")
+	defer r.end(span, &err)
+	return r.next.DeleteProjectTodos(ctx, arg)
+}
+
+func (r *repository) MoveProjectTodos(ctx context.Context, arg database.MoveProjectTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "MoveProjectTodos")
+	defer r.end(span, &err)
+	return r.next.MoveProjectTodos(ctx, arg)
+}
+
+func (r *repository) SetTodoTags(ctx context.Context, arg database.SetTodoTagsParams) (err error) {
+	ctx, span := r.start(ctx, "SetTodoTags")
+	defer r.end(span, &err)
+	return r.next.SetTodoTags(ctx, arg)
+}
+
+func (r *repository) ListTodoTags(ctx context.Context, todoIDs []int32) (rows []database.ListTodoTagsRow, err error) {
+	ctx, span := r.start(ctx, "ListTodoTags")
+	defer r.end(span, &err)
+	return r.next.ListTodoTags(ctx, todoIDs)
+}
+
+func (r *repository) ListTags(ctx context.Context, ownerID int32) (tags []database.ListTagsRow, err error) {
+	ctx, span := r.start(ctx, "ListTags")
+	defer r.end(span, &err)
+	return r.next.ListTags(ctx, ownerID)
+}
+
+func (r *repository) ListSubtaskTree(ctx context.Context, arg database.ListSubtaskTreeParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "ListSubtaskTree")
+	defer r.end(span, &err)
+	return r.next.ListSubtaskTree(ctx, arg)
+}
+
+func (r *repository) ListTodoAncestors(ctx context.Context, arg database.ListTodoAncestorsParams) (ids []int32, err error) {
+	ctx, span := r.start(ctx, "ListTodoAncestors")
+	defer r.end(span, &err)
+	return r.next.ListTodoAncestors(ctx, arg)
+}
+
+func (r *repository) SetTodoParent(ctx context.Context, arg database.SetTodoParentParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "SetTodoParent")
+	defer r.end(span, &err)
+	return r.next.SetTodoParent(ctx, arg)
+}
+
+func (r *repository) ListSubtaskProgress(ctx context.Context, todoIDs []int32) (rows []database.ListSubtaskProgressRow, err error) {
+	ctx, span := r.start(ctx, "ListSubtaskProgress")
+	defer r.end(span, &err)
+	return r.next.ListSubtaskProgress(ctx, todoIDs)
+}
+
+func (r *repository) CompleteParentTodo(ctx context.Context, arg database.CompleteParentTodoParams) (todo database.Todo, err error) {
+	ctx, span := r.start(ctx, "CompleteParentTodo")
+	defer r.end(span, &err)
+	return r.next.CompleteParentTodo(ctx, arg)
+}
+
+func (r *repository) LockTodoDependencies(ctx context.Context, ownerID int32) (err error) {
+	ctx, span := r.start(ctx, "LockTodoDependencies")
+	defer r.end(span, &err)
+	return r.next.LockTodoDependencies(ctx, ownerID)
+}
+
+func (r *repository) AddTodoBlocker(ctx context.Context, arg database.AddTodoBlockerParams) (rows int64, err error) {
+	ctx, span := r.start(ctx, "AddTodoBlocker")
+	defer r.end(span, &err)
+	return r.next.AddTodoBlocker(ctx, arg)
+}
+
+func (r *repository) RemoveTodoBlocker(ctx context.Context, arg database.RemoveTodoBlockerParams) (dependency database.TodoDependency, err error) {
+	ctx, span := r.start(ctx, "RemoveTodoBlocker")
+	defer r.end(span, &err)
+	return r.next.RemoveTodoBlocker(ctx, arg)
+}
+
+func (r *repository) ListTodoBlockerChain(ctx context.Context, arg database.ListTodoBlockerChainParams) (ids []int32, err error) {
+	ctx, span := r.start(ctx, "ListTodoBlockerChain")
+	defer r.end(span, &err)
+	return r.next.ListTodoBlockerChain(ctx, arg)
+}
+
+func (r *repository) ListTodoBlockers(ctx context.Context, todoIDs []int32) (rows []database.ListTodoBlockersRow, err error) {
+	ctx, span := r.start(ctx, "ListTodoBlockers")
+	defer r.end(span, &err)
+	return r.next.ListTodoBlockers(ctx, todoIDs)
+}
+
+func (r *repository) TouchBlockedTodos(ctx context.Context, arg database.TouchBlockedTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "TouchBlockedTodos")
+	defer r.end(span, &err)
+	return r.next.TouchBlockedTodos(ctx, arg)
+}
+
+func (r *repository) ListProjectTodos(ctx context.Context, arg database.ListProjectTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "ListProjectTodos")
+	defer r.end(span, &err)
+	return r.next.ListProjectTodos(ctx, arg)
+}
+
+func (r *repository) CreateNextOccurrence(ctx context.Context, arg database.CreateNextOccurrenceParams) (todo database.CreateNextOccurrenceRow, err error) {
+	ctx, span := r.start(ctx, "CreateNextOccurrence")
+	defer r.end(span, &err)
+	return r.next.CreateNextOccurrence(ctx, arg)
+}
+
+func (r *repository) EndTodoRecurrence(ctx context.Context, id int32) (err error) {
+	ctx, span := r.start(ctx, "EndTodoRecurrence")
+	defer r.end(span, &err)
+	return r.next.EndTodoRecurrence(ctx, id)
+}
+
+func (r *repository) ListDueRecurringTodos(ctx context.Context, arg database.ListDueRecurringTodosParams) (todos []database.Todo, err error) {
+	ctx, span := r.start(ctx, "ListDueRecurringTodos")
+	defer r.end(span, &err)
+	return r.next.ListDueRecurringTodos(ctx, arg)
+}
+
+func (r *repository) CreateReminder(ctx context.Context, arg database.CreateReminderParams) (reminder database.Reminder, err error) {
+	ctx, span := r.start(ctx, "CreateReminder")
+	defer r.end(span, &err)
+	return r.next.CreateReminder(ctx, arg)
+}
+
+func (r *repository) ListReminders(ctx context.Context, arg database.ListRemindersParams) (reminders []database.Reminder, err error) {
+	ctx, span := r.start(ctx, "ListReminders")
+	defer r.end(span, &err)
+	return r.next.ListReminders(ctx, arg)
+}
+
+func (r *repository) DeleteReminder(ctx context.Context, arg database.DeleteReminderParams) (reminder database.Reminder, err error) {
+	ctx, span := r.start(ctx, "DeleteReminder")
+	defer r.end(span, &err)
+	return r.next.DeleteReminder(ctx, arg)
+}
+
+func (r *repository) ClaimDueReminders(ctx context.Context, arg database.ClaimDueRemindersParams) (reminders []database.ClaimDueRemindersRow, err error) {
+	ctx, span := r.start(ctx, "ClaimDueReminders")
+	defer r.end(span, &err)
+	return r.next.ClaimDueReminders(ctx, arg)
+}
+
+func (r *repository) MarkReminderSent(ctx context.Context, id int32) (err error) {
+	ctx, span := r.start(ctx, "MarkReminderSent")
+	defer r.end(span, &err)
+	return r.next.MarkReminderSent(ctx, id)
+}
+
+func (r *repository) RecordReminderFailure(ctx context.Context, arg database.RecordReminderFailureParams) (err error) {
+	ctx, span := r.start(ctx, "RecordReminderFailure")
+	defer r.end(span, &err)
+	return r.next.RecordReminderFailure(ctx, arg)
+}
+
+func (r *repository) CreateWebhook(ctx context.Context, arg database.CreateWebhookParams) (rv database.Webhook, err error) {
+	ctx, span := r.start(ctx, "CreateWebhook")
+	defer r.end(span, &err)
+	return r.next.CreateWebhook(ctx, arg)
+}
+
+func (r *repository) ListWebhooks(ctx context.Context, userID int32) (rv []database.Webhook, err error) {
+	ctx, span := r.start(ctx, "ListWebhooks")
+	defer r.end(span, &err)
+	return r.next.ListWebhooks(ctx, userID)
+}
+
+func (r *repository) GetWebhook(ctx context.Context, arg database.GetWebhookParams) (rv database.Webhook, err error) {
+	ctx, span := r.start(ctx, "GetWebhook")
+	defer r.end(span, &err)
+	return r.next.GetWebhook(ctx, arg)
+}
+
+func (r *repository) UpdateWebhook(ctx context.Context, arg database.UpdateWebhookParams) (rv database.Webhook, err error) {
+	ctx, span := r.start(ctx, "UpdateWebhook")
+	defer r.end(span, &err)
+	return r.next.UpdateWebhook(ctx, arg)
+}
+
+func (r *repository) DeleteWebhook(ctx context.Context, arg database.DeleteWebhookParams) (rv database.Webhook, err error) {
+	ctx, span := r.start(ctx, "DeleteWebhook")
+	defer r.end(span, &err)
+	return r.next.DeleteWebhook(ctx, arg)
+}
+
+func (r *repository) ListWebhookDeliveries(ctx context.Context, arg database.ListWebhookDeliveriesParams) (rv []database.WebhookDelivery, err error) {
+	ctx, span := r.start(ctx, "ListWebhookDeliveries")
+	defer r.end(span, &err)
+	return r.next.ListWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) ClaimWebhookDeliveries(ctx context.Context, arg database.ClaimWebhookDeliveriesParams) (rv []database.ClaimWebhookDeliveriesRow, err error) {
+	ctx, span := r.start(ctx, "ClaimWebhookDeliveries")
+	defer r.end(span, &err)
+	return r.next.ClaimWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) RecordWebhookDeliveryFailure(ctx context.Context, arg database.RecordWebhookDeliveryFailureParams) (rv database.Webhook, err error) {
+	ctx, span := r.start(ctx, "RecordWebhookDeliveryFailure")
+	defer r.end(span, &err)
+	return r.next.RecordWebhookDeliveryFailure(ctx, arg)
+}
+
+func (r *repository) EnqueueWebhookDeliveries(ctx context.Context, arg database.EnqueueWebhookDeliveriesParams) (err error) {
+	ctx, span := r.start(ctx, "EnqueueWebhookDeliveries")
+	defer r.end(span, &err)
+	return r.next.EnqueueWebhookDeliveries(ctx, arg)
+}
+
+func (r *repository) RecordWebhookDeliverySuccess(ctx context.Context, arg database.RecordWebhookDeliverySuccessParams) (err error) {
+	ctx, span := r.start(ctx, "RecordWebhookDeliverySuccess")
+	defer r.end(span, &err)
+	return r.next.RecordWebhookDeliverySuccess(ctx, arg)
+}
+
+func (r *repository) ClaimOutboxEvents(ctx context.Context, rowLimit int32) (rv []database.Outbox, err error) {
+	ctx, span := r.start(ctx, "ClaimOutboxEvents")
+	defer r.end(span, &err)
+	return r.next.ClaimOutboxEvents(ctx, rowLimit)
+}
+
+func (r *repository) InsertOutboxEvent(ctx context.Context, arg database.InsertOutboxEventParams) (err error) {
+	ctx, span := r.start(ctx, "InsertOutboxEvent")
+	defer r.end(span, &err)
+	return r.next.InsertOutboxEvent(ctx, arg)
+}
+
+func (r *repository) MarkOutboxEventsSent(ctx context.Context, ids []int64) (err error) {
+	ctx, span := r.start(ctx, "MarkOutboxEventsSent")
+	defer r.end(span, &err)
+	return r.next.MarkOutboxEventsSent(ctx, ids)
+}
+
+func (r *repository) DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) (err error) {
+	ctx, span := r.start(ctx, "DeleteSentOutboxEvents")
+	defer r.end(span, &err)
+	return r.next.DeleteSentOutboxEvents(ctx, sentBefore)
+}
+
+// InTx runs fn in a transaction of the next repository, nesting the spans of its queries under the transaction span.
+func (r *repository) InTx(ctx context.Context, fn func(repo database.Repository) error) (err error) {
+	ctx, span := r.start(ctx, "InTx")
+	defer r.end(span, &err)
+	return r.next.InTx(ctx, func(repo database.Repository) error {
+		return fn(&repository{next: repo})
+	})
+}
+
+func (r *repository) GetOutboxEvent(ctx context.Context, id int64) (rv database.Outbox, err error) {
+	ctx, span := r.start(ctx, "GetOutboxEvent")
+	defer r.end(span, &err)
+	return r.next.GetOutboxEvent(ctx, id)
+}
+
+func (r *repository) ListTodoEvents(ctx context.Context, arg database.ListTodoEventsParams) (rv []database.Outbox, err error) {
+	ctx, span := r.start(ctx, "ListTodoEvents")
+	defer r.end(span, &err)
+	return r.next.ListTodoEvents(ctx, arg)
+}
+
+func (r *repository) GetLastTodoEventPosition(ctx context.Context, userID int32) (position int64, err error) {
+	ctx, span := r.start(ctx, "GetLastTodoEventPosition")
+	defer r.end(span, &err)
+	return r.next.GetLastTodoEventPosition(ctx, userID)
+}
@@ -0,0 +1,90 @@
+package tracing_test
+
+import (
+	"github.com/go-chi/chi"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"to-do-list-go/internal/database"
+	mock_repo "to-do-list-go/internal/database/mocks"
+	"to-do-list-go/internal/delivery/handlers"
+	"to-do-list-go/internal/delivery/middleware"
+	"to-do-list-go/internal/service"
+	"to-do-list-go/internal/tracing"
+	"to-do-list-go/internal/validator"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	repo := mock_repo.NewMockRepository(ctl)
+	repo.EXPECT().GetTodo(gomock.Any(), database.GetTodoParams{ID: 1, OwnerID: 1}).Return(database.Todo{
+		ID:        1,
+		Title:     "test",
+		DueDate:   time.Date(2024, 9, 5, 5, 40, 16, 0, time.UTC),
+		CreatedAt: time.Date(2024, 9, 1, 5, 40, 16, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 9, 1, 5, 40, 16, 0, time.UTC),
+		Status:    database.TodoStatusOpen,
+		Version:   1,
+	}, nil).Times(1)
+	repo.EXPECT().ListTodoTags(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+	repo.EXPECT().ListSubtaskProgress(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+	repo.EXPECT().ListTodoBlockers(gomock.Any(), []int32{1}).Return(nil, nil).Times(1)
+
+	s := service.NewService(tracing.NewRepository(repo), mock_repo.NewMockPool(ctl), service.AuthConfig{Secret: []byte("secret")}, service.TodoConfig{})
+	s.Todos = tracing.NewTodos(s.Todos)
+	v, _ := validator.InitValidator()
+	h := handlers.NewHandler(s, v)
+	r := chi.NewRouter()
+	r.Use(middleware.Tracing)
+	h.RegisterRoutes(r)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("Authorization", "Bearer "+accessToken(t, "secret", 1))
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 7)
+
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+		require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	}
+	require.Equal(t, []string{"Repository.GetTodo", "Repository.ListTodoTags", "Repository.ListSubtaskProgress", "Repository.ListTodoBlockers", "TodoService.GetTodo", "TodoHandler.getTodo", "GET /tasks/{id}"}, names)
+
+	require.Equal(t, "00f067aa0ba902b7", spans[6].Parent().SpanID().String())
+	for i := 0; i < 4; i++ {
+		require.Equal(t, spans[4].SpanContext().SpanID(), spans[i].Parent().SpanID())
+	}
+	for i := 4; i < 6; i++ {
+		require.Equal(t, spans[i+1].SpanContext().SpanID(), spans[i].Parent().SpanID())
+	}
+}
+
+func accessToken(t *testing.T, secret string, userID int) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.Itoa(userID),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
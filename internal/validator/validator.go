@@ -2,15 +2,27 @@ package validator
 
 import (
 	"github.com/go-playground/validator/v10"
+	"github.com/teambition/rrule-go"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// InitValidator initializes and returns a validator instance with custom RFC3339 validation.
+// InitValidator initializes and returns a validator instance with custom RFC3339, RRULE and byte length validation.
+// Validation errors name fields after their json or query tags, the way clients send them.
 func InitValidator() (*validator.Validate, error) {
 	v := validator.New()
+	v.RegisterTagNameFunc(fieldName)
 	if err := v.RegisterValidation("rfc3339", validateRFC3339); err != nil {
 		return nil, err
 	}
+	if err := v.RegisterValidation("rrule", validateRRule); err != nil {
+		return nil, err
+	}
+	if err := v.RegisterValidation("maxbytes", validateMaxBytes); err != nil {
+		return nil, err
+	}
 
 	return v, nil
 }
@@ -19,3 +31,41 @@ func validateRFC3339(fl validator.FieldLevel) bool {
 	_, err := time.Parse(time.RFC3339, fl.Field().String())
 	return err == nil
 }
+
+// validateRRule accepts a single RFC 5545 RRULE, with or without the "RRULE:" prefix.
+// Occurrences start at the todo due date, so DTSTART is rejected, and so are rules repeating more often than hourly.
+func validateRRule(fl validator.FieldLevel) bool {
+	rule := fl.Field().String()
+	if strings.ContainsAny(rule, "\r\n") {
+		return false
+	}
+
+	option, err := rrule.StrToROption(strings.ToUpper(rule))
+	if err != nil || !option.Dtstart.IsZero() || option.Freq > rrule.HOURLY || option.Interval < 0 || option.Count < 0 {
+		return false
+	}
+
+	_, err = rrule.NewRRule(*option)
+	return err == nil
+}
+
+// validateMaxBytes limits the length of a string in bytes rather than characters, e.g. for passwords hashed by bcrypt,
+// which rejects passwords longer than 72 bytes.
+func validateMaxBytes(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+
+	return len(fl.Field().String()) <= limit
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}